@@ -0,0 +1,86 @@
+package flagged
+
+import "math/bits"
+
+// swapBits swaps the bits at the indices i and j of f, without branching
+// on their values, by toggling both if they differ.
+func swapBits[T bitFlags](f *T, size int, i, j BitIndex) {
+	validateBitIndex(size, i)
+	validateBitIndex(size, j)
+	diff := (*f>>i ^ *f>>j) & 1
+	*f ^= diff<<i | diff<<j
+}
+
+// RotateLeft returns the value of f rotated left by n bits, so the bit at
+// index i moves to index (i+n) mod 8. To rotate right, use RotateRight,
+// or a negative n.
+func (f BitFlags8) RotateLeft(n int) BitFlags8 { return BitFlags8(bits.RotateLeft8(uint8(f), n)) }
+
+// RotateRight returns the value of f rotated right by n bits, so the bit
+// at index i moves to index (i-n) mod 8.
+func (f BitFlags8) RotateRight(n int) BitFlags8 { return BitFlags8(bits.RotateLeft8(uint8(f), -n)) }
+
+// RotateLeft returns the value of f rotated left by n bits, so the bit at
+// index i moves to index (i+n) mod 16. To rotate right, use RotateRight,
+// or a negative n.
+func (f BitFlags16) RotateLeft(n int) BitFlags16 { return BitFlags16(bits.RotateLeft16(uint16(f), n)) }
+
+// RotateRight returns the value of f rotated right by n bits, so the bit
+// at index i moves to index (i-n) mod 16.
+func (f BitFlags16) RotateRight(n int) BitFlags16 {
+	return BitFlags16(bits.RotateLeft16(uint16(f), -n))
+}
+
+// RotateLeft returns the value of f rotated left by n bits, so the bit at
+// index i moves to index (i+n) mod 32. To rotate right, use RotateRight,
+// or a negative n.
+func (f BitFlags32) RotateLeft(n int) BitFlags32 { return BitFlags32(bits.RotateLeft32(uint32(f), n)) }
+
+// RotateRight returns the value of f rotated right by n bits, so the bit
+// at index i moves to index (i-n) mod 32.
+func (f BitFlags32) RotateRight(n int) BitFlags32 {
+	return BitFlags32(bits.RotateLeft32(uint32(f), -n))
+}
+
+// RotateLeft returns the value of f rotated left by n bits, so the bit at
+// index i moves to index (i+n) mod 64. To rotate right, use RotateRight,
+// or a negative n.
+func (f BitFlags64) RotateLeft(n int) BitFlags64 { return BitFlags64(bits.RotateLeft64(uint64(f), n)) }
+
+// RotateRight returns the value of f rotated right by n bits, so the bit
+// at index i moves to index (i-n) mod 64.
+func (f BitFlags64) RotateRight(n int) BitFlags64 {
+	return BitFlags64(bits.RotateLeft64(uint64(f), -n))
+}
+
+// Reverse returns the value of f with its bits in reversed order, so the
+// bit at index i moves to index 7-i.
+func (f BitFlags8) Reverse() BitFlags8 { return BitFlags8(bits.Reverse8(uint8(f))) }
+
+// Reverse returns the value of f with its bits in reversed order, so the
+// bit at index i moves to index 15-i.
+func (f BitFlags16) Reverse() BitFlags16 { return BitFlags16(bits.Reverse16(uint16(f))) }
+
+// Reverse returns the value of f with its bits in reversed order, so the
+// bit at index i moves to index 31-i.
+func (f BitFlags32) Reverse() BitFlags32 { return BitFlags32(bits.Reverse32(uint32(f))) }
+
+// Reverse returns the value of f with its bits in reversed order, so the
+// bit at index i moves to index 63-i.
+func (f BitFlags64) Reverse() BitFlags64 { return BitFlags64(bits.Reverse64(uint64(f))) }
+
+// SwapBits swaps the values of the bits at the indices i and j.
+// It panics if i or j is out of the allowed range [0, 7].
+func (f *BitFlags8) SwapBits(i, j BitIndex) { swapBits(f, 8, i, j) }
+
+// SwapBits swaps the values of the bits at the indices i and j.
+// It panics if i or j is out of the allowed range [0, 15].
+func (f *BitFlags16) SwapBits(i, j BitIndex) { swapBits(f, 16, i, j) }
+
+// SwapBits swaps the values of the bits at the indices i and j.
+// It panics if i or j is out of the allowed range [0, 31].
+func (f *BitFlags32) SwapBits(i, j BitIndex) { swapBits(f, 32, i, j) }
+
+// SwapBits swaps the values of the bits at the indices i and j.
+// It panics if i or j is out of the allowed range [0, 63].
+func (f *BitFlags64) SwapBits(i, j BitIndex) { swapBits(f, 64, i, j) }
@@ -0,0 +1,208 @@
+package flagged
+
+import (
+	"fmt"
+	"testing"
+)
+
+func helperRunTestRotate[T bitFlags, TP interface {
+	ptrBitFlags[T]
+	RotateLeft(n int) T
+	RotateRight(n int) T
+}](t *testing.T) {
+	var (
+		zero   T
+		allset = ^zero
+		size   = TP(&zero).Size()
+	)
+	type testCase struct {
+		name    string
+		initial T
+		n       int
+	}
+	tests := []testCase{
+		{
+			name:    "zero",
+			initial: zero,
+			n:       3,
+		},
+		{
+			name:    "allset",
+			initial: allset,
+			n:       3,
+		},
+		{
+			name:    "partial",
+			initial: zero | 0b0101 | T(1)<<(size-1),
+			n:       3,
+		},
+		{
+			name:    "partial full rotation",
+			initial: zero | 0b0101 | T(1)<<(size-1),
+			n:       size,
+		},
+		{
+			name:    "partial negative",
+			initial: zero | 0b0101 | T(1)<<(size-1),
+			n:       -1,
+		},
+	}
+	t.Run(fmt.Sprintf("%T", zero), func(t *testing.T) {
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				var f TP = &tt.initial
+
+				// The bit at index i moves to index (i+n) mod size.
+				var wantLeft, wantRight T
+				for i := range size {
+					shifted := ((i+tt.n)%size + size) % size
+					TP(&wantLeft).SetTo(shifted, f.Is(i))
+					TP(&wantRight).SetTo(i, f.Is(shifted))
+				}
+				if got := f.RotateLeft(tt.n); got != wantLeft {
+					t.Errorf("RotateLeft(%v) = %v, want = %v", tt.n, TP(&got).String(), TP(&wantLeft).String())
+				}
+				if got := f.RotateRight(tt.n); got != wantRight {
+					t.Errorf("RotateRight(%v) = %v, want = %v", tt.n, TP(&got).String(), TP(&wantRight).String())
+				}
+			})
+		}
+	})
+}
+
+func TestBitFlags_Rotate(t *testing.T) {
+	helperRunTestRotate[BitFlags8](t)
+	helperRunTestRotate[BitFlags16](t)
+	helperRunTestRotate[BitFlags32](t)
+	helperRunTestRotate[BitFlags64](t)
+}
+
+func helperRunTestReverse[T bitFlags, TP interface {
+	ptrBitFlags[T]
+	Reverse() T
+}](t *testing.T) {
+	var (
+		zero   T
+		allset = ^zero
+		size   = TP(&zero).Size()
+	)
+	tests := []struct {
+		name    string
+		initial T
+		want    T
+	}{
+		{
+			name:    "zero",
+			initial: zero,
+			want:    zero,
+		},
+		{
+			name:    "allset",
+			initial: allset,
+			want:    allset,
+		},
+		{
+			name:    "partial",
+			initial: zero | 0b0110 | T(1)<<(size-1),
+			want:    zero | 1 | T(0b0110)<<(size-4),
+		},
+	}
+	t.Run(fmt.Sprintf("%T", zero), func(t *testing.T) {
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				var f TP = &tt.initial
+				if got := f.Reverse(); got != tt.want {
+					t.Errorf("Reverse() = %v, want = %v", TP(&got).String(), TP(&tt.want).String())
+				}
+			})
+		}
+	})
+}
+
+func TestBitFlags_Reverse(t *testing.T) {
+	helperRunTestReverse[BitFlags8](t)
+	helperRunTestReverse[BitFlags16](t)
+	helperRunTestReverse[BitFlags32](t)
+	helperRunTestReverse[BitFlags64](t)
+}
+
+func helperRunTestSwapBits[T bitFlags, TP interface {
+	ptrBitFlags[T]
+	SwapBits(i, j BitIndex)
+}](t *testing.T) {
+	var (
+		zero   T
+		allset = ^zero
+		size   = TP(&zero).Size()
+	)
+	tests := []struct {
+		name    string
+		initial T
+		i, j    BitIndex
+		want    T
+		panics  bool
+	}{
+		{
+			name:    "zero",
+			initial: zero,
+			i:       0,
+			j:       size - 1,
+			want:    zero,
+		},
+		{
+			name:    "allset",
+			initial: allset,
+			i:       0,
+			j:       size - 1,
+			want:    allset,
+		},
+		{
+			name:    "different bits",
+			initial: zero | 0b0010,
+			i:       1,
+			j:       size - 1,
+			want:    zero | T(1)<<(size-1),
+		},
+		{
+			name:    "same index",
+			initial: zero | 0b0010,
+			i:       1,
+			j:       1,
+			want:    zero | 0b0010,
+		},
+		{
+			name:    "out of range",
+			initial: zero | 0b0010,
+			i:       1,
+			j:       size,
+			want:    zero | 0b0010,
+			panics:  true,
+		},
+	}
+	t.Run(fmt.Sprintf("%T", zero), func(t *testing.T) {
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				skipUnvalidated(t, tt.panics)
+				f := tt.initial
+				func() {
+					defer func() {
+						if r := recover(); (r != nil) != tt.panics {
+							t.Errorf("SwapBits() panic = %v, want panic = %v", r, tt.panics)
+						}
+					}()
+					TP(&f).SwapBits(tt.i, tt.j)
+				}()
+				if f != tt.want {
+					t.Errorf("SwapBits(%v, %v) = %v, want = %v", tt.i, tt.j, TP(&f).String(), TP(&tt.want).String())
+				}
+			})
+		}
+	})
+}
+
+func TestBitFlags_SwapBits(t *testing.T) {
+	helperRunTestSwapBits[BitFlags8](t)
+	helperRunTestSwapBits[BitFlags16](t)
+	helperRunTestSwapBits[BitFlags32](t)
+	helperRunTestSwapBits[BitFlags64](t)
+}
@@ -0,0 +1,70 @@
+package flagged
+
+import "math/bits"
+
+// Builder composes a value of one of the BitFlags types by chaining
+// mutations, like:
+//
+//	f := flagged.Build8().Set(0).Set(3).Reset(1).Value()
+//
+// Its methods have value receivers, and return the updated [Builder], so
+// an intermediate [Builder] can be reused as the base of different values.
+// The zero value is a [Builder] of a value with no bits set.
+type Builder[T BitFlags8 | BitFlags16 | BitFlags32 | BitFlags64] struct {
+	f T
+}
+
+// Build8 returns a [Builder] of a [BitFlags8] value with no bits set.
+func Build8() Builder[BitFlags8] { return Builder[BitFlags8]{} }
+
+// Build16 returns a [Builder] of a [BitFlags16] value with no bits set.
+func Build16() Builder[BitFlags16] { return Builder[BitFlags16]{} }
+
+// Build32 returns a [Builder] of a [BitFlags32] value with no bits set.
+func Build32() Builder[BitFlags32] { return Builder[BitFlags32]{} }
+
+// Build64 returns a [Builder] of a [BitFlags64] value with no bits set.
+func Build64() Builder[BitFlags64] { return Builder[BitFlags64]{} }
+
+// BuildFrom returns a [Builder] of the value f.
+func BuildFrom[T BitFlags8 | BitFlags16 | BitFlags32 | BitFlags64](f T) Builder[T] {
+	return Builder[T]{f: f}
+}
+
+// Set sets the bit at index idx to true.
+// It panics if idx is out of the allowed range [0, Size-1].
+func (b Builder[T]) Set(idx BitIndex) Builder[T] {
+	set(&b.f, sizeOf[T](), idx, true)
+	return b
+}
+
+// Reset sets the bit at index idx to false.
+// It panics if idx is out of the allowed range [0, Size-1].
+func (b Builder[T]) Reset(idx BitIndex) Builder[T] {
+	set(&b.f, sizeOf[T](), idx, false)
+	return b
+}
+
+// SetTo sets the bit at index idx to new.
+// It panics if idx is out of the allowed range [0, Size-1].
+func (b Builder[T]) SetTo(idx BitIndex, new bool) Builder[T] {
+	set(&b.f, sizeOf[T](), idx, new)
+	return b
+}
+
+// Toggle toggles the bit at index idx.
+// It panics if idx is out of the allowed range [0, Size-1].
+func (b Builder[T]) Toggle(idx BitIndex) Builder[T] {
+	toggle(&b.f, sizeOf[T](), idx)
+	return b
+}
+
+// Value returns the composed value.
+func (b Builder[T]) Value() T {
+	return b.f
+}
+
+// sizeOf returns the number of bits of T.
+func sizeOf[T underlyingBitFlags]() int {
+	return bits.Len64(uint64(^T(0)))
+}
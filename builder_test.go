@@ -0,0 +1,50 @@
+package flagged
+
+import (
+	"fmt"
+	"testing"
+)
+
+func helperRunTestBuilder[T bitFlags, TP ptrBitFlags[T]](t *testing.T, build func() Builder[T]) {
+	var (
+		zero   T
+		allset = ^zero
+		size   = TP(&zero).Size()
+	)
+	t.Run(fmt.Sprintf("%T", zero), func(t *testing.T) {
+		b := build().Set(0).Set(3).Set(size-1).Reset(3).Toggle(1).SetTo(2, true)
+		want := zero | 0b0111 | T(1)<<(size-1)
+		if got := b.Value(); got != want {
+			t.Errorf("Value() = %v, want = %v", TP(&got).String(), TP(&want).String())
+		}
+
+		// The builder has value receivers, so b isn't changed.
+		if got := b.Reset(0).Value(); got != want&^1 {
+			t.Errorf("Reset(0).Value() = %v, want = %v", TP(&got).String(), TP(&want).String())
+		}
+		if got := b.Value(); got != want {
+			t.Errorf("Value() after Reset(0) = %v, want = %v", TP(&got).String(), TP(&want).String())
+		}
+
+		if got := BuildFrom(allset).Reset(0).Value(); got != allset&^1 {
+			t.Errorf("BuildFrom().Reset(0).Value() = %v", TP(&got).String())
+		}
+
+		if !validatesIndexes {
+			return
+		}
+		defer func() {
+			if r := recover(); r == nil {
+				t.Error("Set(Size) didn't panic")
+			}
+		}()
+		build().Set(size)
+	})
+}
+
+func TestBuilder(t *testing.T) {
+	helperRunTestBuilder(t, Build8)
+	helperRunTestBuilder(t, Build16)
+	helperRunTestBuilder(t, Build32)
+	helperRunTestBuilder(t, Build64)
+}
@@ -0,0 +1,302 @@
+package flagged
+
+// Op is a single operation on one bit, applied by the Apply method of the
+// BitFlags types, like [BitFlags8.Apply].
+// It's created by [SetOp], [ResetOp] or [ToggleOp].
+type Op struct {
+	kind opKind
+	idx  BitIndex
+}
+
+type opKind uint8
+
+const (
+	opSet opKind = iota
+	opReset
+	opToggle
+)
+
+// SetOp returns an [Op] setting the bit at index idx to true.
+func SetOp(idx BitIndex) Op { return Op{kind: opSet, idx: idx} }
+
+// ResetOp returns an [Op] setting the bit at index idx to false.
+func ResetOp(idx BitIndex) Op { return Op{kind: opReset, idx: idx} }
+
+// ToggleOp returns an [Op] toggling the bit at index idx.
+func ToggleOp(idx BitIndex) Op { return Op{kind: opToggle, idx: idx} }
+
+// apply validates the indices of all the ops first, then applies them in
+// order, without validating each of them again.
+func apply[T bitFlags](f *T, size int, ops ...Op) {
+	for _, op := range ops {
+		validateBitIndex(size, op.idx)
+	}
+	for _, op := range ops {
+		switch op.kind {
+		case opSet:
+			*f |= 1 << op.idx
+		case opReset:
+			*f &^= 1 << op.idx
+		case opToggle:
+			*f ^= 1 << op.idx
+		}
+	}
+}
+
+// indicesMask returns the mask with the bits at the indices idx set.
+// It validates all the indices before building the mask, so it panics
+// before any of them is applied.
+func indicesMask[T bitFlags](size int, idx ...BitIndex) (mask T) {
+	for _, bi := range idx {
+		validateBitIndex(size, bi)
+		mask |= 1 << bi
+	}
+	return mask
+}
+
+func setIndices[T bitFlags](f *T, size int, idx ...BitIndex) {
+	*f |= indicesMask[T](size, idx...)
+}
+
+func resetIndices[T bitFlags](f *T, size int, idx ...BitIndex) {
+	*f &^= indicesMask[T](size, idx...)
+}
+
+// setMask sets the bits of f set in mask, and returns the old value of
+// those bits.
+func setMask[T bitFlags](f *T, mask T) (old T) {
+	old = *f & mask
+	*f |= mask
+	return old
+}
+
+// resetMask resets the bits of f set in mask, and returns the old value
+// of those bits.
+func resetMask[T bitFlags](f *T, mask T) (old T) {
+	old = *f & mask
+	*f &^= mask
+	return old
+}
+
+// toggleMask toggles the bits of f set in mask, and returns the old value
+// of those bits.
+func toggleMask[T bitFlags](f *T, mask T) (old T) {
+	old = *f & mask
+	*f ^= mask
+	return old
+}
+
+// rangeMask returns the mask with the bits in the range [from, to) set.
+// It panics if the range isn't within [0, size].
+func rangeMask[T bitFlags](size int, from, to BitIndex) T {
+	validateBitRange(size, from, to)
+	if from == to {
+		return 0
+	}
+	return ^T(0) >> (size - (to - from)) << from
+}
+
+func validateBitRange(size int, from, to BitIndex) {
+	if 0 <= from && from <= to && to <= size {
+		return
+	}
+	validateBitRangeSlow(size, from, to)
+}
+
+func validateBitRangeSlow(size int, from, to BitIndex) {
+	panic(RangeError{From: from, To: to, Size: size})
+}
+
+// rangeErrorString returns the message of an invalid range [from, to).
+func rangeErrorString(size int, from, to BitIndex) string {
+	// print a helpful message without using fmt or strconv.
+	strLen := 34 // of "range [00:00) out of range [0..00]"
+	panicStr := make(stringBuilder, 0, strLen)
+	panicStr.WriteString("range ")
+
+	// only print the range if both ends are between 0 and nSmalls.
+	if 0 <= from && from < nSmalls && 0 <= to && to < nSmalls {
+		panicStr.WriteByte('[')
+		panicStr.WriteString(small(from))
+		panicStr.WriteByte(':')
+		panicStr.WriteString(small(to))
+		panicStr.WriteString(") ")
+	}
+
+	panicStr.WriteString("out of range [0..")
+	panicStr.WriteString(small(size))
+	panicStr.WriteString("]")
+	return panicStr.String()
+}
+
+func setRange[T bitFlags](f *T, size int, from, to BitIndex) {
+	*f |= rangeMask[T](size, from, to)
+}
+
+func resetRange[T bitFlags](f *T, size int, from, to BitIndex) {
+	*f &^= rangeMask[T](size, from, to)
+}
+
+func toggleRange[T bitFlags](f *T, size int, from, to BitIndex) {
+	*f ^= rangeMask[T](size, from, to)
+}
+
+// SetIndices sets the bits at the indices idx to true, in one pass.
+// It panics if any of idx is out of the allowed range [0, 7], without
+// changing any of the bits.
+func (f *BitFlags8) SetIndices(idx ...BitIndex) { setIndices(f, 8, idx...) }
+
+// ResetIndices sets the bits at the indices idx to false, in one pass.
+// It panics if any of idx is out of the allowed range [0, 7], without
+// changing any of the bits.
+func (f *BitFlags8) ResetIndices(idx ...BitIndex) { resetIndices(f, 8, idx...) }
+
+// SetMask sets the bits set in mask to true, in one operation, and
+// returns the old value of those bits, as the bits of mask set in f.
+func (f *BitFlags8) SetMask(mask BitFlags8) (old BitFlags8) { return setMask(f, mask) }
+
+// ResetMask sets the bits set in mask to false, in one operation, and
+// returns the old value of those bits, as the bits of mask set in f.
+func (f *BitFlags8) ResetMask(mask BitFlags8) (old BitFlags8) { return resetMask(f, mask) }
+
+// ToggleMask toggles the bits set in mask, in one operation, and
+// returns the old value of those bits, as the bits of mask set in f.
+func (f *BitFlags8) ToggleMask(mask BitFlags8) (old BitFlags8) { return toggleMask(f, mask) }
+
+// SetRange sets the bits in the range [from, to) to true.
+// It panics if the range isn't within [0, 8], or if from > to.
+func (f *BitFlags8) SetRange(from, to BitIndex) { setRange(f, 8, from, to) }
+
+// ResetRange sets the bits in the range [from, to) to false.
+// It panics if the range isn't within [0, 8], or if from > to.
+func (f *BitFlags8) ResetRange(from, to BitIndex) { resetRange(f, 8, from, to) }
+
+// ToggleRange toggles the bits in the range [from, to).
+// It panics if the range isn't within [0, 8], or if from > to.
+func (f *BitFlags8) ToggleRange(from, to BitIndex) { toggleRange(f, 8, from, to) }
+
+// Apply applies the ops in order, after validating all their indices in
+// one pass.
+// It panics if any of the indices is out of the allowed range [0, 7],
+// without applying any of the ops.
+func (f *BitFlags8) Apply(ops ...Op) { apply(f, 8, ops...) }
+
+// SetIndices sets the bits at the indices idx to true, in one pass.
+// It panics if any of idx is out of the allowed range [0, 15], without
+// changing any of the bits.
+func (f *BitFlags16) SetIndices(idx ...BitIndex) { setIndices(f, 16, idx...) }
+
+// ResetIndices sets the bits at the indices idx to false, in one pass.
+// It panics if any of idx is out of the allowed range [0, 15], without
+// changing any of the bits.
+func (f *BitFlags16) ResetIndices(idx ...BitIndex) { resetIndices(f, 16, idx...) }
+
+// SetMask sets the bits set in mask to true, in one operation, and
+// returns the old value of those bits, as the bits of mask set in f.
+func (f *BitFlags16) SetMask(mask BitFlags16) (old BitFlags16) { return setMask(f, mask) }
+
+// ResetMask sets the bits set in mask to false, in one operation, and
+// returns the old value of those bits, as the bits of mask set in f.
+func (f *BitFlags16) ResetMask(mask BitFlags16) (old BitFlags16) { return resetMask(f, mask) }
+
+// ToggleMask toggles the bits set in mask, in one operation, and
+// returns the old value of those bits, as the bits of mask set in f.
+func (f *BitFlags16) ToggleMask(mask BitFlags16) (old BitFlags16) { return toggleMask(f, mask) }
+
+// SetRange sets the bits in the range [from, to) to true.
+// It panics if the range isn't within [0, 16], or if from > to.
+func (f *BitFlags16) SetRange(from, to BitIndex) { setRange(f, 16, from, to) }
+
+// ResetRange sets the bits in the range [from, to) to false.
+// It panics if the range isn't within [0, 16], or if from > to.
+func (f *BitFlags16) ResetRange(from, to BitIndex) { resetRange(f, 16, from, to) }
+
+// ToggleRange toggles the bits in the range [from, to).
+// It panics if the range isn't within [0, 16], or if from > to.
+func (f *BitFlags16) ToggleRange(from, to BitIndex) { toggleRange(f, 16, from, to) }
+
+// Apply applies the ops in order, after validating all their indices in
+// one pass.
+// It panics if any of the indices is out of the allowed range [0, 15],
+// without applying any of the ops.
+func (f *BitFlags16) Apply(ops ...Op) { apply(f, 16, ops...) }
+
+// SetIndices sets the bits at the indices idx to true, in one pass.
+// It panics if any of idx is out of the allowed range [0, 31], without
+// changing any of the bits.
+func (f *BitFlags32) SetIndices(idx ...BitIndex) { setIndices(f, 32, idx...) }
+
+// ResetIndices sets the bits at the indices idx to false, in one pass.
+// It panics if any of idx is out of the allowed range [0, 31], without
+// changing any of the bits.
+func (f *BitFlags32) ResetIndices(idx ...BitIndex) { resetIndices(f, 32, idx...) }
+
+// SetMask sets the bits set in mask to true, in one operation, and
+// returns the old value of those bits, as the bits of mask set in f.
+func (f *BitFlags32) SetMask(mask BitFlags32) (old BitFlags32) { return setMask(f, mask) }
+
+// ResetMask sets the bits set in mask to false, in one operation, and
+// returns the old value of those bits, as the bits of mask set in f.
+func (f *BitFlags32) ResetMask(mask BitFlags32) (old BitFlags32) { return resetMask(f, mask) }
+
+// ToggleMask toggles the bits set in mask, in one operation, and
+// returns the old value of those bits, as the bits of mask set in f.
+func (f *BitFlags32) ToggleMask(mask BitFlags32) (old BitFlags32) { return toggleMask(f, mask) }
+
+// SetRange sets the bits in the range [from, to) to true.
+// It panics if the range isn't within [0, 32], or if from > to.
+func (f *BitFlags32) SetRange(from, to BitIndex) { setRange(f, 32, from, to) }
+
+// ResetRange sets the bits in the range [from, to) to false.
+// It panics if the range isn't within [0, 32], or if from > to.
+func (f *BitFlags32) ResetRange(from, to BitIndex) { resetRange(f, 32, from, to) }
+
+// ToggleRange toggles the bits in the range [from, to).
+// It panics if the range isn't within [0, 32], or if from > to.
+func (f *BitFlags32) ToggleRange(from, to BitIndex) { toggleRange(f, 32, from, to) }
+
+// Apply applies the ops in order, after validating all their indices in
+// one pass.
+// It panics if any of the indices is out of the allowed range [0, 31],
+// without applying any of the ops.
+func (f *BitFlags32) Apply(ops ...Op) { apply(f, 32, ops...) }
+
+// SetIndices sets the bits at the indices idx to true, in one pass.
+// It panics if any of idx is out of the allowed range [0, 63], without
+// changing any of the bits.
+func (f *BitFlags64) SetIndices(idx ...BitIndex) { setIndices(f, 64, idx...) }
+
+// ResetIndices sets the bits at the indices idx to false, in one pass.
+// It panics if any of idx is out of the allowed range [0, 63], without
+// changing any of the bits.
+func (f *BitFlags64) ResetIndices(idx ...BitIndex) { resetIndices(f, 64, idx...) }
+
+// SetMask sets the bits set in mask to true, in one operation, and
+// returns the old value of those bits, as the bits of mask set in f.
+func (f *BitFlags64) SetMask(mask BitFlags64) (old BitFlags64) { return setMask(f, mask) }
+
+// ResetMask sets the bits set in mask to false, in one operation, and
+// returns the old value of those bits, as the bits of mask set in f.
+func (f *BitFlags64) ResetMask(mask BitFlags64) (old BitFlags64) { return resetMask(f, mask) }
+
+// ToggleMask toggles the bits set in mask, in one operation, and
+// returns the old value of those bits, as the bits of mask set in f.
+func (f *BitFlags64) ToggleMask(mask BitFlags64) (old BitFlags64) { return toggleMask(f, mask) }
+
+// SetRange sets the bits in the range [from, to) to true.
+// It panics if the range isn't within [0, 64], or if from > to.
+func (f *BitFlags64) SetRange(from, to BitIndex) { setRange(f, 64, from, to) }
+
+// ResetRange sets the bits in the range [from, to) to false.
+// It panics if the range isn't within [0, 64], or if from > to.
+func (f *BitFlags64) ResetRange(from, to BitIndex) { resetRange(f, 64, from, to) }
+
+// ToggleRange toggles the bits in the range [from, to).
+// It panics if the range isn't within [0, 64], or if from > to.
+func (f *BitFlags64) ToggleRange(from, to BitIndex) { toggleRange(f, 64, from, to) }
+
+// Apply applies the ops in order, after validating all their indices in
+// one pass.
+// It panics if any of the indices is out of the allowed range [0, 63],
+// without applying any of the ops.
+func (f *BitFlags64) Apply(ops ...Op) { apply(f, 64, ops...) }
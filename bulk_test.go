@@ -0,0 +1,403 @@
+package flagged
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"testing"
+)
+
+func helperRunTestSetIndices[T bitFlags, TP interface {
+	ptrBitFlags[T]
+	SetIndices(idx ...BitIndex)
+	ResetIndices(idx ...BitIndex)
+}](t *testing.T) {
+	var (
+		zero   T
+		allset = ^zero
+		size   = TP(&zero).Size()
+	)
+	type testCase struct {
+		name      string
+		initial   T
+		idx       []BitIndex
+		wantSet   T
+		wantReset T
+		panics    bool
+	}
+	tests := []testCase{
+		{
+			name:      "no indices",
+			initial:   zero | 0b101,
+			idx:       nil,
+			wantSet:   zero | 0b101,
+			wantReset: zero | 0b101,
+		},
+		{
+			name:      "some indices",
+			initial:   zero | 0b101,
+			idx:       []BitIndex{1, 2, size - 1},
+			wantSet:   zero | 0b111 | T(1)<<(size-1),
+			wantReset: zero | 0b001,
+		},
+		{
+			name:      "all indices",
+			initial:   zero,
+			idx:       slices.Collect(TP(&allset).SetBits()),
+			wantSet:   allset,
+			wantReset: zero,
+		},
+		{
+			name:      "out of range",
+			initial:   zero | 0b101,
+			idx:       []BitIndex{1, size},
+			wantSet:   zero | 0b101,
+			wantReset: zero | 0b101,
+			panics:    true,
+		},
+	}
+	t.Run(fmt.Sprintf("%T", zero), func(t *testing.T) {
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				skipUnvalidated(t, tt.panics)
+				for _, op := range []struct {
+					name string
+					fn   func(f TP, idx ...BitIndex)
+					want T
+				}{
+					{"SetIndices", TP.SetIndices, tt.wantSet},
+					{"ResetIndices", TP.ResetIndices, tt.wantReset},
+				} {
+					f := tt.initial
+					func() {
+						defer func() {
+							if r := recover(); (r != nil) != tt.panics {
+								t.Errorf("%s() panic = %v, want panic = %v", op.name, r, tt.panics)
+							}
+						}()
+						op.fn(&f, tt.idx...)
+					}()
+					if f != op.want {
+						t.Errorf("%s() = %v, want = %v", op.name, TP(&f).String(), TP(&op.want).String())
+					}
+				}
+			})
+		}
+	})
+}
+
+func TestBitFlags_SetIndices(t *testing.T) {
+	helperRunTestSetIndices[BitFlags8](t)
+	helperRunTestSetIndices[BitFlags16](t)
+	helperRunTestSetIndices[BitFlags32](t)
+	helperRunTestSetIndices[BitFlags64](t)
+}
+
+func helperRunTestSetMask[T bitFlags, TP interface {
+	ptrBitFlags[T]
+	SetMask(mask T) (old T)
+	ResetMask(mask T) (old T)
+	ToggleMask(mask T) (old T)
+}](t *testing.T) {
+	var (
+		zero   T
+		allset = ^zero
+		size   = TP(&zero).Size()
+	)
+	type testCase struct {
+		name       string
+		initial    T
+		mask       T
+		wantOld    T
+		wantSet    T
+		wantReset  T
+		wantToggle T
+	}
+	tests := []testCase{
+		{
+			name:       "zero mask",
+			initial:    zero | 0b0101,
+			mask:       zero,
+			wantOld:    zero,
+			wantSet:    zero | 0b0101,
+			wantReset:  zero | 0b0101,
+			wantToggle: zero | 0b0101,
+		},
+		{
+			name:       "allset mask",
+			initial:    zero | 0b0101,
+			mask:       allset,
+			wantOld:    zero | 0b0101,
+			wantSet:    allset,
+			wantReset:  zero,
+			wantToggle: allset &^ 0b0101,
+		},
+		{
+			name:       "partial mask",
+			initial:    zero | 0b0101 | T(1)<<(size-1),
+			mask:       zero | 0b0011 | T(1)<<(size-1),
+			wantOld:    zero | 0b0001 | T(1)<<(size-1),
+			wantSet:    zero | 0b0111 | T(1)<<(size-1),
+			wantReset:  zero | 0b0100,
+			wantToggle: zero | 0b0110,
+		},
+	}
+	t.Run(fmt.Sprintf("%T", zero), func(t *testing.T) {
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				for _, op := range []struct {
+					name string
+					fn   func(f TP, mask T) T
+					want T
+				}{
+					{"SetMask", TP.SetMask, tt.wantSet},
+					{"ResetMask", TP.ResetMask, tt.wantReset},
+					{"ToggleMask", TP.ToggleMask, tt.wantToggle},
+				} {
+					f := tt.initial
+					if old := op.fn(&f, tt.mask); old != tt.wantOld {
+						t.Errorf("%s() old = %v, want = %v", op.name, TP(&old).String(), TP(&tt.wantOld).String())
+					}
+					if f != op.want {
+						t.Errorf("%s() = %v, want = %v", op.name, TP(&f).String(), TP(&op.want).String())
+					}
+				}
+			})
+		}
+	})
+}
+
+func TestBitFlags_SetMask(t *testing.T) {
+	helperRunTestSetMask[BitFlags8](t)
+	helperRunTestSetMask[BitFlags16](t)
+	helperRunTestSetMask[BitFlags32](t)
+	helperRunTestSetMask[BitFlags64](t)
+}
+
+func helperRunTestSetRange[T bitFlags, TP interface {
+	ptrBitFlags[T]
+	SetRange(from, to BitIndex)
+	ResetRange(from, to BitIndex)
+	ToggleRange(from, to BitIndex)
+}](t *testing.T) {
+	var (
+		zero   T
+		allset = ^zero
+		size   = TP(&zero).Size()
+	)
+	type testCase struct {
+		name     string
+		initial  T
+		from, to BitIndex
+		panics   bool
+	}
+	tests := []testCase{
+		{
+			name:    "empty range",
+			initial: zero | 0b0101,
+			from:    2,
+			to:      2,
+		},
+		{
+			name:    "full range",
+			initial: zero | 0b0101,
+			from:    0,
+			to:      size,
+		},
+		{
+			name:    "partial range",
+			initial: zero | 0b0101 | T(1)<<(size-1),
+			from:    1,
+			to:      size - 1,
+		},
+		{
+			name:    "range end",
+			initial: allset,
+			from:    size - 3,
+			to:      size,
+		},
+		{
+			name:    "out of range",
+			initial: zero | 0b0101,
+			from:    1,
+			to:      size + 1,
+			panics:  true,
+		},
+		{
+			name:    "reversed range",
+			initial: zero | 0b0101,
+			from:    3,
+			to:      2,
+			panics:  true,
+		},
+	}
+	t.Run(fmt.Sprintf("%T", zero), func(t *testing.T) {
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				for _, op := range []struct {
+					name  string
+					fn    func(f TP, from, to BitIndex)
+					bitOp func(old bool) bool
+				}{
+					{"SetRange", TP.SetRange, func(bool) bool { return true }},
+					{"ResetRange", TP.ResetRange, func(bool) bool { return false }},
+					{"ToggleRange", TP.ToggleRange, func(old bool) bool { return !old }},
+				} {
+					// The bits in the range are changed with bitOp, unless
+					// it panics, and the other bits are kept.
+					want := tt.initial
+					if !tt.panics {
+						for i := tt.from; i < tt.to; i++ {
+							TP(&want).SetTo(i, op.bitOp(TP(&want).Is(i)))
+						}
+					}
+
+					f := tt.initial
+					func() {
+						defer func() {
+							if r := recover(); (r != nil) != tt.panics {
+								t.Errorf("%s() panic = %v, want panic = %v", op.name, r, tt.panics)
+							}
+						}()
+						op.fn(&f, tt.from, tt.to)
+					}()
+					if f != want {
+						t.Errorf("%s() = %v, want = %v", op.name, TP(&f).String(), TP(&want).String())
+					}
+				}
+			})
+		}
+	})
+}
+
+func TestBitFlags_SetRange(t *testing.T) {
+	helperRunTestSetRange[BitFlags8](t)
+	helperRunTestSetRange[BitFlags16](t)
+	helperRunTestSetRange[BitFlags32](t)
+	helperRunTestSetRange[BitFlags64](t)
+}
+
+func Test_validateBitRange_panic(t *testing.T) {
+	tests := []struct {
+		name     string
+		size     int
+		from, to BitIndex
+		panicV   any
+		wantMsg  string
+	}{
+		{
+			name:   "no panic",
+			size:   8,
+			from:   0,
+			to:     8,
+			panicV: nil,
+		},
+		{
+			name:    "positive panic - small range",
+			size:    16,
+			from:    3,
+			to:      17,
+			panicV:  RangeError{From: 3, To: 17, Size: 16},
+			wantMsg: "range [3:17) out of range [0..16]",
+		},
+		{
+			name:    "reversed panic - small range",
+			size:    32,
+			from:    5,
+			to:      4,
+			panicV:  RangeError{From: 5, To: 4, Size: 32},
+			wantMsg: "range [5:4) out of range [0..32]",
+		},
+		{
+			name:    "negative panic",
+			size:    64,
+			from:    -1,
+			to:      4,
+			panicV:  RangeError{From: -1, To: 4, Size: 64},
+			wantMsg: "range out of range [0..64]",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer func() {
+				r := recover()
+				if r != tt.panicV {
+					t.Errorf("validateBitRange() panic = %v, want = %v", r, tt.panicV)
+				}
+				if r == nil {
+					return
+				}
+				var rangeErr RangeError
+				if err, _ := r.(error); !errors.As(err, &rangeErr) {
+					t.Errorf("validateBitRange() panic = %v, want a RangeError", r)
+				} else if msg := rangeErr.Error(); msg != tt.wantMsg {
+					t.Errorf("validateBitRange() panic message = %q, want = %q", msg, tt.wantMsg)
+				}
+			}()
+			validateBitRange(tt.size, tt.from, tt.to)
+		})
+	}
+}
+
+func helperRunTestApply[T bitFlags, TP interface {
+	ptrBitFlags[T]
+	Apply(ops ...Op)
+}](t *testing.T) {
+	var (
+		zero T
+		size = TP(&zero).Size()
+	)
+	tests := []struct {
+		name    string
+		initial T
+		ops     []Op
+		want    T
+		panics  bool
+	}{
+		{
+			name:    "no ops",
+			initial: zero | 0b0101,
+			ops:     nil,
+			want:    zero | 0b0101,
+		},
+		{
+			name:    "ops in order",
+			initial: zero | 0b0101,
+			ops:     []Op{SetOp(1), ResetOp(0), ToggleOp(2), ToggleOp(size - 1), SetOp(0), ResetOp(0)},
+			want:    zero | 0b0010 | T(1)<<(size-1),
+		},
+		{
+			name:    "out of range",
+			initial: zero | 0b0101,
+			ops:     []Op{SetOp(1), ResetOp(size)},
+			want:    zero | 0b0101,
+			panics:  true,
+		},
+	}
+	t.Run(fmt.Sprintf("%T", zero), func(t *testing.T) {
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				skipUnvalidated(t, tt.panics)
+				f := tt.initial
+				func() {
+					defer func() {
+						if r := recover(); (r != nil) != tt.panics {
+							t.Errorf("Apply() panic = %v, want panic = %v", r, tt.panics)
+						}
+					}()
+					TP(&f).Apply(tt.ops...)
+				}()
+				if f != tt.want {
+					t.Errorf("Apply() = %v, want = %v", TP(&f).String(), TP(&tt.want).String())
+				}
+			})
+		}
+	})
+}
+
+func TestBitFlags_Apply(t *testing.T) {
+	helperRunTestApply[BitFlags8](t)
+	helperRunTestApply[BitFlags16](t)
+	helperRunTestApply[BitFlags32](t)
+	helperRunTestApply[BitFlags64](t)
+}
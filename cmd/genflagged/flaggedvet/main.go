@@ -0,0 +1,28 @@
+// flaggedvet runs the analyzers of the genflagged module, checking the usage
+// of the github.com/asmsh/flagged bit flags types, and the ones generated by
+// genflagged.
+//
+// It's meant to be used as a vet tool:
+//
+//	go install github.com/asmsh/flagged/cmd/genflagged/flaggedvet@latest
+//	go vet -vettool=$(which flaggedvet) ./...
+//
+// The analyzers are:
+//   - bitindex: reports constant bit indexes out of the range of the flags
+//     they are passed to.
+//   - stale: reports generated types which are out of date with their source
+//     struct types, and need to be regenerated.
+package main
+
+import (
+	"github.com/asmsh/flagged/cmd/genflagged/passes/bitindex"
+	"github.com/asmsh/flagged/cmd/genflagged/passes/stale"
+	"golang.org/x/tools/go/analysis/unitchecker"
+)
+
+func main() {
+	unitchecker.Main(
+		bitindex.Analyzer,
+		stale.Analyzer,
+	)
+}
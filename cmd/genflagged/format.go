@@ -0,0 +1,17 @@
+package main
+
+import gofumptformat "mvdan.cc/gofumpt/format"
+
+// Supported values of the -format flag.
+const (
+	formatGofmt   = "gofmt"
+	formatGofumpt = "gofumpt"
+	formatNone    = "none"
+)
+
+// gofumpt formats src with the gofumpt library, which is stricter than
+// gofmt, at the version pinned in go.mod, so the output doesn't depend on
+// the tools installed on the machine.
+func gofumpt(src []byte) ([]byte, error) {
+	return gofumptformat.Source(src, gofumptformat.Options{})
+}
@@ -17,14 +17,59 @@
 //   - Set<field name>To: sets the field to the new value, and returns the old value.
 //   - Toggle<field name>: toggles the field's value, and returns the new value.
 //
-// In addition to 4 other methods for the whole generated type:
+// Each bool array field, like 'Shards [4]bool', is expanded into a flag per
+// element, named after the field followed by the index of the element, like
+// Shards0 to Shards3, with the same 5 methods generated for each, like
+// IsShards0.
+//
+// In addition to 18 other methods for the whole generated type:
 //   - BitFlags: returns a [github.com/asmsh/flagged.BitFlags] value,
 //     wrapping the receiver value, and exposing a wider range of methods.
-//   - Clone: returns a copy of the receiver value.
+//   - Clone: returns a copy of the receiver value, and unlike the rest of
+//     the methods, it has a value receiver, so it can be used on
+//     non-addressable values too.
+//   - CloneBitFlags: returns a copy of the receiver value, as a pointer
+//     wrapped in a [github.com/asmsh/flagged.BitFlags] value, with a value
+//     receiver, like Clone, but not with -raw.
+//   - CopyFrom: overrides the receiver value with a copy of another value.
 //   - TypedFlags: returns a copy of the receiver value as a value of the
 //     original type that was used to generate the new flags type.
 //   - SetTypedFlags: takes a value of the original type and overrides the
 //     receiver value based on its fields.
+//   - ToMap: returns the receiver value as a map[string]bool, keyed by
+//     the flag names.
+//   - FromMap: takes a map[string]bool, keyed by the flag names, and
+//     overrides the flags included in it.
+//   - IsNamed: reports whether the flag with the given name is set.
+//   - SetNamedTo: sets the flag with the given name to the new value.
+//   - Name: returns the name of the flag at the given bit index.
+//   - IndexOf: returns the bit index of the flag with the given name.
+//   - AllDefinedSet: reports whether all the flags are set, ignoring the
+//     unused bits of the generated type, unlike BitFlags().AllSet().
+//   - AnyDefinedSet: reports whether any of the flags is set, ignoring the
+//     unused bits of the generated type, unlike BitFlags().AnySet().
+//   - Equal: reports whether the receiver and another value have the same
+//     flags set, ignoring the unused bits of the generated type.
+//   - Hash: returns a hash of the flags, ignoring the unused bits of the
+//     generated type, so it's consistent with Equal.
+//   - AppendString: appends the names of the set flags, separated by '|',
+//     to a byte slice, without allocating, for hot logging paths.
+//   - GoString: returns the flags as a Go expression of the generated type,
+//     with a comment of the names of the set flags, for the %#v verb.
+//
+// When not in raw mode, the generated type also implements the
+// [github.com/asmsh/flagged.BitFlags] interface directly, by forwarding its
+// methods to the value returned by the BitFlags method, so a pointer to it
+// can be used wherever a [github.com/asmsh/flagged.BitFlags] is expected.
+//
+// Along with a constant and 3 package-level functions, named after the
+// original type T:
+//   - TNumFlags: the number of flags, which can be less than the bit width
+//     of the generated type.
+//   - TFlagNames: returns the names of all the flags, ordered by bit index.
+//   - TFlagIndexes: returns the bit indexes of all the flags, in order.
+//   - TAllFlags: returns an iterator over the bit indexes of all the flags,
+//     in order, skipping the unused bits of the generated type.
 //
 // For example, given this type:
 //
@@ -45,9 +90,23 @@
 //	type PermissionsFlags flagged.BitFlags8
 //
 //	func (f *PermissionsFlags) BitFlags() flagged.BitFlags
-//	func (f *PermissionsFlags) Clone() PermissionsFlags
+//	func (f PermissionsFlags) Clone() PermissionsFlags
+//	func (f PermissionsFlags) CloneBitFlags() flagged.BitFlags
+//	func (f *PermissionsFlags) CopyFrom(*PermissionsFlags)
 //	func (f *PermissionsFlags) TypedFlags() Permissions
 //	func (f *PermissionsFlags) SetTypedFlags(Permissions)
+//	func (f *PermissionsFlags) ToMap() map[string]bool
+//	func (f *PermissionsFlags) FromMap(map[string]bool) error
+//	func (f *PermissionsFlags) IsNamed(string) (bool, error)
+//	func (f *PermissionsFlags) SetNamedTo(string, bool) error
+//	func (f *PermissionsFlags) Name(flagged.BitIndex) string
+//	func (f *PermissionsFlags) IndexOf(string) (flagged.BitIndex, bool)
+//	func (f *PermissionsFlags) AllDefinedSet() bool
+//	func (f *PermissionsFlags) AnyDefinedSet() bool
+//	func (f *PermissionsFlags) Equal(PermissionsFlags) bool
+//	func (f *PermissionsFlags) Hash() uint64
+//	func (f *PermissionsFlags) AppendString([]byte) []byte
+//	func (f PermissionsFlags) GoString() string
 //	func (f *PermissionsFlags) IsRead() bool
 //	func (f *PermissionsFlags) SetRead() bool
 //	func (f *PermissionsFlags) ResetRead() bool
@@ -60,6 +119,10 @@
 //	func (f *PermissionsFlags) SetExec() bool
 //	...
 //
+//	func PermissionsFlagNames() []string
+//	func PermissionsFlagIndexes() []flagged.BitIndex
+//	func PermissionsAllFlags() iter.Seq[flagged.BitIndex]
+//
 // This enables memory-efficient and type-safe storage of bool configurations,
 // replacing big structs with a compact uint-based types, without sacrificing
 // readability or performance.
@@ -109,22 +172,259 @@
 //   - 17 to 32 bool fields: the underlying type is uint32.
 //   - 33 to 64 bool fields: the underlying type is uint64.
 //
+// The -size flag fails the generation of types with more bool fields than the
+// bit width, unless it's in the form auto-min:<size>, like -size=auto-min:16,
+// which makes it the minimum bit width, so the next bit width that's enough is
+// chosen for such types, with a warning, and shared go:generate lines keep
+// working as they grow. Just -size=auto-min is the same as -size=auto-min:8.
+//
 // The -trimprefix and -trimsuffix flags specifies a prefix and suffix to be
 // removed from each bool field's name, in each source type in the -type flag,
 // before it's used to generated the different methods.
 //
+// The -getterName, -setterName, -resetterName, -setterToName and -togglerName
+// flags specify the names of the 5 methods generated for each bool field,
+// instead of Is<field name>, Set<field name>, Reset<field name>,
+// Set<field name>To and Toggle<field name>, respectively, so they can match
+// the conventions of an existing codebase.
+// Each is a text/template, executed with the flag name as {{.Flag}}, and the
+// field name as {{.Field}}, like:
+//
+//	genflagged -type=Permissions -getterName=Has{{.Flag}} -setterName=Enable{{.Flag}} -resetterName=Disable{{.Flag}}
+//
+// The names must be valid identifiers, which don't collide with each other,
+// nor with the other methods of the generated type, which depend on the mode
+// and the enabled features, so a field named All collides with the SetAll
+// method of [github.com/asmsh/flagged.BitFlags], but not with -raw.
+//
 // The -raw flag generates self-contained code that doesn't import the
 // github.com/asmsh/flagged package. The generated type is defined directly
 // as the matching uint type (uint8, uint16, uint32 or uint64) instead of a
 // flagged.BitFlags type, and the BitFlags method is omitted, since it returns
-// a flagged.BitFlags value. All other methods are generated as usual.
+// a flagged.BitFlags value, along with the methods implementing the
+// flagged.BitFlags interface. All other methods are generated as usual.
+//
+// The -valueReceivers flag generates the read-only methods, like Is<field name>,
+// TypedFlags, ToMap, Equal and String, on value receivers, instead of pointer
+// receivers, keeping the methods modifying the value on pointer receivers,
+// so the read-only methods can be used on non-addressable values too, like
+// map entries and function results.
 //
 // The -tests flag additionally generates a companion _test.go file next to
 // the output, containing table-driven tests that exercise the generated
 // methods for each type (the per-flag Is/Set/Reset/SetTo/Toggle accessors, the
-// TypedFlags/SetTypedFlags round-trip, and Clone). The generated tests use
-// only the standard library and the generated methods, so they compile in
-// both normal and -raw mode.
+// TypedFlags/SetTypedFlags and ToMap/FromMap round-trips, the named accessors,
+// and the rest of the type-level methods and package-level functions),
+// including the ones added by the other flags, except -prometheus and -proto.
+// The generated tests use only the standard library and the generated
+// methods, so they compile in both normal and -raw mode.
+//
+// The -benchmarks flag additionally generates benchmarks in the companion
+// _test.go file, for each generated type T, with source type S, named
+// BenchmarkT, which benchmark the generated methods, along with the
+// equivalent operations on S values, as a baseline, so the generated type
+// can be compared to the struct it replaces.
+//
+// The -fuzz flag additionally generates a fuzz target in the companion
+// _test.go file, for each generated type T, named FuzzT, which fuzzes the
+// round-trips between T values and the other representations of their flags
+// (TypedFlags/SetTypedFlags, ToMap/FromMap and IsNamed), along with the
+// consistency of Equal and Hash, starting from arbitrary values.
+//
+// The -examples flag additionally generates a companion _example_test.go file
+// next to the output, containing runnable examples, with verified output, of
+// the generated methods for each type, so they are shown in the
+// documentation of the package.
+//
+// The -mock flag additionally generates a TMock type in the companion _test.go
+// file, for each generated type T, which implements the generated interface
+// and records the calls to its methods, so code depending on the interface
+// can be tested against it.
+// It embeds a T value, which all the calls are forwarded to after being
+// recorded, so the mock behaves like a T value.
+//
+// The -with flag additionally generates a With<field name> method for each
+// bool field, with a value receiver, which returns a copy of the receiver
+// value with the field set to the new value, leaving the receiver unchanged.
+// It's useful for treating the generated type as an immutable value.
+//
+// The -options flag additionally generates a functional-options constructor
+// for each type T, with its generated type O:
+//
+//	type TOption func(*O)
+//
+//	func NewO(opts ...TOption) O
+//
+// Along with a With<field name> and a Without<field name> package-level
+// function for each bool field, returning a TOption that sets or resets the
+// field, respectively.
+// Since the option functions are named after the fields only, generating
+// options for multiple types with the same field names in the same package
+// is an error.
+//
+// The -atomic flag additionally generates a TAtomicBitFlags type for each
+// type T, which holds a generated type value that can be accessed and modified
+// by multiple goroutines concurrently, using the sync/atomic package.
+// It has the same 5 methods per bool field as the generated type, along with
+// Load and Store methods for the whole value.
+// Since it's based on atomic.Uint32 or atomic.Uint64, it has a Go 1.23
+// minimum requirement.
+//
+// The -safe flag additionally generates a TSafeBitFlags type for each type T,
+// which holds a generated type value guarded by a mutex, so it can be used by
+// multiple goroutines concurrently.
+// It has the same 5 methods per bool field as the generated type, along with
+// Load and Store methods for the whole value, and an Update method, which
+// calls a function with the value while holding the lock, so multiple flags
+// can be changed together, without other goroutines observing the
+// intermediate values.
+//
+// The -slice flag additionally generates a OSlice type for each generated
+// type O, defined as []O, for code storing the flags of many records in a
+// single slice. It has a Count<field name> method for each bool field,
+// returning the number of elements with the field set, along with CountMask
+// and FilterMask methods, returning the number of elements, and a new slice
+// of the elements, with all the flags in a mask set, respectively.
+//
+// The -context flag additionally generates a pair of package-level functions
+// for each type T, with its generated type O, for passing the flags through
+// a [context.Context]:
+//
+//	func ContextWithT(ctx context.Context, f O) context.Context
+//
+//	func TFromContext(ctx context.Context) (f O, ok bool)
+//
+// The context key is of an unexported type, so it can't collide with keys
+// defined in other packages.
+//
+// Rules between the bool fields of a type T can be declared in their struct
+// tags, with the 'flagged' key, as a comma-separated list of:
+//   - requires=<field name>: the field must be set when this field is set.
+//   - excludes=<field name>: the field mustn't be set when this field is set.
+//   - group=<group name>: at most one of the fields in the group can be set.
+//
+// For example:
+//
+//	type Permissions struct {
+//		Read  bool `flagged:"group=access"`
+//		Write bool `flagged:"group=access,requires=Audit"`
+//		Audit bool
+//	}
+//
+// The requires and excludes keys can be repeated, to reference multiple fields.
+// The elements of bool array fields are referenced by their index, like
+// requires=Shards[0], and the rules of a bool array field apply to each of
+// its elements, so group=shard allows at most one of them to be set.
+// When any rules are declared, a Validate method is generated for the type,
+// which returns all the rules violated by the flags value, joined as a single
+// error, or nil if there's none.
+//
+// The -convert flag additionally generates an AsBitFlags method on each
+// source type T, with a value receiver, returning the receiver converted to
+// its generated type, so a T value can be converted without going through
+// SetTypedFlags on a zero generated type value.
+//
+// The -crossConvert flag additionally generates conversions between each
+// pair of types generated into the same file, which share at least one flag
+// name. For each such types T and U, with generated types O and P, it
+// generates:
+//
+//	func (f *O) ToP() P
+//	func (f *P) ToO() O
+//
+// Each copying the values of the shared flags, leaving the rest unset.
+// It's useful when migrating between different versions of a type.
+//
+// The -hook flag accepts a command, split into its arguments by spaces, which
+// is run for each generated type, in the directory of the output file, after
+// it's written, with the model of the type as JSON on its standard input, so
+// it can write additional files, like company-specific adapters, without
+// changing the templates of genflagged.
+// The model has the names of the package, the source and the generated type,
+// and the output file, the size of the generated type, whether it's in raw
+// mode, and, for each flag, ordered by its bit index, its field, its name, its
+// bit index, its doc, the names of its methods, and its rules, like:
+//
+//	{
+//		"package": "permissions",
+//		"sourceType": "Permissions",
+//		"outType": "PermissionsFlags",
+//		"size": 8,
+//		"raw": false,
+//		"outFile": "permissions_flagged.go",
+//		"flags": [
+//			{
+//				"field": "Read",
+//				"flag": "Read",
+//				"index": 0,
+//				"getter": "IsRead",
+//				"setter": "SetRead",
+//				"resetter": "ResetRead",
+//				"setterTo": "SetReadTo",
+//				"toggler": "ToggleRead"
+//			}
+//		]
+//	}
+//
+// The generator fails if the command fails.
+//
+// The -jsonSchema flag additionally generates a T+JSONSchema string constant,
+// holding a JSON Schema, which OpenAPI 3.1 schema objects are compatible with,
+// of the object form of the flags, as returned by the ToMap method, and
+// accepted by the FromMap method, which has a boolean property for each flag,
+// keyed by its name, so API definitions can reference it.
+//
+// The -guard flag additionally generates a conversion of a zero value of the
+// struct type T was defined as, when generated, to T, which fails to build
+// when the fields of T change, like when a bool field is added or moved, so
+// forgetting to regenerate is caught at compile time, rather than by the
+// stale analyzer, or at runtime.
+// As struct conversions require identical fields, changing a field of T
+// that's not a bool field requires regenerating too.
+//
+// The -lockFile flag accepts a file path, which records the layouts of the
+// flags of each generated type, that is, the names of the flags ordered by
+// their bit indexes, each with a version, starting from 1.
+// When the layout of a type changes, like when a flag is added, removed or
+// moved, a new version is added to the file, which should be committed along
+// with the generated code.
+// For each type, a MarshalBinary method is generated, encoding the flags as
+// the version of the current layout, in a single byte, followed by their bits,
+// along with an UnmarshalBinary method, decoding the flags encoded with any of
+// the recorded layouts, mapping the flags of older layouts by their names, so
+// the stored values remain decodable after the flags change.
+//
+// The -format flag controls how the generated Go files are formatted, which is
+// gofmt by default.
+// When set to gofumpt, the output is formatted with gofmt, then with the
+// stricter gofumpt, through its library, at the version required by this
+// module, so it's not rewritten by the CI formatters of repositories
+// enforcing it.
+// When set to none, the output is written as generated, without formatting.
+//
+// The -docOut flag accepts a file path, which a markdown document is written
+// to, containing a table of the flags of each generated type, with their
+// names, fields, bit indexes, masks, and descriptions, taken from the doc
+// comments of the fields, or their line comments, so the documentation of
+// the flags is generated from the same source as the code.
+// Like the -outFile flag, it can't be used when the types are found in
+// multiple packages.
+//
+// The -prometheus flag additionally generates a Collector method for each
+// type, returning a [github.com/prometheus/client_golang/prometheus.Collector]
+// that exports one gauge per flag, set to 1 when the flag is set and 0
+// otherwise. The gauges are named '<type>_<flag>', both in snake case.
+// The generated code imports the prometheus client package, so it has to be
+// a dependency of the module the output belongs to.
+//
+// The -proto flag accepts a comma-separated list of protobuf Go messages, in
+// the form 'importpath.Message', matching the types provided in the -type
+// flag, in length, like the -outType flag. For each type with a message, a
+// ToProto and a FromProto method are generated, converting between the flags
+// and the message, with each flag mapped to the message field with the same
+// name.
+// If the '_' is provided as a message, no conversions are generated for its
+// matching source type.
 package main
 
 import (
@@ -148,19 +448,65 @@ var (
 	typeFlag       = flag.String("type", "", "comma-separated list of type names to generate flags for; must be set")
 	outTypeFlag    = flag.String("outType", "", "comma-separated list of generated type names; default <type>BitFlags")
 	outFileFlag    = flag.String("outFile", "", "output file name; default srcdir/<type>_flagged.go")
-	sizeFlag       = flag.Int("size", 0, "generated type size; one of 8,16,32,64; default depends on number of flags in <type>")
+	sizeFlag       = flag.String("size", "", "generated type size; one of 8,16,32,64, or auto-min:<size> to make it the minimum size; default depends on number of flags in <type>")
 	trimprefixFlag = flag.String("trimprefix", "", "trim the `prefix` from each field in <type> before using it")
 	trimsuffixFlag = flag.String("trimsuffix", "", "trim the `suffix` from each field in <type> before using it")
 
+	getterNameFlag   = flag.String("getterName", defaultGetterName, "`template` of the name of the method reporting whether each flag is set")
+	setterNameFlag   = flag.String("setterName", defaultSetterName, "`template` of the name of the method setting each flag")
+	resetterNameFlag = flag.String("resetterName", defaultResetterName, "`template` of the name of the method resetting each flag")
+	setterToNameFlag = flag.String("setterToName", defaultSetterToName, "`template` of the name of the method setting each flag to a value")
+	togglerNameFlag  = flag.String("togglerName", defaultTogglerName, "`template` of the name of the method toggling each flag")
+
 	buildTagsFlag = flag.String("tags", "", "comma-separated list of build tags to apply")
 
+	formatFlag = flag.String("format", formatGofmt, "how the generated Go files are formatted; one of gofmt,gofumpt,none")
+
 	rawFlag = flag.Bool("raw", false, "generate self-contained code that doesn't import 'github.com/asmsh/flagged'; omits the BitFlags method")
 
 	testsFlag = flag.Bool("tests", false, "also generate a companion _test.go file with tests for the generated types")
 
-	verboseFlag = flag.Bool("verbose", false, "enable detailed logging during execution, including while loading packages")
+	benchmarksFlag = flag.Bool("benchmarks", false, "also generate benchmarks for the generated types in the companion _test.go file")
+
+	fuzzFlag = flag.Bool("fuzz", false, "also generate fuzz targets for the generated types in the companion _test.go file")
+
+	examplesFlag = flag.Bool("examples", false, "also generate a companion _example_test.go file with runnable examples for the generated types")
+
+	mockFlag = flag.Bool("mock", false, "also generate a mock of the generated interface, recording calls, in the companion _test.go file")
+
+	valueReceiversFlag = flag.Bool("valueReceivers", false, "generate the read-only methods, like Is<field> and TypedFlags, on value receivers")
+
+	withFlag = flag.Bool("with", false, "also generate an immutable With<field> method for each field, returning a modified copy")
+
+	optionsFlag = flag.Bool("options", false, "also generate a New<outType> constructor taking functional options, with With<field> and Without<field> options")
 
-	// TODO: add a flag to generate benchmarks for the generated types.
+	atomicFlag = flag.Bool("atomic", false, "also generate a <type>AtomicBitFlags type, with the same per-field methods, implemented using sync/atomic")
+
+	safeFlag = flag.Bool("safe", false, "also generate a <type>SafeBitFlags type, guarded by a mutex, with a transactional Update method")
+
+	sliceFlag = flag.Bool("slice", false, "also generate a <outType>Slice type, with bulk Count<field>, CountMask and FilterMask methods")
+
+	contextFlag = flag.Bool("context", false, "also generate ContextWith<type> and <type>FromContext functions, passing the flags through a context.Context")
+
+	convertFlag = flag.Bool("convert", false, "also generate an AsBitFlags method on each <type>, converting it to its generated type")
+
+	crossConvertFlag = flag.Bool("crossConvert", false, "also generate To<outType> conversions between the generated types sharing flag names")
+
+	prometheusFlag = flag.Bool("prometheus", false, "also generate a prometheus.Collector exporting one gauge per flag for each generated type")
+
+	protoFlag = flag.String("proto", "", "comma-separated list of `importpath.Message` proto messages to generate conversions to, matching <type>")
+
+	hookFlag = flag.String("hook", "", "`command` to run for each generated type, with its model as JSON on its standard input, to generate additional files")
+
+	jsonSchemaFlag = flag.Bool("jsonSchema", false, "also generate a <type>JSONSchema constant, with a JSON Schema of the object form of the flags returned by ToMap")
+
+	guardFlag = flag.Bool("guard", false, "also generate a compile-time guard, failing the build when the fields of <type> change without regenerating")
+
+	lockFileFlag = flag.String("lockFile", "", "also generate versioned MarshalBinary and UnmarshalBinary methods, recording the layouts of the flags in the `file`")
+
+	docOutFlag = flag.String("docOut", "", "also write a markdown table of the flags of the generated types to the `file`")
+
+	verboseFlag = flag.Bool("verbose", false, "enable detailed logging during execution, including while loading packages")
 )
 
 // Usage is a replacement usage function for the flags package.
@@ -190,19 +536,31 @@ func main() {
 	in := validateFlags()
 
 	// Load the needed templates.
-	headerTmpl, err := template.New("header").Parse(flaggedHeaderTemplate)
+	headerTmpl, err := template.New("header").Funcs(templateFuncs).Parse(flaggedHeaderTemplate)
 	if err != nil {
 		log.Fatalf("error: internal: failed to load header template: %s", err)
 	}
-	bodyTmpl, err := template.New("body").Parse(flaggedTypeTemplate)
+	bodyTmpl, err := template.New("body").Funcs(templateFuncs).Parse(flaggedTypeTemplate)
 	if err != nil {
 		log.Fatalf("error: internal: failed to load type template: %s", err)
 	}
-	testHeaderTmpl, err := template.New("testHeader").Parse(flaggedTestHeaderTemplate)
+	crossConvertTmpl, err := template.New("crossConvert").Parse(flaggedCrossConvertTemplate)
 	if err != nil {
-		log.Fatalf("error: internal: failed to load test header template: %s", err)
+		log.Fatalf("error: internal: failed to load cross convert template: %s", err)
 	}
-	testBodyTmpl, err := template.New("testBody").Parse(flaggedTestTypeTemplate)
+	docHeaderTmpl, err := template.New("docHeader").Parse(flaggedDocHeaderTemplate)
+	if err != nil {
+		log.Fatalf("error: internal: failed to load doc header template: %s", err)
+	}
+	docBodyTmpl, err := template.New("docBody").Funcs(templateFuncs).Parse(flaggedDocTypeTemplate)
+	if err != nil {
+		log.Fatalf("error: internal: failed to load doc type template: %s", err)
+	}
+	exampleBodyTmpl, err := template.New("exampleBody").Funcs(templateFuncs).Parse(flaggedExampleTypeTemplate)
+	if err != nil {
+		log.Fatalf("error: internal: failed to load example type template: %s", err)
+	}
+	testBodyTmpl, err := template.New("testBody").Funcs(templateFuncs).Parse(flaggedTestTypeTemplate)
 	if err != nil {
 		log.Fatalf("error: internal: failed to load test type template: %s", err)
 	}
@@ -218,6 +576,15 @@ func main() {
 	// from which they were generated.
 	//
 	// Types will be excluded when generated, to avoid repetitions.
+	// Load the layouts recorded by the previous generations, if any.
+	var lock *schemaLock
+	if in.lockFile != "" {
+		lock, err = loadSchemaLock(in.lockFile)
+		if err != nil {
+			log.Fatalf("error: failed to load lock file: %s", err)
+		}
+	}
+
 	pkgs := loadPackages(in)
 	sort.Slice(pkgs, func(i, j int) bool {
 		// Put x_test packages last.
@@ -231,9 +598,28 @@ func main() {
 	})
 	for _, pkg := range pkgs {
 		g := Generator{
-			pkg:   pkg,
-			raw:   in.raw,
-			tests: in.genTests,
+			pkg:           pkg,
+			raw:           in.raw,
+			valueRecvs:    in.valueReceivers,
+			tests:         in.genTests,
+			benchmarks:    in.benchmarks,
+			fuzz:          in.fuzz,
+			examples:      in.examples,
+			doc:           in.docOut != "",
+			mock:          in.mock,
+			with:          in.with,
+			options:       in.options,
+			atomic:        in.atomic,
+			safe:          in.safe,
+			slice:         in.slice,
+			context:       in.context,
+			convert:       in.convert,
+			prometheus:    in.prometheus,
+			protoMessages: in.protoMessages,
+			guard:         in.guard,
+			jsonSchema:    in.jsonSchema,
+			lock:          lock,
+			formatter:     in.formatter,
 		}
 
 		verbose.Printf(
@@ -242,8 +628,6 @@ func main() {
 			len(in.sourceTypeNames),
 		)
 
-		g.generateHeader(headerTmpl, testHeaderTmpl)
-
 		// Run generate for types that can be found. Keep the rest for the remainingTypes iteration.
 		var foundTypes, remainingTypes []string
 		for idx, sourceTypeName := range in.sourceTypeNames {
@@ -293,7 +677,7 @@ func main() {
 					)
 				}
 
-				g.generateForStruct(sourceTypeName, outTypeName, bodyTmpl, testBodyTmpl, file)
+				g.generateForStruct(sourceTypeName, outTypeName, bodyTmpl, testBodyTmpl, exampleBodyTmpl, docBodyTmpl, file)
 				foundTypes = append(foundTypes, sourceTypeName)
 			} else {
 				remainingTypes = append(remainingTypes, sourceTypeName)
@@ -326,12 +710,28 @@ func main() {
 					*outFileFlag,
 				)
 			}
+			if in.docOut != "" {
+				log.Fatalf(
+					"error: cannot write to single file (-docOut=%q) when matching types are found in multiple packages",
+					in.docOut,
+				)
+			}
 		}
 
 		// Update the source types to the remaining types, to try to find
 		// them in the rest of the loaded packages.
 		in.sourceTypeNames = remainingTypes
 
+		if in.crossConvert {
+			g.generateCrossConversions(crossConvertTmpl)
+		}
+
+		// Generate the header, now that all the needed imports are known.
+		g.generateHeader(headerTmpl)
+		if g.doc {
+			g.generateDocHeader(docHeaderTmpl)
+		}
+
 		// Format the output.
 		src := g.format()
 
@@ -354,7 +754,7 @@ func main() {
 		}
 
 		// Write the companion test file next to the generated code.
-		if in.genTests {
+		if g.hasTestFile() {
 			testFileName := testFileName(outFileName)
 			verbose.Printf(
 				"info: writing tests to file %s after processing package %s\n",
@@ -365,6 +765,50 @@ func main() {
 				log.Fatalf("error: failed to write to test out file: %s", err)
 			}
 		}
+
+		// Write the companion example file next to the generated code.
+		if g.examples {
+			exampleFileName := exampleFileName(outFileName)
+			verbose.Printf(
+				"info: writing examples to file %s after processing package %s\n",
+				exampleFileName,
+				pkg.name,
+			)
+			if err := os.WriteFile(exampleFileName, g.formatExamples(), 0644); err != nil {
+				log.Fatalf("error: failed to write to example out file: %s", err)
+			}
+		}
+
+		// Write the markdown document of the flags.
+		if g.doc {
+			verbose.Printf(
+				"info: writing doc to file %s after processing package %s\n",
+				in.docOut,
+				pkg.name,
+			)
+			if err := os.WriteFile(in.docOut, append(g.docHeader.Bytes(), g.docBuf.Bytes()...), 0644); err != nil {
+				log.Fatalf("error: failed to write to doc out file: %s", err)
+			}
+		}
+
+		// Run the hook for each generated type, now that all the files are
+		// written.
+		if len(in.hook) > 0 {
+			for _, t := range g.types {
+				verbose.Printf("info: running hook for type %s\n", t.OutTypeName)
+				model := newHookModel(pkg.name, outFileName, t)
+				if err := runHook(in.hook, filepath.Dir(outFileName), model); err != nil {
+					log.Fatalf("error: failed to run hook: %s", err)
+				}
+			}
+		}
+	}
+
+	if lock != nil {
+		verbose.Printf("info: writing lock file %s\n", in.lockFile)
+		if err := lock.save(in.lockFile); err != nil {
+			log.Fatalf("error: failed to write to lock file: %s", err)
+		}
 	}
 
 	if len(in.sourceTypeNames) > 0 {
@@ -378,11 +822,56 @@ func main() {
 // Generator holds the state of the analysis.
 // Primarily used to buffer the output for format.Source.
 type Generator struct {
-	buf     bytes.Buffer // Accumulated output.
-	testBuf bytes.Buffer // Accumulated output for the companion _test.go file.
-	pkg     *Package     // Package we are scanning.
-	raw     bool         // Generate self-contained code without the flagged dependency.
-	tests   bool         // Also generate a companion _test.go file.
+	header     bytes.Buffer // Header of the output, generated last.
+	buf        bytes.Buffer // Accumulated output.
+	testHeader bytes.Buffer // Header of the companion _test.go file, generated last.
+	testBuf    bytes.Buffer // Accumulated output for the companion _test.go file.
+
+	exampleHeader bytes.Buffer // Header of the companion _example_test.go file, generated last.
+	exampleBuf    bytes.Buffer // Accumulated output for the companion _example_test.go file.
+
+	docHeader bytes.Buffer // Header of the markdown document, generated last.
+	docBuf    bytes.Buffer // Accumulated output for the markdown document.
+
+	imports     map[string]string // Imports needed by the output, keyed by path, with optional name.
+	testImports map[string]string // Imports needed by the companion _test.go file, like imports.
+
+	pkg        *Package // Package we are scanning.
+	raw        bool     // Generate self-contained code without the flagged dependency.
+	valueRecvs bool     // Generate the read-only methods on value receivers.
+	tests      bool     // Also generate tests in the companion _test.go file.
+	benchmarks bool     // Also generate benchmarks in the companion _test.go file.
+	fuzz       bool     // Also generate fuzz targets in the companion _test.go file.
+	examples   bool     // Also generate examples in the companion _example_test.go file.
+	doc        bool     // Also generate a markdown table of the flags.
+	mock       bool     // Also generate mocks in the companion _test.go file.
+	with       bool     // Also generate immutable With<field> methods.
+	options    bool     // Also generate a functional-options constructor.
+	atomic     bool     // Also generate an atomic variant of each type.
+	safe       bool     // Also generate a mutex-guarded variant of each type.
+	slice      bool     // Also generate a slice type of each type, with bulk methods.
+	context    bool     // Also generate context helpers for each type.
+	convert    bool     // Also generate a conversion method on each source type.
+	prometheus bool     // Also generate a prometheus.Collector for each type.
+	guard      bool     // Also generate a compile-time guard for each type.
+	jsonSchema bool     // Also generate a JSON Schema of the object form of each type.
+
+	// types are the inputs of the types generated so far in the package.
+	types []templateTypeInput
+
+	// optionFuncs are the option functions generated so far in the
+	// package, mapped to the source type they are generated for.
+	optionFuncs map[string]string
+
+	// lock records the layouts of the generated types, if -lockFile is set.
+	lock *schemaLock
+
+	// formatter is how the generated Go files are formatted, from -format.
+	formatter string
+
+	// protoMessages are the proto messages to generate conversions to,
+	// keyed by the source type name.
+	protoMessages map[string]protoMessage
 }
 
 type Package struct {
@@ -395,6 +884,13 @@ type Package struct {
 	trimPrefix string
 	trimSuffix string
 	flagsSize  int
+
+	// sizeAutoMin makes flagsSize the minimum size, rather than the exact
+	// size, of the generated types.
+	sizeAutoMin bool
+
+	// methodNames are the templates of the names of the per-flag methods.
+	methodNames methodNames
 }
 
 // File holds a single parsed file and associated data.
@@ -406,6 +902,7 @@ type File struct {
 	sourceTypeName  string // Name of the source flag type.
 	foundSourceType types.Object
 	flagValues      []flagValue // Accumulator for flag values of that type.
+	flagGroups      []flagGroup // Groups declared in the rules of flagValues.
 	flagsSize       int         // Actual value based on number of flagValues
 }
 
@@ -446,12 +943,15 @@ func loadPackages(in *input) []*Package {
 	out := make([]*Package, len(pkgs))
 	for i, pkg := range pkgs {
 		p := &Package{
-			name:       pkg.Name,
-			defs:       pkg.TypesInfo.Defs,
-			files:      make([]*File, len(pkg.Syntax)),
-			trimPrefix: in.trimPrefix,
-			trimSuffix: in.trimSuffix,
-			flagsSize:  in.flagsSize,
+			name:        pkg.Name,
+			defs:        pkg.TypesInfo.Defs,
+			files:       make([]*File, len(pkg.Syntax)),
+			trimPrefix:  in.trimPrefix,
+			trimSuffix:  in.trimSuffix,
+			flagsSize:   in.flagsSize,
+			sizeAutoMin: in.sizeAutoMin,
+
+			methodNames: in.methodNames,
 		}
 
 		for j, file := range pkg.Syntax {
@@ -482,6 +982,7 @@ func (pkg *Package) findStructTypeFile(sourceTypeName string) *File {
 		file.sourceTypeName = sourceTypeName
 		file.foundSourceType = nil
 		file.flagValues = nil
+		file.flagGroups = nil
 		file.flagsSize = 0
 
 		// Return the first file we find the matching sourceTypeName in.
@@ -493,22 +994,96 @@ func (pkg *Package) findStructTypeFile(sourceTypeName string) *File {
 	return nil
 }
 
-func (g *Generator) generateHeader(headerTmpl, testHeaderTmpl *template.Template) {
+// addImport records that the output needs the package at path, imported
+// with the given name, or with its default name if name is empty.
+func (g *Generator) addImport(name, path string) {
+	if g.imports == nil {
+		g.imports = make(map[string]string)
+	}
+	g.imports[path] = name
+}
+
+// addTestImport is like addImport, but for the companion _test.go file.
+func (g *Generator) addTestImport(name, path string) {
+	if g.testImports == nil {
+		g.testImports = make(map[string]string)
+	}
+	g.testImports[path] = name
+}
+
+// hasTestFile reports whether a companion _test.go file is generated.
+func (g *Generator) hasTestFile() bool {
+	return g.tests || g.benchmarks || g.fuzz || g.mock
+}
+
+// generateHeader generates the header, package clause and imports of the
+// output files.
+// It must be called after all types are generated, so that all the needed
+// imports are recorded.
+func (g *Generator) generateHeader(headerTmpl *template.Template) {
 	// Print the header and package clause.
 	headerInput := templateHeaderInput{
-		CmdArgs:     strings.Join(os.Args[1:], " "),
-		PackageName: g.pkg.name,
-		Raw:         g.raw,
+		CmdArgs:      strings.Join(os.Args[1:], " "),
+		PackageName:  g.pkg.name,
+		ImportGroups: groupImports(g.imports),
 	}
-	if err := headerTmpl.Execute(&g.buf, headerInput); err != nil {
+	if err := headerTmpl.Execute(&g.header, headerInput); err != nil {
 		log.Fatalf("error: failed to generate header: %s", err)
 	}
 
-	if g.tests {
-		if err := testHeaderTmpl.Execute(&g.testBuf, headerInput); err != nil {
+	if g.hasTestFile() {
+		headerInput.ImportGroups = groupImports(g.testImports)
+		if err := headerTmpl.Execute(&g.testHeader, headerInput); err != nil {
 			log.Fatalf("error: failed to generate test header: %s", err)
 		}
 	}
+
+	if g.examples {
+		headerInput.ImportGroups = groupImports(map[string]string{"fmt": ""})
+		if err := headerTmpl.Execute(&g.exampleHeader, headerInput); err != nil {
+			log.Fatalf("error: failed to generate example header: %s", err)
+		}
+	}
+}
+
+// generateDocHeader generates the header of the markdown document.
+func (g *Generator) generateDocHeader(docHeaderTmpl *template.Template) {
+	headerInput := templateHeaderInput{
+		CmdArgs:     strings.Join(os.Args[1:], " "),
+		PackageName: g.pkg.name,
+	}
+	if err := docHeaderTmpl.Execute(&g.docHeader, headerInput); err != nil {
+		log.Fatalf("error: failed to generate doc header: %s", err)
+	}
+}
+
+// groupImports returns the imports, keyed by path, grouped into standard
+// library and other packages, in that order, with each group sorted by path.
+func groupImports(imports map[string]string) [][]importSpec {
+	var std, other []importSpec
+	for path, name := range imports {
+		spec := importSpec{Name: name, Path: path}
+
+		// Like goimports, assume only non-standard packages have a dot in
+		// their first path element.
+		if first, _, _ := strings.Cut(path, "/"); strings.Contains(first, ".") {
+			other = append(other, spec)
+		} else {
+			std = append(std, spec)
+		}
+	}
+
+	var groups [][]importSpec
+	for _, group := range [][]importSpec{std, other} {
+		if len(group) == 0 {
+			continue
+		}
+		sort.Slice(group, func(i, j int) bool {
+			return group[i].Path < group[j].Path
+		})
+		groups = append(groups, group)
+	}
+	return groups
 }
 
 func (g *Generator) generateForStruct(
@@ -516,6 +1091,8 @@ func (g *Generator) generateForStruct(
 	outTypeName string,
 	bodyTmpl *template.Template,
 	testBodyTmpl *template.Template,
+	exampleBodyTmpl *template.Template,
+	docBodyTmpl *template.Template,
 	structFile *File,
 ) {
 	// Make sure the flags size is within allowed limit.
@@ -532,7 +1109,18 @@ func (g *Generator) generateForStruct(
 	if g.pkg.flagsSize != 0 {
 		// If the want size is less than the required for the current file,
 		// return with an error.
-		if g.pkg.flagsSize < size {
+		switch {
+		case g.pkg.flagsSize >= size:
+			size = g.pkg.flagsSize
+		case g.pkg.sizeAutoMin:
+			log.Printf(
+				"warning: type %s flags size is too small; required at least %d, requested %d; using %d",
+				sourceTypeName,
+				size,
+				g.pkg.flagsSize,
+				size,
+			)
+		default:
 			log.Fatalf(
 				"error: type %s flags size is too small; required at least %d, requested %d",
 				sourceTypeName,
@@ -540,20 +1128,103 @@ func (g *Generator) generateForStruct(
 				g.pkg.flagsSize,
 			)
 		}
-		size = g.pkg.flagsSize
 	}
 
 	// In raw mode the generated code is self-contained: the underlying type
 	// is a plain uint and bit indexes are plain ints, so nothing from the
 	// flagged package is referenced.
+	// Only the standard library is imported in both modes.
 	underlyingType := fmt.Sprintf("uint%d", size)
 	bitIndexType := "int"
 	if !g.raw {
 		underlyingType = fmt.Sprintf("flagged.BitFlags%d", size)
 		bitIndexType = "flagged.BitIndex"
+		g.addImport("", "github.com/asmsh/flagged")
+	}
+	g.addImport("", "fmt")
+	g.addImport("", "iter")
+	g.addImport("", "strconv")
+	if g.prometheus {
+		g.addImport("", "github.com/prometheus/client_golang/prometheus")
+	}
+	if g.options {
+		g.checkOptionFuncs(sourceTypeName, structFile.flagValues)
+	}
+	// The atomic types don't support 8 and 16 bits, so use 32 bits instead.
+	atomicUint := "uint32"
+	if size == 64 {
+		atomicUint = "uint64"
+	}
+	if g.atomic {
+		g.addImport("", "sync/atomic")
+	}
+	if g.safe {
+		g.addImport("", "sync")
+	}
+	if g.context {
+		g.addImport("", "context")
+	}
+	if hasRules(structFile.flagValues, structFile.flagGroups) {
+		g.addImport("", "errors")
+	}
+	if g.tests {
+		g.addTestImport("", "reflect")
+		g.addTestImport("", "slices")
+		g.addTestImport("", "testing")
+		if g.context {
+			g.addTestImport("", "context")
+		}
+		if g.jsonSchema {
+			g.addTestImport("", "encoding/json")
+		}
+		if g.lock != nil && !g.raw {
+			g.addTestImport("", "github.com/asmsh/flagged")
+		}
+	}
+	if g.benchmarks || g.fuzz {
+		g.addTestImport("", "testing")
+	}
+	if g.mock && !g.raw {
+		g.addTestImport("", "github.com/asmsh/flagged")
+	}
+	protoMsg, hasProto := g.protoMessages[sourceTypeName]
+	if hasProto {
+		g.addImport(protoMsg.importName, protoMsg.importPath)
+	}
+
+	reserved := reservedMethodNames(methodFeatures{
+		raw:        g.raw,
+		versioned:  g.lock != nil,
+		rules:      hasRules(structFile.flagValues, structFile.flagGroups),
+		prometheus: g.prometheus,
+		proto:      hasProto,
+	})
+	if err := checkReservedMethodNames(structFile.flagValues, reserved); err != nil {
+		log.Fatalf("error: invalid method names in type %s: %s", sourceTypeName, err)
+	}
+
+	var schema string
+	if g.jsonSchema {
+		schema = jsonSchema(outTypeName, structFile.flagValues)
+	}
+
+	var sourceStruct string
+	if g.guard {
+		st := structFile.foundSourceType.Type().Underlying().(*types.Struct)
+		sourceStruct = g.sourceStruct(st, structFile.foundSourceType.Pkg())
+	}
+
+	var schemaVersions []schemaVersion
+	if g.lock != nil {
+		var err error
+		schemaVersions, err = g.lock.schemaVersions(g.pkg.name+"."+outTypeName, structFile.flagValues)
+		if err != nil {
+			log.Fatalf("error: failed to version type %s: %s", sourceTypeName, err)
+		}
 	}
 
 	tmplInput := templateTypeInput{
+		PackageName:      g.pkg.name,
 		SourceTypeName:   sourceTypeName,
 		OutTypeName:      outTypeName,
 		OutTypeSize:      size,
@@ -561,8 +1232,28 @@ func (g *Generator) generateForStruct(
 		UnderlyingType:   underlyingType,
 		BitIndexType:     bitIndexType,
 		Raw:              g.raw,
+		ValueReceivers:   g.valueRecvs,
+		With:             g.with,
+		Options:          g.options,
+		Atomic:           g.atomic,
+		AtomicUint:       atomicUint,
+		Safe:             g.safe,
+		Slice:            g.slice,
+		Context:          g.context,
+		Convert:          g.convert,
+		Tests:            g.tests,
+		Benchmarks:       g.benchmarks,
+		Fuzz:             g.fuzz,
+		Mock:             g.mock,
+		Prometheus:       g.prometheus,
+		ProtoMessage:     protoMsg.qualifiedName(),
+		JSONSchema:       schema,
+		SourceStruct:     sourceStruct,
 		FlagValues:       structFile.flagValues,
+		FlagGroups:       structFile.flagGroups,
+		SchemaVersions:   schemaVersions,
 	}
+	g.types = append(g.types, tmplInput)
 	if err := bodyTmpl.Execute(&g.buf, tmplInput); err != nil {
 		log.Fatalf(
 			"error: failed to generated implementation for type %s: %s",
@@ -571,7 +1262,7 @@ func (g *Generator) generateForStruct(
 		)
 	}
 
-	if g.tests {
+	if g.hasTestFile() {
 		if err := testBodyTmpl.Execute(&g.testBuf, tmplInput); err != nil {
 			log.Fatalf(
 				"error: failed to generate tests for type %s: %s",
@@ -580,21 +1271,110 @@ func (g *Generator) generateForStruct(
 			)
 		}
 	}
+
+	if g.examples {
+		if err := exampleBodyTmpl.Execute(&g.exampleBuf, tmplInput); err != nil {
+			log.Fatalf(
+				"error: failed to generate examples for type %s: %s",
+				sourceTypeName,
+				err,
+			)
+		}
+	}
+
+	if g.doc {
+		if err := docBodyTmpl.Execute(&g.docBuf, tmplInput); err != nil {
+			log.Fatalf(
+				"error: failed to generate doc for type %s: %s",
+				sourceTypeName,
+				err,
+			)
+		}
+	}
+}
+
+// generateCrossConversions generates conversions between each pair of the
+// generated types, which share at least one flag name.
+func (g *Generator) generateCrossConversions(crossConvertTmpl *template.Template) {
+	for _, from := range g.types {
+		for _, to := range g.types {
+			if from.OutTypeName == to.OutTypeName {
+				continue
+			}
+
+			input := templateCrossConvertInput{From: from, To: to}
+			for _, fromFV := range from.FlagValues {
+				for _, toFV := range to.FlagValues {
+					if fromFV.Flag == toFV.Flag {
+						input.SharedFlags = append(input.SharedFlags, fromFV)
+						break
+					}
+				}
+			}
+			if len(input.SharedFlags) == 0 {
+				verbose.Printf(
+					"info: skip conversion from type %s to type %s with no shared flags\n",
+					from.OutTypeName,
+					to.OutTypeName,
+				)
+				continue
+			}
+
+			if err := crossConvertTmpl.Execute(&g.buf, input); err != nil {
+				log.Fatalf(
+					"error: failed to generate conversion from type %s to type %s: %s",
+					from.OutTypeName,
+					to.OutTypeName,
+					err,
+				)
+			}
+		}
+	}
+}
+
+// checkOptionFuncs makes sure the option functions of the source type don't
+// collide with the ones generated for other types in the same package.
+func (g *Generator) checkOptionFuncs(sourceTypeName string, flagValues []flagValue) {
+	if g.optionFuncs == nil {
+		g.optionFuncs = make(map[string]string)
+	}
+	for _, fv := range flagValues {
+		for _, name := range []string{"With" + fv.Flag, "Without" + fv.Flag} {
+			if other, ok := g.optionFuncs[name]; ok {
+				log.Fatalf(
+					"error: option function %s of type %s collides with the one of type %s",
+					name,
+					sourceTypeName,
+					other,
+				)
+			}
+			g.optionFuncs[name] = sourceTypeName
+		}
+	}
 }
 
-// format returns the gofmt-ed contents of the Generator's buffer.
+// format returns the formatted contents of the Generator's buffer.
 func (g *Generator) format() []byte {
-	return formatSource(g.buf.Bytes())
+	return g.formatSource(append(g.header.Bytes(), g.buf.Bytes()...))
 }
 
-// formatTests returns the gofmt-ed contents of the Generator's test buffer.
+// formatTests returns the formatted contents of the Generator's test buffer.
 func (g *Generator) formatTests() []byte {
-	return formatSource(g.testBuf.Bytes())
+	return g.formatSource(append(g.testHeader.Bytes(), g.testBuf.Bytes()...))
+}
+
+// formatExamples returns the formatted contents of the Generator's example buffer.
+func (g *Generator) formatExamples() []byte {
+	return g.formatSource(append(g.exampleHeader.Bytes(), g.exampleBuf.Bytes()...))
 }
 
-// formatSource gofmt's src, falling back to the raw bytes when it can't be
-// parsed so the user can compile it to see the underlying error.
-func formatSource(src []byte) []byte {
+// formatSource formats src as set by -format, falling back to the raw bytes
+// when it can't be parsed so the user can compile it to see the underlying
+// error.
+func (g *Generator) formatSource(src []byte) []byte {
+	if g.formatter == formatNone {
+		return src
+	}
 	out, err := format.Source(src)
 	if err != nil {
 		// Should never happen, but can arise when developing this code.
@@ -603,5 +1383,11 @@ func formatSource(src []byte) []byte {
 		log.Printf("warning: compile the package to analyze the error")
 		return src
 	}
+	if g.formatter == formatGofumpt {
+		out, err = gofumpt(out)
+		if err != nil {
+			log.Fatalf("error: failed to format with gofumpt: %s", err)
+		}
+	}
 	return out
 }
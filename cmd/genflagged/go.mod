@@ -2,9 +2,13 @@ module github.com/asmsh/flagged/cmd/genflagged
 
 go 1.25.0
 
-require golang.org/x/tools v0.47.0
+require (
+	golang.org/x/tools v0.47.0
+	mvdan.cc/gofumpt v0.9.2
+)
 
 require (
+	github.com/google/go-cmp v0.7.0 // indirect
 	golang.org/x/mod v0.37.0 // indirect
 	golang.org/x/sync v0.21.0 // indirect
 )
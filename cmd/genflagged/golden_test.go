@@ -2,9 +2,14 @@ package main
 
 import (
 	"flag"
+	"go/ast"
+	"go/parser"
+	"go/token"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"slices"
+	"strconv"
 	"strings"
 	"testing"
 )
@@ -26,6 +31,40 @@ var goldenFixtures = []string{
 	"raw_options",
 	"tested_options",
 	"raw_tested_options",
+	"prometheus_options",
+	"proto_options",
+	"with_options",
+	"options_constructor",
+	"atomic_options",
+	"safe_options",
+	"mock_options",
+	"convert_options",
+	"cross_convert_options",
+	"slice_options",
+	"context_options",
+	"rules_options",
+	"full_tested_options",
+	"benchmarked_options",
+	"fuzzed_options",
+	"example_options",
+	"doc_options",
+	"named_options",
+	"value_receivers_options",
+	"versioned_options",
+	"unformatted_options",
+	"array_options",
+	"guard_options",
+	"json_schema_options",
+	"hook_options",
+	"size_auto_min_options",
+	"raw_reserved_options",
+	"gofumpt_options",
+}
+
+// warningFixtures are the goldenFixtures whose generation is expected to
+// warn with the mapped message, while still succeeding.
+var warningFixtures = map[string]string{
+	"size_auto_min_options": "warning: type Large flags size is too small; required at least 16, requested 8; using 16",
 }
 
 func TestGolden(t *testing.T) {
@@ -47,9 +86,13 @@ func TestGolden(t *testing.T) {
 			args := generateArgs(t, inputs)
 			gen := exec.Command(bin, append(args, ".")...)
 			gen.Dir = filepath.Dir(inputs[0])
-			if out, err := gen.CombinedOutput(); err != nil {
+			out, err := gen.CombinedOutput()
+			if err != nil {
 				t.Fatalf("running genflagged %v: %v\n%s", args, err, out)
 			}
+			if want, ok := warningFixtures[fixture]; ok && !strings.Contains(string(out), want) {
+				t.Errorf("running genflagged %v output:\n%s\nwant warning %q", args, out, want)
+			}
 
 			// Compare every produced file against its golden counterpart.
 			for _, produced := range producedFiles(t, gen.Dir, inputs) {
@@ -73,16 +116,58 @@ func TestGolden(t *testing.T) {
 						filepath.Base(produced), golden, got, want)
 				}
 			}
+
+			// Comparing the text doesn't catch generated code that doesn't
+			// compile, like a method no longer implementing flagged.BitFlags,
+			// so build it, with its tests, against the local flagged package.
+			vet := exec.Command("go", "vet", ".")
+			vet.Dir = gen.Dir
+			if out, err := vet.CombinedOutput(); err != nil {
+				t.Errorf("building the generated code: %v\n%s", err, out)
+			}
+		})
+	}
+}
+
+// errorFixtures are directories under testdata/errors/, like goldenFixtures,
+// whose generation is expected to fail with the mapped error.
+var errorFixtures = map[string]string{
+	"reserved_options":            "error: invalid method names in type reservedOptions: setter name SetAll of field All collides with a method of the generated type",
+	"prometheus_reserved_options": "error: invalid method names in type reservedOptions: getter name Collector of field Collector collides with a method of the generated type",
+	"size_options":                "error: invalid size argument auto-min:12: supported sizes are 8,16,32,64",
+}
+
+func TestGoldenErrors(t *testing.T) {
+	bin := filepath.Join(t.TempDir(), "genflagged")
+	if out, err := exec.Command("go", "build", "-o", bin, ".").CombinedOutput(); err != nil {
+		t.Fatalf("building genflagged: %v\n%s", err, out)
+	}
+
+	for fixture, want := range errorFixtures {
+		t.Run(fixture, func(t *testing.T) {
+			inputs := copyFixture(t, filepath.Join("testdata", "errors", fixture))
+			args := generateArgs(t, inputs)
+			gen := exec.Command(bin, append(args, ".")...)
+			gen.Dir = filepath.Dir(inputs[0])
+			out, err := gen.CombinedOutput()
+			if err == nil {
+				t.Fatalf("running genflagged %v succeeded, want error %q", args, want)
+			}
+			if !strings.Contains(string(out), want) {
+				t.Errorf("running genflagged %v failed with:\n%s\nwant error %q", args, out, want)
+			}
 		})
 	}
 }
 
 // copyFixture copies the .go files from srcDir into a fresh temp module
 // and returns the paths of the copied files.
+// It also copies the .json lock files, which the generator updates, so they
+// aren't returned as inputs, but compared as produced files.
 func copyFixture(t *testing.T, srcDir string) []string {
 	t.Helper()
 	tmp := t.TempDir()
-	writeFile(t, filepath.Join(tmp, "go.mod"), "module fixture\n\ngo 1.23\n")
+	writeFile(t, filepath.Join(tmp, "go.mod"), fixtureGoMod(t))
 
 	entries, err := os.ReadDir(srcDir)
 	if err != nil {
@@ -90,7 +175,8 @@ func copyFixture(t *testing.T, srcDir string) []string {
 	}
 	var copied []string
 	for _, e := range entries {
-		if e.IsDir() || !strings.HasSuffix(e.Name(), ".go") {
+		isLock := strings.HasSuffix(e.Name(), ".json")
+		if e.IsDir() || !strings.HasSuffix(e.Name(), ".go") && !isLock {
 			continue
 		}
 		content, err := os.ReadFile(filepath.Join(srcDir, e.Name()))
@@ -99,7 +185,9 @@ func copyFixture(t *testing.T, srcDir string) []string {
 		}
 		dst := filepath.Join(tmp, e.Name())
 		writeFile(t, dst, string(content))
-		copied = append(copied, dst)
+		if !isLock {
+			copied = append(copied, dst)
+		}
 	}
 	if len(copied) == 0 {
 		t.Fatalf("no .go files in %s", srcDir)
@@ -107,6 +195,33 @@ func copyFixture(t *testing.T, srcDir string) []string {
 	return copied
 }
 
+// fixtureModules are the modules imported by the generated code, mapped to
+// their local directories, relative to the genflagged directory; the ones
+// under testdata/stubs/ only have the API the generated code uses.
+var fixtureModules = map[string]string{
+	"github.com/asmsh/flagged":            "../..",
+	"github.com/prometheus/client_golang": "testdata/stubs/client_golang",
+	"example.com/gen/optionspb":           "testdata/stubs/optionspb",
+}
+
+// fixtureGoMod returns the go.mod of the temp module of a fixture, which
+// replaces each of the fixtureModules by its local directory, so the
+// generated code builds without the network.
+func fixtureGoMod(t *testing.T) string {
+	t.Helper()
+	var b strings.Builder
+	b.WriteString("module fixture\n\ngo 1.23\n")
+	for path, dir := range fixtureModules {
+		abs, err := filepath.Abs(dir)
+		if err != nil {
+			t.Fatal(err)
+		}
+		b.WriteString("\nrequire " + path + " v0.0.0\n")
+		b.WriteString("\nreplace " + path + " => " + abs + "\n")
+	}
+	return b.String()
+}
+
 func writeFile(t *testing.T, path, content string) {
 	t.Helper()
 	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
@@ -129,7 +244,7 @@ func generateArgs(t *testing.T, files []string) []string {
 			if !strings.HasPrefix(strings.TrimSpace(line), "//go:generate") {
 				continue
 			}
-			fields := strings.Fields(line)
+			fields := splitGenerateArgs(t, line)
 			for i, fld := range fields {
 				if fld == marker {
 					return fields[i+1:]
@@ -141,7 +256,33 @@ func generateArgs(t *testing.T, files []string) []string {
 	return nil
 }
 
-// producedFiles returns the .go files in dir that were not part of the
+// splitGenerateArgs splits a //go:generate directive into its arguments,
+// separated by spaces, unless double-quoted, like the go generate command.
+func splitGenerateArgs(t *testing.T, line string) []string {
+	t.Helper()
+	var args []string
+	for line = strings.TrimSpace(line); line != ""; line = strings.TrimSpace(line) {
+		if line[0] != '"' {
+			arg, rest, _ := strings.Cut(line, " ")
+			args = append(args, arg)
+			line = rest
+			continue
+		}
+		quoted, err := strconv.QuotedPrefix(line)
+		if err != nil {
+			t.Fatalf("invalid quoted argument in %q: %v", line, err)
+		}
+		arg, err := strconv.Unquote(quoted)
+		if err != nil {
+			t.Fatal(err)
+		}
+		args = append(args, arg)
+		line = line[len(quoted):]
+	}
+	return args
+}
+
+// producedFiles returns the .go, .md and .json files in dir that were not part of the
 // copied inputs (i.e. the generator's output).
 func producedFiles(t *testing.T, dir string, inputs []string) []string {
 	t.Helper()
@@ -155,7 +296,14 @@ func producedFiles(t *testing.T, dir string, inputs []string) []string {
 	}
 	var produced []string
 	for _, e := range entries {
-		if e.IsDir() || !strings.HasSuffix(e.Name(), ".go") || original[e.Name()] {
+		if e.IsDir() || original[e.Name()] {
+			continue
+		}
+		// Besides the Go files, the generator can write a markdown doc, and
+		// update a lock file.
+		switch filepath.Ext(e.Name()) {
+		case ".go", ".md", ".json":
+		default:
 			continue
 		}
 		produced = append(produced, filepath.Join(dir, e.Name()))
@@ -165,3 +313,73 @@ func producedFiles(t *testing.T, dir string, inputs []string) []string {
 	}
 	return produced
 }
+
+// TestReservedMethodNames checks reservedMethodNames against the methods
+// actually generated in the goldens, which are the reserved ones, plus the
+// per-flag ones, so it's kept in sync with the template.
+func TestReservedMethodNames(t *testing.T) {
+	tests := []struct {
+		fixture  string
+		outType  string
+		features methodFeatures
+		flags    []string
+	}{
+		{"options", "optionsBitFlags", methodFeatures{}, []string{"Flag0", "Flag1", "Flag2", "Flag3", "Flag4", "Flag5"}},
+		{"raw_reserved_options", "rawReservedOptionsBitFlags", methodFeatures{raw: true}, []string{"All", "Bits", "Size"}},
+		{"versioned_options", "PermissionsBitFlags", methodFeatures{versioned: true}, []string{"Write", "Read", "Admin"}},
+		{"rules_options", "PermissionsBitFlags", methodFeatures{rules: true}, []string{"Read", "Write", "Admin", "Audit", "Guest", "Legacy"}},
+		{"prometheus_options", "ServerOptionsBitFlags", methodFeatures{prometheus: true}, []string{"EnableTLS", "HTTP2", "AccessLogs", "Maintenance"}},
+		{"proto_options", "OptionsBitFlags", methodFeatures{proto: true}, []string{"Verbose", "DryRun", "Force"}},
+	}
+	names, err := parseMethodNames(defaultGetterName, defaultSetterName, defaultResetterName, defaultSetterToName, defaultTogglerName)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, tt := range tests {
+		t.Run(tt.fixture, func(t *testing.T) {
+			flagValues := make([]flagValue, len(tt.flags))
+			for i, flag := range tt.flags {
+				flagValues[i] = flagValue{Field: flag, Flag: flag}
+			}
+			if err := names.setMethodNames(flagValues); err != nil {
+				t.Fatal(err)
+			}
+			want := reservedMethodNames(tt.features)
+			for _, fv := range flagValues {
+				want = append(want, fv.Getter, fv.Setter, fv.Resetter, fv.SetterTo, fv.Toggler)
+			}
+			slices.Sort(want)
+
+			got := generatedMethodNames(t, filepath.Join("testdata", tt.fixture, tt.fixture+"_flagged.go.golden"), tt.outType)
+			if !slices.Equal(got, want) {
+				t.Errorf("generated methods of %s:\n%v\nwant the reserved and per-flag ones:\n%v", tt.outType, got, want)
+			}
+		})
+	}
+}
+
+// generatedMethodNames returns the sorted names of the methods of typeName
+// declared in the Go file at path.
+func generatedMethodNames(t *testing.T, path, typeName string) []string {
+	t.Helper()
+	file, err := parser.ParseFile(token.NewFileSet(), path, nil, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for _, decl := range file.Decls {
+		fn, ok := decl.(*ast.FuncDecl)
+		if !ok || fn.Recv == nil {
+			continue
+		}
+		recv := fn.Recv.List[0].Type
+		if star, ok := recv.(*ast.StarExpr); ok {
+			recv = star.X
+		}
+		if id, ok := recv.(*ast.Ident); ok && id.Name == typeName {
+			names = append(names, fn.Name.Name)
+		}
+	}
+	slices.Sort(names)
+	return names
+}
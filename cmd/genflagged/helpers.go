@@ -3,6 +3,8 @@ package main
 import (
 	"bytes"
 	"fmt"
+	"go/ast"
+	"go/types"
 	"strings"
 	"unicode"
 )
@@ -32,6 +34,83 @@ func testFileName(outFileName string) string {
 	return base + "_test.go"
 }
 
+// exampleFileName derives the companion example file name from the
+// generated output file name, e.g. "options_flagged.go" ->
+// "options_flagged_example_test.go".
+func exampleFileName(outFileName string) string {
+	base := strings.TrimSuffix(outFileName, ".go")
+	base = strings.TrimSuffix(base, "_test")
+	return base + "_example_test.go"
+}
+
+// fieldDoc returns the doc comment of a field, or its line comment if it
+// has no doc comment, joined into a single line.
+func fieldDoc(field *ast.Field) string {
+	group := field.Doc
+	if group == nil {
+		group = field.Comment
+	}
+	return strings.Join(strings.Fields(group.Text()), " ")
+}
+
+// sourceStruct returns the struct type st, of a type defined in pkg, as Go
+// source, with no tags, which a value of the type can be converted to, as
+// long as its fields don't change.
+// The packages of the types of its fields are added to the imports of g.
+func (g *Generator) sourceStruct(st *types.Struct, pkg *types.Package) string {
+	qualifier := func(other *types.Package) string {
+		if other == pkg {
+			return ""
+		}
+		g.addImport("", other.Path())
+		return other.Name()
+	}
+
+	var sb strings.Builder
+	sb.WriteString("struct {\n")
+	for field := range st.Fields() {
+		if !field.Embedded() {
+			sb.WriteString(field.Name())
+			sb.WriteByte(' ')
+		}
+		sb.WriteString(types.TypeString(field.Type(), qualifier))
+		sb.WriteByte('\n')
+	}
+	sb.WriteString("}")
+	return sb.String()
+}
+
+// bitMask formats the mask of the bit at index idx, as a hex number with as
+// many digits as needed for a value of the given bit size.
+func bitMask(idx, size int) string {
+	return fmt.Sprintf("0x%0*x", size/4, uint64(1)<<idx)
+}
+
+// markdownCell escapes s to be used as a cell of a markdown table.
+func markdownCell(s string) string {
+	return strings.ReplaceAll(s, "|", `\|`)
+}
+
+// snakeCase converts an identifier like "MaxOptions" or "HTTPServer" to
+// its snake case form, like "max_options" or "http_server".
+func snakeCase(name string) string {
+	runes := []rune(name)
+	var sb strings.Builder
+	for i, r := range runes {
+		if unicode.IsUpper(r) {
+			// Start a new word at a lower-to-upper boundary ("maxOptions"),
+			// or at the last upper char of an acronym ("HTTPServer").
+			if i > 0 && (!unicode.IsUpper(runes[i-1]) ||
+				i+1 < len(runes) && unicode.IsLower(runes[i+1])) && runes[i-1] != '_' {
+				sb.WriteByte('_')
+			}
+			r = unicode.ToLower(r)
+		}
+		sb.WriteRune(r)
+	}
+	return sb.String()
+}
+
 // TODO: what happens if there's nothing left after applying both trimmings?
 func flagName(fieldName, trimPrefix, trimSuffix string) string {
 	fn := []byte(fieldName)
@@ -59,3 +138,16 @@ func flagSize(numFields int) int {
 		return numFields
 	}
 }
+
+// hasRules reports whether any rules are declared for the flag values.
+func hasRules(flagValues []flagValue, flagGroups []flagGroup) bool {
+	if len(flagGroups) > 0 {
+		return true
+	}
+	for _, fv := range flagValues {
+		if len(fv.Requires) > 0 || len(fv.Excludes) > 0 {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,106 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+)
+
+// hookModel is the model of a generated type, passed as JSON to the -hook
+// command, on its standard input.
+type hookModel struct {
+	Package    string           `json:"package"`
+	SourceType string           `json:"sourceType"`
+	OutType    string           `json:"outType"`
+	Size       int              `json:"size"`
+	Raw        bool             `json:"raw"`
+	OutFile    string           `json:"outFile"` // the base name of the output file.
+	Flags      []hookModelFlag  `json:"flags"`
+	Groups     []hookModelGroup `json:"groups,omitempty"`
+}
+
+// hookModelFlag is a single flag of a hookModel, ordered by its bit index.
+type hookModelFlag struct {
+	Field    string   `json:"field"`
+	Flag     string   `json:"flag"`
+	Index    int      `json:"index"`
+	Doc      string   `json:"doc,omitempty"`
+	Getter   string   `json:"getter"`
+	Setter   string   `json:"setter"`
+	Resetter string   `json:"resetter"`
+	SetterTo string   `json:"setterTo"`
+	Toggler  string   `json:"toggler"`
+	Requires []string `json:"requires,omitempty"` // the names of the flags.
+	Excludes []string `json:"excludes,omitempty"` // the names of the flags.
+}
+
+// hookModelGroup is a group of flags of a hookModel, at most one of which can be
+// set.
+type hookModelGroup struct {
+	Name  string   `json:"name"`
+	Flags []string `json:"flags"` // the names of the flags.
+}
+
+// newHookModel returns the model of the generated type of in, written to
+// outFileName, in package pkgName.
+func newHookModel(pkgName, outFileName string, in templateTypeInput) hookModel {
+	names := func(fvs []flagValue) []string {
+		var names []string
+		for _, fv := range fvs {
+			names = append(names, fv.Flag)
+		}
+		return names
+	}
+
+	model := hookModel{
+		Package:    pkgName,
+		SourceType: in.SourceTypeName,
+		OutType:    in.OutTypeName,
+		Size:       in.OutTypeSize,
+		Raw:        in.Raw,
+		OutFile:    filepath.Base(outFileName),
+		Flags:      make([]hookModelFlag, len(in.FlagValues)),
+	}
+	for i, fv := range in.FlagValues {
+		model.Flags[i] = hookModelFlag{
+			Field:    fv.Field,
+			Flag:     fv.Flag,
+			Index:    i,
+			Doc:      fv.Doc,
+			Getter:   fv.Getter,
+			Setter:   fv.Setter,
+			Resetter: fv.Resetter,
+			SetterTo: fv.SetterTo,
+			Toggler:  fv.Toggler,
+			Requires: names(fv.Requires),
+			Excludes: names(fv.Excludes),
+		}
+	}
+	for _, group := range in.FlagGroups {
+		model.Groups = append(model.Groups, hookModelGroup{Name: group.Name, Flags: names(group.Flags)})
+	}
+	return model
+}
+
+// runHook runs the hook command in dir, with the model as JSON on its
+// standard input, so it can write additional files for the generated type.
+// Its output is forwarded to the output of the generator.
+func runHook(command []string, dir string, model hookModel) error {
+	data, err := json.MarshalIndent(model, "", "\t")
+	if err != nil {
+		return err
+	}
+
+	cmd := exec.Command(command[0], command[1:]...)
+	cmd.Dir = dir
+	cmd.Stdin = bytes.NewReader(append(data, '\n'))
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("hook %q for type %s: %s", command, model.OutType, err)
+	}
+	return nil
+}
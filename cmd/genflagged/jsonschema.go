@@ -0,0 +1,52 @@
+package main
+
+import (
+	"encoding/json"
+	"strconv"
+	"strings"
+)
+
+// jsonSchemaDialect is the JSON Schema dialect of the generated schemas,
+// which OpenAPI 3.1 schema objects are compatible with.
+const jsonSchemaDialect = "https://json-schema.org/draft/2020-12/schema"
+
+// jsonSchema returns a JSON Schema of the object form of the flags of the
+// generated type, as returned by its ToMap method, and accepted by its
+// FromMap method, which has a boolean property for each flag, keyed by
+// its name, in the order of their bit indexes, as a Go string literal.
+func jsonSchema(outTypeName string, flagValues []flagValue) string {
+	var sb strings.Builder
+	sb.WriteString("{\n")
+	sb.WriteString("\t\"$schema\": " + jsonString(jsonSchemaDialect) + ",\n")
+	sb.WriteString("\t\"title\": " + jsonString(outTypeName) + ",\n")
+	sb.WriteString("\t\"type\": \"object\",\n")
+	sb.WriteString("\t\"properties\": {\n")
+	for i, fv := range flagValues {
+		sb.WriteString("\t\t" + jsonString(fv.Flag) + ": {\"type\": \"boolean\"")
+		if fv.Doc != "" {
+			sb.WriteString(", \"description\": " + jsonString(fv.Doc))
+		}
+		sb.WriteString("}")
+		if i < len(flagValues)-1 {
+			sb.WriteByte(',')
+		}
+		sb.WriteByte('\n')
+	}
+	sb.WriteString("\t},\n")
+	sb.WriteString("\t\"additionalProperties\": false\n")
+	sb.WriteString("}")
+
+	// Use a raw string literal, so the schema is readable in the generated
+	// code, unless it can't represent it.
+	schema := sb.String()
+	if strings.Contains(schema, "`") {
+		return strconv.Quote(schema)
+	}
+	return "`" + schema + "`"
+}
+
+// jsonString returns s as a JSON string.
+func jsonString(s string) string {
+	b, _ := json.Marshal(s)
+	return string(b)
+}
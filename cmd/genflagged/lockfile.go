@@ -0,0 +1,101 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"slices"
+)
+
+// schemaLock is the content of the -lockFile, recording the layouts of the
+// flags of each generated type, across generations, so values serialized
+// with older layouts can still be decoded.
+type schemaLock struct {
+	// Types maps each generated type, qualified by its package name, to its
+	// layouts, ordered by their versions, starting from version 1.
+	// Each layout is the names of the flags, ordered by their bit indexes.
+	Types map[string][][]string `json:"types"`
+}
+
+// maxSchemaVersion is the maximum number of layouts of a type, since the
+// version is encoded as a single byte.
+const maxSchemaVersion = 255
+
+// loadSchemaLock reads the lock file at path, or returns an empty one if it
+// doesn't exist yet.
+func loadSchemaLock(path string) (*schemaLock, error) {
+	lock := &schemaLock{Types: make(map[string][][]string)}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return lock, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, lock); err != nil {
+		return nil, fmt.Errorf("invalid lock file %s: %s", path, err)
+	}
+	if lock.Types == nil {
+		lock.Types = make(map[string][][]string)
+	}
+	return lock, nil
+}
+
+// save writes the lock file to path.
+func (l *schemaLock) save(path string) error {
+	data, err := json.MarshalIndent(l, "", "\t")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0644)
+}
+
+// schemaVersions returns the versions of the layouts of the type, adding
+// the current layout of flagValues as a new version if it's different from
+// the latest one recorded.
+func (l *schemaLock) schemaVersions(key string, flagValues []flagValue) ([]schemaVersion, error) {
+	current := make([]string, len(flagValues))
+	for i, fv := range flagValues {
+		current[i] = fv.Flag
+	}
+
+	layouts := l.Types[key]
+	if len(layouts) == 0 || !slices.Equal(layouts[len(layouts)-1], current) {
+		if len(layouts) == maxSchemaVersion {
+			return nil, fmt.Errorf("type %s has more than %d schema versions", key, maxSchemaVersion)
+		}
+		layouts = append(layouts, current)
+		l.Types[key] = layouts
+	}
+
+	versions := make([]schemaVersion, len(layouts))
+	for i, layout := range layouts {
+		v := schemaVersion{Version: i + 1}
+		for idx, name := range layout {
+			j := slices.IndexFunc(flagValues, func(fv flagValue) bool { return fv.Flag == name })
+			if j < 0 {
+				// The flag was removed since, so it's dropped when decoded.
+				continue
+			}
+			v.Flags = append(v.Flags, schemaFlag{Index: idx, Flag: flagValues[j]})
+		}
+		versions[i] = v
+	}
+	return versions, nil
+}
+
+// schemaVersion is a single layout of the flags of a type.
+type schemaVersion struct {
+	Version int
+	// Flags are the flags of the layout that still exist in the current
+	// layout.
+	Flags []schemaFlag
+}
+
+// schemaFlag is a flag of a layout, mapped to the current layout by name.
+type schemaFlag struct {
+	Index int       // the bit index of the flag in the layout.
+	Flag  flagValue // the flag in the current layout.
+}
@@ -0,0 +1,166 @@
+package main
+
+import (
+	"fmt"
+	"go/token"
+	"slices"
+	"strconv"
+	"strings"
+	"text/template"
+)
+
+// Default templates of the names of the methods generated for each flag.
+const (
+	defaultGetterName   = "Is{{.Flag}}"
+	defaultSetterName   = "Set{{.Flag}}"
+	defaultResetterName = "Reset{{.Flag}}"
+	defaultSetterToName = "Set{{.Flag}}To"
+	defaultTogglerName  = "Toggle{{.Flag}}"
+)
+
+// methodNames holds the templates of the names of the 5 methods generated
+// for each flag, which are executed with the flag's [flagValue].
+type methodNames struct {
+	getter   *template.Template
+	setter   *template.Template
+	resetter *template.Template
+	setterTo *template.Template
+	toggler  *template.Template
+}
+
+// parseMethodNames parses the templates of the method names, which must
+// reference the flag name, or the field name, so each flag gets different
+// method names.
+func parseMethodNames(getter, setter, resetter, setterTo, toggler string) (methodNames, error) {
+	var names methodNames
+	for _, n := range []struct {
+		kind string
+		text string
+		tmpl **template.Template
+	}{
+		{"getter", getter, &names.getter},
+		{"setter", setter, &names.setter},
+		{"resetter", resetter, &names.resetter},
+		{"setterTo", setterTo, &names.setterTo},
+		{"toggler", toggler, &names.toggler},
+	} {
+		if !strings.Contains(n.text, ".Flag") && !strings.Contains(n.text, ".Field") {
+			return names, fmt.Errorf("%s name %q doesn't reference {{.Flag}} or {{.Field}}", n.kind, n.text)
+		}
+		tmpl, err := template.New(n.kind).Parse(n.text)
+		if err != nil {
+			return names, fmt.Errorf("invalid %s name %q: %s", n.kind, n.text, err)
+		}
+		*n.tmpl = tmpl
+	}
+	return names, nil
+}
+
+// setMethodNames sets the names of the methods of each flag value, and makes
+// sure they are valid identifiers, which don't collide with each other.
+// The collisions with the other methods of the generated type are checked
+// by [checkReservedMethodNames], once the enabled features are known.
+func (names methodNames) setMethodNames(flagValues []flagValue) error {
+	seen := make(map[string]string, 5*len(flagValues))
+	for i := range flagValues {
+		fv := &flagValues[i]
+		for _, n := range []struct {
+			tmpl *template.Template
+			name *string
+		}{
+			{names.getter, &fv.Getter},
+			{names.setter, &fv.Setter},
+			{names.resetter, &fv.Resetter},
+			{names.setterTo, &fv.SetterTo},
+			{names.toggler, &fv.Toggler},
+		} {
+			// The elements of bool array fields are referenced as the field
+			// name followed by the index, like the flag name.
+			data := *fv
+			if data.Array != "" {
+				data.Field = data.Array + strconv.Itoa(data.Index)
+			}
+			var sb strings.Builder
+			if err := n.tmpl.Execute(&sb, data); err != nil {
+				return fmt.Errorf("failed to generate %s name of field %s: %s", n.tmpl.Name(), fv.Field, err)
+			}
+			name := sb.String()
+			if !token.IsIdentifier(name) {
+				return fmt.Errorf("invalid %s name %q of field %s", n.tmpl.Name(), name, fv.Field)
+			}
+			if other, ok := seen[name]; ok {
+				return fmt.Errorf("%s name %s of field %s collides with a method of %s", n.tmpl.Name(), name, fv.Field, other)
+			}
+			seen[name] = "field " + fv.Field
+			*n.name = name
+		}
+	}
+	return nil
+}
+
+// checkReservedMethodNames makes sure the names of the methods of each flag
+// value don't collide with the reserved names of the other methods of the
+// generated type.
+func checkReservedMethodNames(flagValues []flagValue, reserved []string) error {
+	for _, fv := range flagValues {
+		for _, n := range []struct {
+			kind string
+			name string
+		}{
+			{"getter", fv.Getter},
+			{"setter", fv.Setter},
+			{"resetter", fv.Resetter},
+			{"setterTo", fv.SetterTo},
+			{"toggler", fv.Toggler},
+		} {
+			if slices.Contains(reserved, n.name) {
+				return fmt.Errorf("%s name %s of field %s collides with a method of the generated type", n.kind, n.name, fv.Field)
+			}
+		}
+	}
+	return nil
+}
+
+// methodFeatures are the features that decide which methods, other than
+// the per-flag ones, the generated type has.
+type methodFeatures struct {
+	raw        bool // -raw, which drops the flagged.BitFlags methods.
+	versioned  bool // -lockFile, which adds MarshalBinary and UnmarshalBinary.
+	rules      bool // rules declared on the fields, which add Validate.
+	prometheus bool // -prometheus, which adds Collector.
+	proto      bool // -proto for the type, which adds ToProto and FromProto.
+}
+
+// reservedMethodNames returns the names of the methods of the generated type,
+// which aren't generated per flag, with the given features.
+// It's checked against the methods in the goldens by TestReservedMethodNames,
+// so it has to be updated with each method added to the template.
+func reservedMethodNames(ft methodFeatures) []string {
+	names := []string{
+		"Clone", "CopyFrom", "TypedFlags", "SetTypedFlags", "ToMap", "FromMap",
+		"IsNamed", "SetNamedTo", "Name", "IndexOf", "AllDefinedSet", "AnyDefinedSet",
+		"Equal", "Hash", "AppendString", "GoString",
+	}
+	if !ft.raw {
+		// The methods of the flagged.BitFlags interface.
+		names = append(names,
+			"BitFlags", "Is", "Set", "Reset", "SetTo", "Toggle", "SetAll", "ResetAll",
+			"AnySet", "AllSet", "AnyOf", "AllOf", "Size", "String", "PrettyString",
+			"CountSet", "Bits", "SetBits", "Len", "TrailingZeros", "LeadingZeros", "CloneBitFlags",
+			"Uint64", "SetUint64",
+		)
+	}
+	if ft.versioned {
+		names = append(names, "MarshalBinary", "UnmarshalBinary")
+	}
+	if ft.rules {
+		names = append(names, "Validate")
+	}
+	if ft.prometheus {
+		names = append(names, "Collector")
+	}
+	if ft.proto {
+		names = append(names, "ToProto", "FromProto")
+	}
+	return names
+}
@@ -0,0 +1,168 @@
+// Package bitindex defines an Analyzer that reports constant bit indexes,
+// which are out of the range of the flags they are passed to.
+//
+// # Analyzer bitindex
+//
+// bitindex: report out-of-range constant bit indexes
+//
+// The methods of the github.com/asmsh/flagged bit flags types, and the ones
+// generated by genflagged, take bit indexes of type flagged.BitIndex, which
+// must be within the bit width of the flags, or else they panic at run time.
+// This analyzer reports the calls passing constant bit indexes that are
+// negative, or not less than the bit width of the receiver's type, or 64 if
+// the receiver is an interface, like flagged.BitFlags.
+// The bounds of the bit ranges, passed as the from and to parameters, like
+// the ones of SetRange, can also be equal to the bit width, as the ranges
+// exclude their upper bound.
+package bitindex
+
+import (
+	"go/ast"
+	"go/constant"
+	"go/types"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/passes/inspect"
+	"golang.org/x/tools/go/ast/inspector"
+	"golang.org/x/tools/go/types/typeutil"
+)
+
+const Doc = `report out-of-range constant bit indexes
+
+The methods of the github.com/asmsh/flagged bit flags types, and the ones
+generated by genflagged, take bit indexes of type flagged.BitIndex, which
+must be within the bit width of the flags, or else they panic at run time.
+This analyzer reports the calls passing constant bit indexes that are
+negative, or not less than the bit width of the receiver's type, or 64 if
+the receiver is an interface, like flagged.BitFlags.
+The bounds of the bit ranges, passed as the from and to parameters, like
+the ones of SetRange, can also be equal to the bit width, as the ranges
+exclude their upper bound.`
+
+var Analyzer = &analysis.Analyzer{
+	Name:     "bitindex",
+	Doc:      Doc,
+	URL:      "https://pkg.go.dev/github.com/asmsh/flagged/cmd/genflagged/passes/bitindex",
+	Requires: []*analysis.Analyzer{inspect.Analyzer},
+	Run:      run,
+}
+
+const (
+	flaggedPath  = "github.com/asmsh/flagged"
+	bitIndexName = "BitIndex"
+
+	// maxSize is the bit width of the widest flags, used as the limit when
+	// the receiver is an interface.
+	maxSize = 64
+)
+
+func run(pass *analysis.Pass) (any, error) {
+	inspect := pass.ResultOf[inspect.Analyzer].(*inspector.Inspector)
+
+	// Qualify the types of other packages by their names only.
+	qualifier := func(pkg *types.Package) string {
+		if pkg == pass.Pkg {
+			return ""
+		}
+		return pkg.Name()
+	}
+
+	nodeFilter := []ast.Node{
+		(*ast.CallExpr)(nil),
+	}
+	inspect.Preorder(nodeFilter, func(n ast.Node) {
+		call := n.(*ast.CallExpr)
+		fn, ok := typeutil.Callee(pass.TypesInfo, call).(*types.Func)
+		if !ok {
+			return
+		}
+		sig := fn.Signature()
+		if sig.Recv() == nil {
+			return
+		}
+		size, ok := flagsSize(sig.Recv().Type())
+		if !ok {
+			return
+		}
+
+		params := sig.Params()
+		for i, arg := range call.Args {
+			var param *types.Var
+			var paramType types.Type
+			switch {
+			case sig.Variadic() && i >= params.Len()-1:
+				if call.Ellipsis.IsValid() {
+					// The indexes are passed as a slice.
+					return
+				}
+				param = params.At(params.Len() - 1)
+				paramType = param.Type().(*types.Slice).Elem()
+			case i < params.Len():
+				param = params.At(i)
+				paramType = param.Type()
+			default:
+				return
+			}
+			if !isBitIndex(paramType) {
+				continue
+			}
+
+			tv, ok := pass.TypesInfo.Types[arg]
+			if !ok || tv.Value == nil || tv.Value.Kind() != constant.Int {
+				continue
+			}
+			idx, exact := constant.Int64Val(tv.Value)
+			if isRangeBound(param) {
+				if !exact || idx < 0 || idx > int64(size) {
+					pass.ReportRangef(arg, "bit range bound %s out of range [0, %d] of %s", tv.Value, size, types.TypeString(sig.Recv().Type(), qualifier))
+				}
+				continue
+			}
+			if !exact || idx < 0 || idx >= int64(size) {
+				pass.ReportRangef(arg, "bit index %s out of range [0, %d) of %s", tv.Value, size, types.TypeString(sig.Recv().Type(), qualifier))
+			}
+		}
+	})
+	return nil, nil
+}
+
+// isBitIndex reports whether t is the flagged.BitIndex alias.
+func isBitIndex(t types.Type) bool {
+	alias, ok := t.(*types.Alias)
+	if !ok {
+		return false
+	}
+	obj := alias.Obj()
+	return obj.Name() == bitIndexName && obj.Pkg() != nil && obj.Pkg().Path() == flaggedPath
+}
+
+// isRangeBound reports whether param is a bound of a bit range, like the
+// from and to parameters of SetRange, which can be equal to the bit width.
+func isRangeBound(param *types.Var) bool {
+	return param.Name() == "from" || param.Name() == "to"
+}
+
+// flagsSize returns the bit width of the flags of the receiver type t,
+// which is the width of its underlying unsigned integer type, or maxSize
+// if it's an interface.
+func flagsSize(t types.Type) (int, bool) {
+	if ptr, ok := types.Unalias(t).(*types.Pointer); ok {
+		t = ptr.Elem()
+	}
+	switch u := t.Underlying().(type) {
+	case *types.Interface:
+		return maxSize, true
+	case *types.Basic:
+		switch u.Kind() {
+		case types.Uint8:
+			return 8, true
+		case types.Uint16:
+			return 16, true
+		case types.Uint32:
+			return 32, true
+		case types.Uint64:
+			return 64, true
+		}
+	}
+	return 0, false
+}
@@ -0,0 +1,13 @@
+package bitindex_test
+
+import (
+	"testing"
+
+	"github.com/asmsh/flagged/cmd/genflagged/passes/bitindex"
+	"golang.org/x/tools/go/analysis/analysistest"
+)
+
+func TestAnalyzer(t *testing.T) {
+	testdata := analysistest.TestData()
+	analysistest.Run(t, testdata, bitindex.Analyzer, "a")
+}
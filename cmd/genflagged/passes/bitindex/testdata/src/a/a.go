@@ -0,0 +1,58 @@
+package a
+
+import "github.com/asmsh/flagged"
+
+// PermissionsBitFlags is like a type generated by genflagged.
+type PermissionsBitFlags flagged.BitFlags8
+
+func (f *PermissionsBitFlags) Is(idx flagged.BitIndex) (set bool) { return false }
+func (f *PermissionsBitFlags) Name(idx flagged.BitIndex) string   { return "" }
+
+// rawBitFlags is like a type generated by genflagged in raw mode, which
+// takes plain ints, so it's not checked.
+type rawBitFlags uint8
+
+func (f *rawBitFlags) Name(idx int) string { return "" }
+
+const (
+	first flagged.BitIndex = 0
+	last  flagged.BitIndex = 7
+)
+
+func calls(idx flagged.BitIndex) {
+	var f8 flagged.BitFlags8
+	f8.Is(0)
+	f8.Is(last)
+	f8.Is(idx)
+	f8.Is(8)                // want `bit index 8 out of range \[0, 8\) of \*flagged.BitFlags8`
+	f8.Is(last + 1)         // want `bit index 8 out of range`
+	f8.Is(-1)               // want `bit index -1 out of range`
+	f8.AnyOf(first, 9, idx) // want `bit index 9 out of range`
+	f8.AnyOf([]flagged.BitIndex{9}...)
+
+	// The upper bound of the ranges is exclusive, so it can be the size.
+	f8.SetRange(0, 8)
+	f8.SetRange(8, 8)
+	_ = f8.IsRangeSet(0, 8)
+	_ = f8.CountRange(4, 8)
+	f8.SetRange(0, 9)            // want `bit range bound 9 out of range \[0, 8\] of \*flagged.BitFlags8`
+	f8.SetRange(-1, 4)           // want `bit range bound -1 out of range`
+	_ = f8.IsRangeSet(9, 9)      // want `bit range bound 9 out of range` `bit range bound 9 out of range`
+	_ = f8.CountRange(0, last+2) // want `bit range bound 9 out of range`
+
+	var f64 flagged.BitFlags64
+	f64.Is(63)
+	f64.Is(64) // want `bit index 64 out of range \[0, 64\)`
+
+	var bf flagged.BitFlags = &f8
+	bf.Is(63)
+	bf.Is(64) // want `bit index 64 out of range \[0, 64\) of flagged.BitFlags`
+
+	var p PermissionsBitFlags
+	p.Is(7)
+	p.Is(8)    // want `bit index 8 out of range \[0, 8\) of \*PermissionsBitFlags`
+	p.Name(10) // want `bit index 10 out of range`
+
+	var r rawBitFlags
+	r.Name(10)
+}
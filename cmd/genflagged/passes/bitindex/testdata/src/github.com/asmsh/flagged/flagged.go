@@ -0,0 +1,22 @@
+// Package flagged is a minimal stand-in for github.com/asmsh/flagged.
+package flagged
+
+type BitIndex = int
+
+type BitFlags interface {
+	Is(idx BitIndex) (set bool)
+	AnyOf(idx ...BitIndex) bool
+}
+
+type BitFlags8 uint8
+
+func (f *BitFlags8) Is(idx BitIndex) (set bool)       { return *f&(1<<idx) != 0 }
+func (f *BitFlags8) AnyOf(idx ...BitIndex) bool       { return false }
+func (f *BitFlags8) SetRange(from, to BitIndex)       {}
+func (f BitFlags8) IsRangeSet(from, to BitIndex) bool { return false }
+func (f BitFlags8) CountRange(from, to BitIndex) int  { return 0 }
+
+type BitFlags64 uint64
+
+func (f *BitFlags64) Is(idx BitIndex) (set bool) { return *f&(1<<idx) != 0 }
+func (f *BitFlags64) AnyOf(idx ...BitIndex) bool { return false }
@@ -0,0 +1,186 @@
+// Package stale defines an Analyzer that reports the types generated by
+// genflagged, which are out of date with their source struct types.
+//
+// # Analyzer stale
+//
+// stale: report types generated by genflagged that need regeneration
+//
+// The types generated by genflagged have a flag for each bool field of
+// their source struct types, at the time they were generated.
+// This analyzer compares the bool fields of each source struct type with the
+// flags of its generated type, in the same package, and reports when they
+// don't match, like when a bool field is added or moved, so
+// forgetting to run go generate is caught at vet time.
+package stale
+
+import (
+	"go/ast"
+	"go/types"
+	"slices"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+)
+
+const Doc = `report types generated by genflagged that need regeneration
+
+The types generated by genflagged have a flag for each bool field of
+their source struct types, at the time they were generated.
+This analyzer compares the bool fields of each source struct type with the
+flags of its generated type, in the same package, and reports when they
+don't match, like when a bool field is added or moved, so
+forgetting to run go generate is caught at vet time.`
+
+var Analyzer = &analysis.Analyzer{
+	Name: "stale",
+	Doc:  Doc,
+	URL:  "https://pkg.go.dev/github.com/asmsh/flagged/cmd/genflagged/passes/stale",
+	Run:  run,
+}
+
+// generatorName is the name of the generator in the header of the
+// generated files.
+const generatorName = "genflagged"
+
+func run(pass *analysis.Pass) (any, error) {
+	for _, file := range pass.Files {
+		if !isGeneratedByGenflagged(file) {
+			continue
+		}
+		for _, decl := range file.Decls {
+			fn, ok := decl.(*ast.FuncDecl)
+			if !ok || fn.Recv == nil || fn.Name.Name != "TypedFlags" || fn.Body == nil {
+				continue
+			}
+			checkTypedFlags(pass, fn)
+		}
+	}
+	return nil, nil
+}
+
+// checkTypedFlags compares the fields set by a generated TypedFlags method,
+// which are the fields the type was generated for, with the current bool
+// fields of the struct type it returns.
+func checkTypedFlags(pass *analysis.Pass, fn *ast.FuncDecl) {
+	obj, ok := pass.TypesInfo.Defs[fn.Name].(*types.Func)
+	if !ok {
+		return
+	}
+	sig := obj.Signature()
+	if sig.Results().Len() != 1 {
+		return
+	}
+	source, ok := types.Unalias(sig.Results().At(0).Type()).(*types.Named)
+	if !ok || source.Obj().Pkg() != pass.Pkg {
+		return
+	}
+	st, ok := source.Underlying().(*types.Struct)
+	if !ok {
+		return
+	}
+
+	generated := typedFlagsFields(fn)
+	if generated == nil {
+		return
+	}
+	current := boolFields(st)
+	if slices.Equal(generated, current) {
+		return
+	}
+
+	var problems []string
+	for _, field := range current {
+		if !slices.Contains(generated, field) {
+			problems = append(problems, "field "+field+" was added")
+		}
+	}
+	// The removed fields fail the build, as the generated code references
+	// them, so there's nothing to report for them.
+	if len(problems) == 0 {
+		problems = append(problems, "fields were reordered")
+	}
+
+	recv := types.TypeString(sig.Recv().Type(), types.RelativeTo(pass.Pkg))
+	pass.Reportf(
+		source.Obj().Pos(),
+		"generated type %s is out of date with %s: %s; regenerate it with %s",
+		strings.TrimPrefix(recv, "*"),
+		source.Obj().Name(),
+		strings.Join(problems, ", "),
+		generatorName,
+	)
+}
+
+// typedFlagsFields returns the names of the fields set by the composite
+// literal returned by a generated TypedFlags method, in order, or nil if
+// it's not in the generated form.
+func typedFlagsFields(fn *ast.FuncDecl) []string {
+	if len(fn.Body.List) != 1 {
+		return nil
+	}
+	ret, ok := fn.Body.List[0].(*ast.ReturnStmt)
+	if !ok || len(ret.Results) != 1 {
+		return nil
+	}
+	lit, ok := ret.Results[0].(*ast.CompositeLit)
+	if !ok {
+		return nil
+	}
+
+	fields := []string{}
+	for _, elt := range lit.Elts {
+		kv, ok := elt.(*ast.KeyValueExpr)
+		if !ok {
+			return nil
+		}
+		key, ok := kv.Key.(*ast.Ident)
+		if !ok {
+			return nil
+		}
+		fields = append(fields, key.Name)
+	}
+	return fields
+}
+
+// boolFields returns the names of the fields of st that genflagged
+// generates flags for, in order, which are the non-embedded fields, not
+// named '_', with a bool type or an alias to it, or an array of them.
+func boolFields(st *types.Struct) []string {
+	fields := []string{}
+	for i := range st.NumFields() {
+		field := st.Field(i)
+		if field.Embedded() || field.Name() == "_" {
+			continue
+		}
+		typ := types.Unalias(field.Type())
+		// The bool array fields are set as a whole by TypedFlags, and a
+		// change of their length fails the build, like the removed fields.
+		if array, ok := typ.(*types.Array); ok {
+			typ = types.Unalias(array.Elem())
+		}
+		basic, ok := typ.(*types.Basic)
+		if !ok || basic.Info()&types.IsBoolean == 0 {
+			continue
+		}
+		fields = append(fields, field.Name())
+	}
+	return fields
+}
+
+// isGeneratedByGenflagged reports whether file was generated by genflagged.
+func isGeneratedByGenflagged(file *ast.File) bool {
+	if !ast.IsGenerated(file) {
+		return false
+	}
+	for _, group := range file.Comments {
+		if group.Pos() >= file.Package {
+			break
+		}
+		for _, c := range group.List {
+			if strings.HasPrefix(c.Text, "// Code generated by \""+generatorName+" ") {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,13 @@
+package stale_test
+
+import (
+	"testing"
+
+	"github.com/asmsh/flagged/cmd/genflagged/passes/stale"
+	"golang.org/x/tools/go/analysis/analysistest"
+)
+
+func TestAnalyzer(t *testing.T) {
+	testdata := analysistest.TestData()
+	analysistest.Run(t, testdata, stale.Analyzer, "a")
+}
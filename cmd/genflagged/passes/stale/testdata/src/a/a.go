@@ -0,0 +1,29 @@
+package a
+
+type alias = bool
+
+// UpToDate has the same fields it was generated for.
+type UpToDate struct {
+	Read  bool
+	Write alias
+	Name  string
+	_     bool
+	Bits  [2]bool
+}
+
+type Added struct { // want `generated type AddedBitFlags is out of date with Added: field Exec was added, field Shards was added; regenerate it with genflagged`
+	Read   bool
+	Exec   bool
+	Shards [4]alias
+}
+
+type Reordered struct { // want `generated type ReorderedBitFlags is out of date with Reordered: fields were reordered; regenerate it with genflagged`
+	Write bool
+	Read  bool
+}
+
+// NotGenerated has a TypedFlags method that's not generated, so it's not
+// checked.
+type NotGenerated struct {
+	Read bool
+}
@@ -0,0 +1,7 @@
+package a
+
+type NotGeneratedFlags uint8
+
+func (f *NotGeneratedFlags) TypedFlags() NotGenerated {
+	return NotGenerated{}
+}
@@ -0,0 +1,126 @@
+package main
+
+import (
+	"fmt"
+	"go/ast"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// rulesTagKey is the struct tag key the rules of each bool field are
+// declared with, like `flagged:"requires=Write,excludes=Exec,group=mode"`.
+const rulesTagKey = "flagged"
+
+// fieldRules are the rules declared on a single bool field, referencing
+// other bool fields of the same type by their names.
+type fieldRules struct {
+	requires []string // fields that must be set when this one is set.
+	excludes []string // fields that mustn't be set when this one is set.
+	group    string   // group of fields, at most one of which can be set.
+}
+
+// flagGroup is a group of flags, at most one of which can be set.
+type flagGroup struct {
+	Name  string
+	Flags []flagValue
+}
+
+// parseFieldRules parses the rules declared in the struct tag of a field,
+// if any.
+// The rules are a comma-separated list of key=value pairs, where the
+// requires and excludes keys can be repeated.
+func parseFieldRules(tag *ast.BasicLit) (fieldRules, error) {
+	var rules fieldRules
+	if tag == nil {
+		return rules, nil
+	}
+	tagValue, err := strconv.Unquote(tag.Value)
+	if err != nil {
+		return rules, fmt.Errorf("invalid struct tag %s: %s", tag.Value, err)
+	}
+	value, ok := reflect.StructTag(tagValue).Lookup(rulesTagKey)
+	if !ok {
+		return rules, nil
+	}
+
+	for _, rule := range strings.Split(value, ",") {
+		key, name, ok := strings.Cut(strings.TrimSpace(rule), "=")
+		if !ok || name == "" {
+			return rules, fmt.Errorf("invalid rule %q; want key=value", rule)
+		}
+		switch key {
+		case "requires":
+			rules.requires = append(rules.requires, name)
+		case "excludes":
+			rules.excludes = append(rules.excludes, name)
+		case "group":
+			if rules.group != "" {
+				return rules, fmt.Errorf("invalid rule %q; field already in group %q", rule, rules.group)
+			}
+			rules.group = name
+		default:
+			return rules, fmt.Errorf("invalid rule %q; supported keys are requires, excludes and group", rule)
+		}
+	}
+	return rules, nil
+}
+
+// resolveRules sets the Requires and Excludes of each flag value, based on
+// the rules declared on its field, keyed by the field name, and returns the
+// declared groups, in the order they first appear.
+// It returns an error if a rule references a field that's not a flag of the
+// same type, or if a group has a single flag.
+func resolveRules(flagValues []flagValue, rules map[string]fieldRules) ([]flagGroup, error) {
+	byField := make(map[string]flagValue, len(flagValues))
+	for _, fv := range flagValues {
+		byField[fv.Field] = fv
+	}
+	resolve := func(field, rule, ref string) (flagValue, error) {
+		refFV, ok := byField[ref]
+		if !ok {
+			return flagValue{}, fmt.Errorf("rule %s=%s of field %s references an unknown bool field", rule, ref, field)
+		}
+		if ref == field {
+			return flagValue{}, fmt.Errorf("rule %s=%s of field %s references the field itself", rule, ref, field)
+		}
+		return refFV, nil
+	}
+
+	var groups []flagGroup
+	for i, fv := range flagValues {
+		fr := rules[fv.Field]
+		for _, ref := range fr.requires {
+			refFV, err := resolve(fv.Field, "requires", ref)
+			if err != nil {
+				return nil, err
+			}
+			flagValues[i].Requires = append(flagValues[i].Requires, refFV)
+		}
+		for _, ref := range fr.excludes {
+			refFV, err := resolve(fv.Field, "excludes", ref)
+			if err != nil {
+				return nil, err
+			}
+			flagValues[i].Excludes = append(flagValues[i].Excludes, refFV)
+		}
+
+		if fr.group == "" {
+			continue
+		}
+		idx := slices.IndexFunc(groups, func(g flagGroup) bool { return g.Name == fr.group })
+		if idx < 0 {
+			groups = append(groups, flagGroup{Name: fr.group})
+			idx = len(groups) - 1
+		}
+		groups[idx].Flags = append(groups[idx].Flags, fv)
+	}
+
+	for _, g := range groups {
+		if len(g.Flags) == 1 {
+			return nil, fmt.Errorf("group %s has a single field %s", g.Name, g.Flags[0].Field)
+		}
+	}
+	return groups, nil
+}
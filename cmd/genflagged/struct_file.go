@@ -1,10 +1,12 @@
 package main
 
 import (
+	"fmt"
 	"go/ast"
 	"go/token"
 	"go/types"
 	"log"
+	"strconv"
 )
 
 func (f *File) isValidStructFile() bool {
@@ -12,8 +14,8 @@ func (f *File) isValidStructFile() bool {
 }
 
 // genStructDecl processes one 'type <name> struct' declaration clause.
-// Its target fields are these whose type is bool or an alias to bool,
-// and aren't embedded fields nor has the name '_'.
+// Its target fields are these whose type is bool or an alias to bool, or an
+// array of them, and aren't embedded fields nor has the name '_'.
 // Note: it doesn't include fields whose type is named bool-based types,
 // as the implementation doesn't support converting to/from bool-based
 // types yet.
@@ -55,6 +57,7 @@ func (f *File) genStructDecl(node ast.Node) bool {
 		// Init the fields list, assuming the struct contains only target fields,
 		// and only one field per declaration.
 		f.flagValues = make([]flagValue, 0, len(stype.Fields.List))
+		rules := make(map[string]fieldRules)
 
 		verbose.Printf(
 			"info: proccessing %d field declarations for type %s\n",
@@ -76,6 +79,17 @@ func (f *File) genStructDecl(node ast.Node) bool {
 				continue
 			}
 
+			// The rules in the field's tag, if any, apply to all its names.
+			fieldRules, err := parseFieldRules(field.Tag)
+			if err != nil {
+				log.Fatalf(
+					"error: invalid rules for field declaration at index %d in type %s: %s",
+					idx,
+					tspec.Name.Name,
+					err,
+				)
+			}
+
 			// Loop over each name in the same field declaration.
 			for _, name := range field.Names {
 				verbose.Printf(
@@ -106,8 +120,14 @@ func (f *File) genStructDecl(node ast.Node) bool {
 				}
 
 				// Get the actual type of the field.
-				// Note: it must be a builtin bool or an alias to one.
+				// Note: it must be a builtin bool or an alias to one, or an
+				// array of them, which is expanded into a flag per element.
 				actualType := types.Unalias(obj.Type())
+				arrayLen := -1
+				if arrayType, ok := actualType.(*types.Array); ok {
+					arrayLen = int(arrayType.Len())
+					actualType = types.Unalias(arrayType.Elem())
+				}
 
 				// Skip named types, as they're not supported.
 				basicType, ok := actualType.(*types.Basic)
@@ -137,21 +157,50 @@ func (f *File) genStructDecl(node ast.Node) bool {
 
 				// TODO: maybe add some validation to make sure the generated types and flags
 				// doesn't already exist in the package, since we have the type info about it.
-				fv := flagValue{
-					Field: name.Name,
-					Flag:  flagName(name.Name, f.pkg.trimPrefix, f.pkg.trimSuffix),
+				if arrayLen < 0 {
+					fv := flagValue{
+						Field: name.Name,
+						Flag:  flagName(name.Name, f.pkg.trimPrefix, f.pkg.trimSuffix),
+						Doc:   fieldDoc(field),
+					}
+					f.flagValues = append(f.flagValues, fv)
+					rules[fv.Field] = fieldRules
+				}
+				// Expand bool array fields into a flag per element, named
+				// after the field followed by the index of the element.
+				for i := range arrayLen {
+					fv := flagValue{
+						Field: fmt.Sprintf("%s[%d]", name.Name, i),
+						Flag:  flagName(name.Name, f.pkg.trimPrefix, f.pkg.trimSuffix) + strconv.Itoa(i),
+						Doc:   fieldDoc(field),
+						Array: name.Name,
+						Index: i,
+						Len:   arrayLen,
+					}
+					f.flagValues = append(f.flagValues, fv)
+					rules[fv.Field] = fieldRules
 				}
-				f.flagValues = append(f.flagValues, fv)
 
 				verbose.Printf(
-					"info: added flag %s for field %s from type %s with total %d flags\n",
-					fv.Flag,
-					fv.Field,
+					"info: added flags for field %s from type %s with total %d flags\n",
+					name.Name,
 					tspec.Name.Name,
 					len(f.flagValues),
 				)
 			}
 		}
+
+		// Name the methods of the flags, now that all of them are known.
+		if err := f.pkg.methodNames.setMethodNames(f.flagValues); err != nil {
+			log.Fatalf("error: invalid method names in type %s: %s", tspec.Name.Name, err)
+		}
+
+		// Resolve the rules, now that all the flags are known.
+		groups, err := resolveRules(f.flagValues, rules)
+		if err != nil {
+			log.Fatalf("error: invalid rules in type %s: %s", tspec.Name.Name, err)
+		}
+		f.flagGroups = groups
 	}
 
 	// Set the flags size based on the number of loaded flag values.
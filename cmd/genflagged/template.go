@@ -1,22 +1,66 @@
 package main
 
+import (
+	"fmt"
+	"go/token"
+	"strings"
+	"text/template"
+)
+
+// templateFuncs are the helper functions available to all the templates.
+var templateFuncs = template.FuncMap{
+	"snake":    snakeCase,
+	"exported": token.IsExported,
+	"mask":     bitMask,
+	"cell":     markdownCell,
+	"add":      func(a, b int) int { return a + b },
+}
+
 type templateHeaderInput struct {
 	CmdArgs     string
 	PackageName string
-	// Raw omits the 'github.com/asmsh/flagged' import for self-contained output.
-	Raw bool
+	// ImportGroups are the packages imported by the generated code, with
+	// the standard library packages first, each group sorted by path.
+	ImportGroups [][]importSpec
+}
+
+type importSpec struct {
+	Name string // optional, empty for the default package name.
+	Path string
 }
 
 type flagValue struct {
 	// Field is the name of the field that Flag is generated from.
-	// exactly as it appears in the SourceTypeName.
+	// exactly as it appears in the SourceTypeName, followed by the index of
+	// the element, like "Shards[0]", if it's a bool array field.
 	Field string
+	// Array is the name of the bool array field that Flag is generated from,
+	// if any, with Index being the index of its element, and Len its length.
+	Array string
+	Index int
+	Len   int
 	// Flag is the name of the flag that will be used to generate the method.
 	// with no _ prefix, and upper case first char.
 	Flag string
+	// Getter, Setter, Resetter, SetterTo and Toggler are the names of the
+	// methods generated for the flag, e.g. "IsRead", "SetRead", "ResetRead",
+	// "SetReadTo" and "ToggleRead", by default.
+	Getter   string
+	Setter   string
+	Resetter string
+	SetterTo string
+	Toggler  string
+	// Doc is the doc comment of the field, or its line comment, joined into
+	// a single line.
+	Doc string
+	// Requires are the flags that must be set when Flag is set.
+	Requires []flagValue
+	// Excludes are the flags that mustn't be set when Flag is set.
+	Excludes []flagValue
 }
 
 type templateTypeInput struct {
+	PackageName      string // the name of the package of the generated type.
 	SourceTypeName   string // the type we're generating flags from.
 	OutTypeName      string
 	OutTypeSize      int    // 8,16,32,64
@@ -29,69 +73,218 @@ type templateTypeInput struct {
 	BitIndexType string
 	// Raw omits the BitFlags method and any reference to the flagged package.
 	Raw bool
+	// With adds the immutable With<field> methods.
+	With bool
+	// ValueReceivers generates the read-only methods on value receivers.
+	ValueReceivers bool
+	// Options adds the functional-options constructor.
+	Options bool
+	// Atomic adds the atomic variant of the generated type.
+	Atomic bool
+	// AtomicUint is the uint type stored by the atomic variant, which is
+	// "uint32" for sizes up to 32, and "uint64" otherwise.
+	AtomicUint string
+	// Safe adds the mutex-guarded variant of the generated type.
+	Safe bool
+	// Slice adds the slice type of the generated type, with bulk methods.
+	Slice bool
+	// Context adds the functions passing the generated type through a context.
+	Context bool
+	// Convert adds the AsBitFlags method to the source type.
+	Convert bool
+	// Tests adds the tests of the generated type to the test file.
+	Tests bool
+	// Benchmarks adds the benchmarks of the generated type to the test file.
+	Benchmarks bool
+	// Fuzz adds the fuzz target of the generated type to the test file.
+	Fuzz bool
+	// Mock adds the mock of the generated interface to the test file.
+	Mock bool
+	// Prometheus adds the Collector method, exporting the flags as gauges.
+	Prometheus bool
+	// ProtoMessage is the package-qualified proto message type to generate
+	// the ToProto and FromProto conversions for, if any.
+	ProtoMessage string
+	// JSONSchema is the JSON Schema of the object form of the flags, as a Go
+	// string literal, if -jsonSchema is set.
+	JSONSchema string
+	// SourceStruct is the struct type the SourceTypeName is defined as, with
+	// no tags, if -guard is set, used to generate a compile-time guard.
+	SourceStruct string
 	// FlagValues are used to generate the fields and flag methods.
 	// They are listed exactly as they appear in the SourceTypeName,
 	// in the same order.
 	FlagValues []flagValue
+	// SchemaVersions are the versions of the layouts of the flags, from the
+	// lock file, if any, with the current layout last, used to generate the
+	// versioned MarshalBinary and UnmarshalBinary methods.
+	SchemaVersions []schemaVersion
+	// FlagGroups are the groups of flags, at most one of which can be set,
+	// in the order they first appear in the SourceTypeName.
+	FlagGroups []flagGroup
+}
+
+// Fields returns the fields of the SourceTypeName that FlagValues are
+// generated from, in the same order, grouping the elements of each bool array
+// field together.
+func (in templateTypeInput) Fields() []sourceField {
+	var fields []sourceField
+	for _, fv := range in.FlagValues {
+		if fv.Array != "" && fv.Index > 0 {
+			last := &fields[len(fields)-1]
+			last.Flags = append(last.Flags, fv)
+			continue
+		}
+		name := fv.Field
+		if fv.Array != "" {
+			name = fv.Array
+		}
+		fields = append(fields, sourceField{Name: name, Flags: []flagValue{fv}})
+	}
+	return fields
+}
+
+// sourceField is a bool field, or a bool array field, of the SourceTypeName,
+// with the flags generated from it, one per element for bool array fields.
+type sourceField struct {
+	Name  string
+	Flags []flagValue
+}
+
+// IsArray reports whether the field is a bool array field.
+func (sf sourceField) IsArray() bool {
+	return sf.Flags[0].Array != ""
+}
+
+// KeyedValue returns the field as a keyed element of a composite literal of
+// the SourceTypeName, with the field, or all its elements, set to v.
+func (sf sourceField) KeyedValue(v string) string {
+	if !sf.IsArray() {
+		return sf.Name + ": " + v
+	}
+	values := make([]string, len(sf.Flags))
+	for i := range values {
+		values[i] = v
+	}
+	return fmt.Sprintf("%s: [%d]bool{%s}", sf.Name, len(sf.Flags), strings.Join(values, ", "))
+}
+
+// KeyedValue returns the field of the flag as a keyed element of a composite
+// literal of the SourceTypeName, with the field, or just its element, set
+// to v.
+func (fv flagValue) KeyedValue(v string) string {
+	if fv.Array == "" {
+		return fv.Field + ": " + v
+	}
+	return fmt.Sprintf("%s: [%d]bool{%d: %s}", fv.Array, fv.Len, fv.Index, v)
+}
+
+// DocLink returns a doc link to the field of the flag, in the source type,
+// followed by the index of its element, if it's a bool array field.
+func (fv flagValue) DocLink(sourceTypeName string) string {
+	if fv.Array == "" {
+		return "[" + sourceTypeName + "." + fv.Field + "]"
+	}
+	return fmt.Sprintf("[%s.%s][%d]", sourceTypeName, fv.Array, fv.Index)
+}
+
+// HasRules reports whether any rules are declared for the flags, so the
+// Validate method is generated.
+func (in templateTypeInput) HasRules() bool {
+	return hasRules(in.FlagValues, in.FlagGroups)
 }
 
 const flaggedHeaderTemplate = `// Code generated by "genflagged {{.CmdArgs}}"; DO NOT EDIT.
 package {{.PackageName}}
-{{if not .Raw}}
-import "github.com/asmsh/flagged"
+{{if and (eq (len .ImportGroups) 1) (eq (len (index .ImportGroups 0)) 1)}}
+{{- with index .ImportGroups 0 0}}
+import {{if .Name}}{{.Name}} {{end}}"{{.Path}}"
+{{end}}
+{{- else if .ImportGroups}}
+import (
+{{- range $i, $group := .ImportGroups}}
+{{- if $i}}
+{{end}}
+{{- range $group}}
+	{{if .Name}}{{.Name}} {{end}}"{{.Path}}"
+{{- end}}
+{{- end}}
+)
 {{end}}`
 
-// flaggedTestHeaderTemplate is the header of the generated _test.go file.
-// It's self-contained and never references the flagged package, so it's
-// identical for both normal and raw output.
-const flaggedTestHeaderTemplate = `// Code generated by "genflagged {{.CmdArgs}}"; DO NOT EDIT.
-package {{.PackageName}}
+type templateCrossConvertInput struct {
+	From templateTypeInput
+	To   templateTypeInput
+	// SharedFlags are the flags of From, which To has flags with the same
+	// names.
+	SharedFlags []flagValue
+}
 
-import (
-	"reflect"
-	"testing"
-)
+// flaggedCrossConvertTemplate generates the conversion from one generated
+// type to another, based on the flag names they share.
+const flaggedCrossConvertTemplate = `
+// To{{.To.OutTypeName}} returns a copy of the current flags value as a [{{.To.OutTypeName}}] value,
+// with the flags it shares with [{{.To.OutTypeName}}] copied by name, and the rest of
+// its flags unset.
+func (f *{{.From.OutTypeName}}) To{{.To.OutTypeName}}() {{.To.OutTypeName}} {
+	var to {{.To.OutTypeName}}
+{{- range $fv := .SharedFlags}}
+	to.{{$fv.SetterTo}}(f.{{$fv.Getter}}())
+{{- end}}
+	return to
+}
 `
 
-// flaggedTestTypeTemplate generates a table of subtests exercising the
-// methods generated for a single type. It only uses the generated methods
-// (never BitFlags), so the same template serves normal and raw output.
+// flaggedTestTypeTemplate generates the contents of the companion _test.go
+// file for a single type.
+// With Tests, it generates a table of subtests exercising the methods
+// generated for the type, including the optional ones, except the ones
+// depending on packages other than the standard library.
+// It only uses the generated methods (never BitFlags, except in its own
+// subtest), so the same template serves normal and raw output.
+// With Benchmarks, it generates benchmarks of the generated methods, along
+// with the equivalent operations on the source type, as a baseline.
+// With Fuzz, it generates a fuzz target of the round-trips between the
+// generated type and the other representations of its flags.
+// With Mock, it generates a mock of the generated interface.
 const flaggedTestTypeTemplate = `
 {{ $SourceTypeName := .SourceTypeName -}}
 {{ $OutTypeName := .OutTypeName -}}
+{{ $BitIndexType := .BitIndexType -}}
 {{ $FlagValues := .FlagValues -}}
-
-func Test{{$OutTypeName}}(t *testing.T) {
+{{ if .Tests }}
+{{- $First := index $FlagValues 0}}
+func Test{{if not (exported $OutTypeName)}}_{{end}}{{$OutTypeName}}(t *testing.T) {
 {{- range $fv := $FlagValues}}
 	t.Run("{{$fv.Flag}}", func(t *testing.T) {
 		var f {{$OutTypeName}}
 
-		if f.Is{{$fv.Flag}}() {
-			t.Fatal("Is{{$fv.Flag}}() = true on the zero value, want false")
+		if f.{{$fv.Getter}}() {
+			t.Fatal("{{$fv.Getter}}() = true on the zero value, want false")
 		}
-		if old := f.Set{{$fv.Flag}}(); old {
-			t.Errorf("Set{{$fv.Flag}}() old = true, want false")
+		if old := f.{{$fv.Setter}}(); old {
+			t.Errorf("{{$fv.Setter}}() old = true, want false")
 		}
-		if !f.Is{{$fv.Flag}}() {
-			t.Errorf("Is{{$fv.Flag}}() = false after Set, want true")
+		if !f.{{$fv.Getter}}() {
+			t.Errorf("{{$fv.Getter}}() = false after Set, want true")
 		}
-		if old := f.Reset{{$fv.Flag}}(); !old {
-			t.Errorf("Reset{{$fv.Flag}}() old = false, want true")
+		if old := f.{{$fv.Resetter}}(); !old {
+			t.Errorf("{{$fv.Resetter}}() old = false, want true")
 		}
-		if f.Is{{$fv.Flag}}() {
-			t.Errorf("Is{{$fv.Flag}}() = true after Reset, want false")
+		if f.{{$fv.Getter}}() {
+			t.Errorf("{{$fv.Getter}}() = true after Reset, want false")
 		}
-		if old := f.Set{{$fv.Flag}}To(true); old {
-			t.Errorf("Set{{$fv.Flag}}To(true) old = true, want false")
+		if old := f.{{$fv.SetterTo}}(true); old {
+			t.Errorf("{{$fv.SetterTo}}(true) old = true, want false")
 		}
-		if old := f.Set{{$fv.Flag}}To(false); !old {
-			t.Errorf("Set{{$fv.Flag}}To(false) old = false, want true")
+		if old := f.{{$fv.SetterTo}}(false); !old {
+			t.Errorf("{{$fv.SetterTo}}(false) old = false, want true")
 		}
-		if got := f.Toggle{{$fv.Flag}}(); !got {
-			t.Errorf("Toggle{{$fv.Flag}}() = false, want true")
+		if got := f.{{$fv.Toggler}}(); !got {
+			t.Errorf("{{$fv.Toggler}}() = false, want true")
 		}
-		if got := f.Toggle{{$fv.Flag}}(); got {
-			t.Errorf("Toggle{{$fv.Flag}}() = true, want false")
+		if got := f.{{$fv.Toggler}}(); got {
+			t.Errorf("{{$fv.Toggler}}() = true, want false")
 		}
 	})
 {{- end}}
@@ -102,8 +295,8 @@ func Test{{$OutTypeName}}(t *testing.T) {
 		var f {{$OutTypeName}}
 
 		all := {{$SourceTypeName}}{
-{{- range $fv := $FlagValues}}
-			{{$fv.Field}}: true,
+{{- range $sf := .Fields}}
+			{{$sf.KeyedValue "true"}},
 {{- end}}
 		}
 		f.SetTypedFlags(all)
@@ -121,19 +314,386 @@ func Test{{$OutTypeName}}(t *testing.T) {
 	// Clone returns an independent copy.
 	t.Run("Clone", func(t *testing.T) {
 		var f {{$OutTypeName}}
-		f.Set{{(index $FlagValues 0).Flag}}()
+		f.{{(index $FlagValues 0).Setter}}()
 
 		c := f.Clone()
 		if c != f {
 			t.Errorf("Clone() = %v, want %v", c, f)
 		}
-		c.Reset{{(index $FlagValues 0).Flag}}()
+		c.{{(index $FlagValues 0).Resetter}}()
 		if c == f {
 			t.Error("Clone() is not independent of the original")
 		}
 	})
 {{- if not .Raw}}
 
+	// CloneBitFlags returns an independent copy, as a flagged.BitFlags.
+	t.Run("CloneBitFlags", func(t *testing.T) {
+		var f {{$OutTypeName}}
+		f.{{(index $FlagValues 0).Setter}}()
+
+		c := f.CloneBitFlags()
+		if p, ok := c.(*{{$OutTypeName}}); !ok || *p != f {
+			t.Fatalf("CloneBitFlags() = %#v, want &%v", c, f)
+		}
+		c.ResetAll()
+		if c.Uint64() == f.Uint64() {
+			t.Error("CloneBitFlags() is not independent of the original")
+		}
+	})
+{{- end}}
+
+	// CopyFrom overrides the whole value.
+	t.Run("CopyFrom", func(t *testing.T) {
+		var src, dst {{$OutTypeName}}
+		src.{{$First.Setter}}()
+
+		dst.CopyFrom(&src)
+		if dst != src {
+			t.Errorf("CopyFrom() = %v, want %v", dst, src)
+		}
+	})
+
+	// ToMap then FromMap round-trips all flags by name.
+	t.Run("ToMap", func(t *testing.T) {
+		var f {{$OutTypeName}}
+
+		m := f.ToMap()
+		if got, want := len(m), {{$SourceTypeName}}NumFlags; got != want {
+			t.Fatalf("len(ToMap()) = %d, want %d", got, want)
+		}
+		for name := range m {
+			m[name] = true
+		}
+		if err := f.FromMap(m); err != nil {
+			t.Fatalf("FromMap() error = %v, want nil", err)
+		}
+		if got := f.ToMap(); !reflect.DeepEqual(got, m) {
+			t.Errorf("ToMap() = %v, want %v", got, m)
+		}
+
+		// An unknown name fails without changing any flag.
+		before := f
+		if err := f.FromMap(map[string]bool{"{{$First.Flag}}": false, "-": true}); err == nil {
+			t.Error("FromMap() with an unknown name error = nil, want non-nil")
+		}
+		if f != before {
+			t.Errorf("FromMap() with an unknown name changed the flags to %v, want %v", f, before)
+		}
+	})
+
+	// The named accessors agree with the bit indexes and with each other.
+	t.Run("Named", func(t *testing.T) {
+		indexes := {{$SourceTypeName}}FlagIndexes()
+		for i, name := range {{$SourceTypeName}}FlagNames() {
+			var f {{$OutTypeName}}
+
+			if err := f.SetNamedTo(name, true); err != nil {
+				t.Fatalf("SetNamedTo(%q, true) error = %v, want nil", name, err)
+			}
+			if set, err := f.IsNamed(name); !set || err != nil {
+				t.Errorf("IsNamed(%q) = %v, %v, want true, nil", name, set, err)
+			}
+			for other, set := range f.ToMap() {
+				if set != (other == name) {
+					t.Errorf("ToMap()[%q] = %v after SetNamedTo(%q, true)", other, set, name)
+				}
+			}
+
+			idx, ok := f.IndexOf(name)
+			if !ok || idx != indexes[i] {
+				t.Errorf("IndexOf(%q) = %v, %v, want %v, true", name, idx, ok, indexes[i])
+			}
+			if got := f.Name(idx); got != name {
+				t.Errorf("Name(%v) = %q, want %q", idx, got, name)
+			}
+		}
+
+		var f {{$OutTypeName}}
+		if _, err := f.IsNamed("-"); err == nil {
+			t.Error("IsNamed() with an unknown name error = nil, want non-nil")
+		}
+		if err := f.SetNamedTo("-", true); err == nil {
+			t.Error("SetNamedTo() with an unknown name error = nil, want non-nil")
+		}
+		if idx, ok := f.IndexOf("-"); ok {
+			t.Errorf("IndexOf() with an unknown name = %v, true, want false", idx)
+		}
+		if got := f.Name(-1); got != "" {
+			t.Errorf("Name(-1) = %q, want \"\"", got)
+		}
+	})
+
+	// AllFlags yields the same indexes as FlagIndexes.
+	t.Run("AllFlags", func(t *testing.T) {
+		got := slices.Collect({{$SourceTypeName}}AllFlags())
+		if want := {{$SourceTypeName}}FlagIndexes(); !reflect.DeepEqual(got, want) {
+			t.Errorf("AllFlags() = %v, want %v", got, want)
+		}
+		if got, want := len({{$SourceTypeName}}FlagNames()), {{$SourceTypeName}}NumFlags; got != want {
+			t.Errorf("len(FlagNames()) = %d, want %d", got, want)
+		}
+	})
+
+	// AllDefinedSet and AnyDefinedSet only consider the defined flags.
+	t.Run("DefinedSet", func(t *testing.T) {
+		var f {{$OutTypeName}}
+		if f.AnyDefinedSet() || f.AllDefinedSet() {
+			t.Error("AnyDefinedSet() or AllDefinedSet() = true on the zero value, want false")
+		}
+
+		f.{{$First.Setter}}()
+		if !f.AnyDefinedSet() {
+			t.Error("AnyDefinedSet() = false after {{$First.Setter}}(), want true")
+		}
+		if got, want := f.AllDefinedSet(), {{$SourceTypeName}}NumFlags == 1; got != want {
+			t.Errorf("AllDefinedSet() = %v after {{$First.Setter}}(), want %v", got, want)
+		}
+
+		f.SetTypedFlags({{$SourceTypeName}}{
+{{- range $sf := .Fields}}
+			{{$sf.KeyedValue "true"}},
+{{- end}}
+		})
+		if !f.AllDefinedSet() {
+			t.Error("AllDefinedSet() = false with all flags set, want true")
+		}
+	})
+
+	// Equal values have the same hash.
+	t.Run("Equal", func(t *testing.T) {
+		var a, b {{$OutTypeName}}
+		a.{{$First.Setter}}()
+		b.{{$First.Setter}}()
+
+		if !a.Equal(b) {
+			t.Errorf("Equal(%v) = false, want true", b)
+		}
+		if a.Hash() != b.Hash() {
+			t.Errorf("Hash() = %d and %d for equal values", a.Hash(), b.Hash())
+		}
+
+		b.{{$First.Toggler}}()
+		if a.Equal(b) {
+			t.Errorf("Equal(%v) = true, want false", b)
+		}
+	})
+
+	// AppendString appends the names of the set flags, without allocating.
+	t.Run("AppendString", func(t *testing.T) {
+		var f {{$OutTypeName}}
+		if got := string(f.AppendString([]byte("flags: "))); got != "flags: " {
+			t.Errorf("AppendString() = %q on the zero value, want %q", got, "flags: ")
+		}
+
+		f.SetTypedFlags({{$SourceTypeName}}{
+{{- range $sf := .Fields}}
+			{{$sf.KeyedValue "true"}},
+{{- end}}
+		})
+		want := "{{range $i, $fv := $FlagValues}}{{if $i}}|{{end}}{{$fv.Flag}}{{end}}"
+		if got := string(f.AppendString(nil)); got != want {
+			t.Errorf("AppendString() = %q, want %q", got, want)
+		}
+
+		buf := make([]byte, 0, len(want))
+		if allocs := testing.AllocsPerRun(10, func() { buf = f.AppendString(buf[:0]) }); allocs != 0 {
+			t.Errorf("AppendString() allocs = %v, want 0", allocs)
+		}
+	})
+
+	// GoString returns a Go expression, commented with the set flags.
+	t.Run("GoString", func(t *testing.T) {
+		var f {{$OutTypeName}}
+		if got, want := f.GoString(), "{{.PackageName}}.{{$OutTypeName}}(0b0)"; got != want {
+			t.Errorf("GoString() = %q on the zero value, want %q", got, want)
+		}
+
+		f.{{$First.Setter}}()
+		if got, want := f.GoString(), "{{.PackageName}}.{{$OutTypeName}}(0b1) /* {{$First.Flag}} */"; got != want {
+			t.Errorf("GoString() = %q, want %q", got, want)
+		}
+	})
+{{- if .JSONSchema}}
+
+	// The JSON schema has a boolean property for each key of ToMap.
+	t.Run("JSONSchema", func(t *testing.T) {
+		var schema struct {
+			Properties map[string]struct {
+				Type string ` + "`json:\"type\"`" + `
+			} ` + "`json:\"properties\"`" + `
+		}
+		if err := json.Unmarshal([]byte({{$SourceTypeName}}JSONSchema), &schema); err != nil {
+			t.Fatalf("json.Unmarshal({{$SourceTypeName}}JSONSchema) error = %v, want nil", err)
+		}
+
+		var f {{$OutTypeName}}
+		m := f.ToMap()
+		if len(schema.Properties) != len(m) {
+			t.Errorf("len(properties) = %d, want %d", len(schema.Properties), len(m))
+		}
+		for name := range m {
+			if got := schema.Properties[name].Type; got != "boolean" {
+				t.Errorf("type of property %q = %q, want %q", name, got, "boolean")
+			}
+		}
+	})
+{{- end}}
+{{- if .SchemaVersions}}
+
+	// MarshalBinary then UnmarshalBinary round-trips all flags together.
+	t.Run("MarshalBinary", func(t *testing.T) {
+		var f {{$OutTypeName}}
+		f.SetTypedFlags({{$SourceTypeName}}{
+{{- range $sf := .Fields}}
+			{{$sf.KeyedValue "true"}},
+{{- end}}
+		})
+
+		data, err := f.MarshalBinary()
+		if err != nil {
+			t.Fatalf("MarshalBinary() error = %v, want nil", err)
+		}
+		var got {{$OutTypeName}}
+		if err := got.UnmarshalBinary(data); err != nil {
+			t.Fatalf("UnmarshalBinary() error = %v, want nil", err)
+		}
+		if got != f {
+			t.Errorf("UnmarshalBinary(MarshalBinary()) = %v, want %v", got, f)
+		}
+
+		if err := got.UnmarshalBinary([]byte{0}); err == nil {
+			t.Error("UnmarshalBinary() with an unknown version error = nil, want non-nil")
+		}
+	})
+{{- end}}
+{{- if .HasRules}}
+
+	// The zero value satisfies all the rules.
+	t.Run("Validate", func(t *testing.T) {
+		var f {{$OutTypeName}}
+		if err := f.Validate(); err != nil {
+			t.Errorf("Validate() = %v on the zero value, want nil", err)
+		}
+	})
+{{- end}}
+{{- if .With}}
+
+	// With returns a modified copy, leaving the original unchanged.
+	t.Run("With", func(t *testing.T) {
+		var f {{$OutTypeName}}
+{{- range $fv := $FlagValues}}
+		if got := f.With{{$fv.Flag}}(true); !got.{{$fv.Getter}}() || f.{{$fv.Getter}}() {
+			t.Errorf("With{{$fv.Flag}}(true) = %v from %v", got, f)
+		}
+{{- end}}
+	})
+{{- end}}
+{{- if .Options}}
+
+	// The options are applied in order.
+	t.Run("New", func(t *testing.T) {
+		f := New{{$OutTypeName}}(With{{$First.Flag}}())
+		if !f.{{$First.Getter}}() {
+			t.Error("New{{$OutTypeName}}(With{{$First.Flag}}()).{{$First.Getter}}() = false, want true")
+		}
+		f = New{{$OutTypeName}}(With{{$First.Flag}}(), Without{{$First.Flag}}())
+		if f.{{$First.Getter}}() {
+			t.Error("New{{$OutTypeName}}(With{{$First.Flag}}(), Without{{$First.Flag}}()).{{$First.Getter}}() = true, want false")
+		}
+	})
+{{- end}}
+{{- if .Convert}}
+
+	// AsBitFlags matches SetTypedFlags.
+	t.Run("AsBitFlags", func(t *testing.T) {
+		typed := {{$SourceTypeName}}{ {{- (index $FlagValues 0).KeyedValue "true"}}}
+
+		var want {{$OutTypeName}}
+		want.SetTypedFlags(typed)
+		if got := typed.AsBitFlags(); got != want {
+			t.Errorf("AsBitFlags() = %v, want %v", got, want)
+		}
+	})
+{{- end}}
+{{- if .Slice}}
+
+	// The bulk methods only consider the elements with the flags set.
+	t.Run("Slice", func(t *testing.T) {
+		var set {{$OutTypeName}}
+		set.{{$First.Setter}}()
+		s := {{$OutTypeName}}Slice{set, 0, set}
+
+		if got := s.Count{{$First.Flag}}(); got != 2 {
+			t.Errorf("Count{{$First.Flag}}() = %d, want 2", got)
+		}
+		if got := s.FilterMask(set); !reflect.DeepEqual(got, {{$OutTypeName}}Slice{set, set}) {
+			t.Errorf("FilterMask(%v) = %v, want %v", set, got, {{$OutTypeName}}Slice{set, set})
+		}
+		if got := s.CountMask(0); got != len(s) {
+			t.Errorf("CountMask(0) = %d, want %d", got, len(s))
+		}
+	})
+{{- end}}
+{{- if .Context}}
+
+	// The flags stored in a context can be retrieved from it.
+	t.Run("Context", func(t *testing.T) {
+		if _, ok := {{$SourceTypeName}}FromContext(context.Background()); ok {
+			t.Error("{{$SourceTypeName}}FromContext() ok = true on an empty context, want false")
+		}
+
+		var want {{$OutTypeName}}
+		want.{{$First.Setter}}()
+		ctx := ContextWith{{$SourceTypeName}}(context.Background(), want)
+		if got, ok := {{$SourceTypeName}}FromContext(ctx); !ok || got != want {
+			t.Errorf("{{$SourceTypeName}}FromContext() = %v, %v, want %v, true", got, ok, want)
+		}
+	})
+{{- end}}
+{{- if .Atomic}}
+
+	// The atomic variant stores and modifies the same value.
+	t.Run("Atomic", func(t *testing.T) {
+		var a {{$SourceTypeName}}AtomicBitFlags
+		if old := a.{{$First.Setter}}(); old {
+			t.Error("{{$First.Setter}}() old = true on the zero value, want false")
+		}
+		if got := a.Load(); !got.{{$First.Getter}}() {
+			t.Errorf("Load() = %v after {{$First.Setter}}(), want it set", got)
+		}
+		if got := a.{{$First.Toggler}}(); got {
+			t.Error("{{$First.Toggler}}() = true, want false")
+		}
+
+		var want {{$OutTypeName}}
+		want.{{$First.Setter}}()
+		a.Store(want)
+		if got := a.Load(); got != want {
+			t.Errorf("Load() = %v after Store(%v)", got, want)
+		}
+	})
+{{- end}}
+{{- if .Safe}}
+
+	// The mutex-guarded variant stores and modifies the same value.
+	t.Run("Safe", func(t *testing.T) {
+		var s {{$SourceTypeName}}SafeBitFlags
+		if old := s.{{$First.Setter}}(); old {
+			t.Error("{{$First.Setter}}() old = true on the zero value, want false")
+		}
+		if got := s.Load(); !got.{{$First.Getter}}() {
+			t.Errorf("Load() = %v after {{$First.Setter}}(), want it set", got)
+		}
+
+		s.Update(func(f *{{$OutTypeName}}) { f.{{$First.Resetter}}() })
+		if s.{{$First.Getter}}() {
+			t.Error("{{$First.Getter}}() = true after Update resetting it, want false")
+		}
+	})
+{{- end}}
+{{- if not .Raw}}
+
 	// BitFlags exposes the same underlying value through the
 	// flagged.BitFlags interface, so changes are visible in both
 	// directions and the bit indexes line up with the generated constants.
@@ -150,108 +710,1213 @@ func Test{{$OutTypeName}}(t *testing.T) {
 		}
 
 		// A change through the typed accessor is visible through BitFlags.
-		f.Set{{(index $FlagValues 0).Flag}}()
+		f.{{(index $FlagValues 0).Setter}}()
 		if !bf.Is(_{{$SourceTypeName}}{{(index $FlagValues 0).Flag}}BitIndex) {
-			t.Error("BitFlags().Is(...) = false after Set{{(index $FlagValues 0).Flag}}(), want true")
+			t.Error("BitFlags().Is(...) = false after {{(index $FlagValues 0).Setter}}(), want true")
 		}
 
 		// A change through BitFlags is visible through the typed accessor.
 		bf.Reset(_{{$SourceTypeName}}{{(index $FlagValues 0).Flag}}BitIndex)
-		if f.Is{{(index $FlagValues 0).Flag}}() {
-			t.Error("Is{{(index $FlagValues 0).Flag}}() = true after BitFlags().Reset(...), want false")
+		if f.{{(index $FlagValues 0).Getter}}() {
+			t.Error("{{(index $FlagValues 0).Getter}}() = true after BitFlags().Reset(...), want false")
 		}
 	})
 {{- end}}
 }
-`
-
-// TODO: add a String method that makes use of the field name somehow.
-const flaggedTypeTemplate = `
-{{ $SourceTypeName := .SourceTypeName -}}
-{{ $OutTypeName := .OutTypeName -}}
-{{ $BitIndexType := .BitIndexType -}}
-{{ $FlagValues := .FlagValues -}}
-
-// {{$OutTypeName}} combines all flags from [{{$SourceTypeName}}] as {{if .Raw}}{{.UnderlyingType}}{{else}}[{{.UnderlyingType}}]{{end}}.
-type {{$OutTypeName}} {{.UnderlyingType}}
-
-// _{{.OutInterfaceName}} includes all the methods generated for type [{{$OutTypeName}}].
-type _{{.OutInterfaceName}} interface {
-{{- if not .Raw}}
-	BitFlags() flagged.BitFlags
-{{- end}}
-	Clone() {{$OutTypeName}}
-	TypedFlags() {{$SourceTypeName}}
-	SetTypedFlags(flags {{$SourceTypeName}})
-
-{{range $fv := $FlagValues}}
-	Is{{$fv.Flag}}() (set bool)
-	Set{{$fv.Flag}}() (old bool)
-	Reset{{$fv.Flag}}() (old bool)
-	Set{{$fv.Flag}}To(new bool) (old bool)
-	Toggle{{$fv.Flag}}() (new bool)
 {{end}}
+{{- if .Benchmarks}}
+{{- $SinkName := printf "_%sBenchmarkSink" $OutTypeName}}
+// {{$SinkName}} keeps the results of the benchmarks of [{{$OutTypeName}}] alive,
+// so the compiler can't optimize away the benchmarked code.
+var {{$SinkName}} any
 
-}
-
-// These are the indexes of the flags used by this generated code.
-// Listed in the same order their corresponding fields are listed in [{{$SourceTypeName}}].
-const (
+// Benchmark{{if not (exported $OutTypeName)}}_{{end}}{{$OutTypeName}} benchmarks the generated methods, along with the
+// equivalent operations on [{{$SourceTypeName}}] values, suffixed with "Struct", as a
+// baseline.
+func Benchmark{{if not (exported $OutTypeName)}}_{{end}}{{$OutTypeName}}(b *testing.B) {
+	b.Run("Is", func(b *testing.B) {
+		b.ReportAllocs()
+		var f {{$OutTypeName}}
+		n := 0
+		for i := 0; i < b.N; i++ {
 {{- range $fv := $FlagValues}}
-	_{{$SourceTypeName}}{{$fv.Flag}}BitIndex {{$BitIndexType}} = iota // for field [{{$SourceTypeName}}.{{$fv.Field}}]
+			if f.{{$fv.Getter}}() {
+				n++
+			}
 {{- end}}
-)
-{{if not .Raw}}
-// BitFlags returns an interface to the underlying value.
-func (f *{{$OutTypeName}}) BitFlags() flagged.BitFlags {
-	return (*flagged.BitFlags{{.OutTypeSize}})(f)
-}
-{{end}}
-// Clone returns a copy of the current flags value.
-func (f *{{$OutTypeName}}) Clone() {{$OutTypeName}} {
-	return *f
-}
-
-// TypedFlags returns a copy of the current flags value inside a typed
-// object, which is the same used to generate the flags in first place.
-func (f *{{$OutTypeName}}) TypedFlags() {{$SourceTypeName}} {
-	return {{$SourceTypeName}}{
+		}
+		{{$SinkName}} = n
+	})
+	b.Run("IsStruct", func(b *testing.B) {
+		b.ReportAllocs()
+		var s {{$SourceTypeName}}
+		n := 0
+		for i := 0; i < b.N; i++ {
 {{- range $fv := $FlagValues}}
-		{{$fv.Field}}: f.Is{{$fv.Flag}}(),
+			if s.{{$fv.Field}} {
+				n++
+			}
 {{- end}}
-	}
-}
-
-// SetTypedFlags overrides the current flags value based on the typed
-// object provided.
-func (f *{{$OutTypeName}}) SetTypedFlags(flags {{$SourceTypeName}}) {
+		}
+		{{$SinkName}} = n
+	})
+	b.Run("SetTo", func(b *testing.B) {
+		b.ReportAllocs()
+		var f {{$OutTypeName}}
+		for i := 0; i < b.N; i++ {
 {{- range $fv := $FlagValues}}
-	f.Set{{$fv.Flag}}To(flags.{{$fv.Field}})
+			f.{{$fv.SetterTo}}(i&1 == 0)
 {{- end}}
-}
-
-{{range $fv := $FlagValues}}
-func (f *{{$OutTypeName}}) Is{{$fv.Flag}}() (set bool) {
-	return *f&(1<<_{{$SourceTypeName}}{{$fv.Flag}}BitIndex) != 0
-}
-func (f *{{$OutTypeName}}) Set{{$fv.Flag}}() (old bool) {
-	return f.Set{{$fv.Flag}}To(true)
-}
-func (f *{{$OutTypeName}}) Reset{{$fv.Flag}}() (old bool) {
-	return f.Set{{$fv.Flag}}To(false)
-}
-func (f *{{$OutTypeName}}) Set{{$fv.Flag}}To(new bool) (old bool) {
-	old = *f&(1<<_{{$SourceTypeName}}{{$fv.Flag}}BitIndex) != 0
-	if new {
-		*f |= 1 << _{{$SourceTypeName}}{{$fv.Flag}}BitIndex
-	} else {
-		*f &^= 1 << _{{$SourceTypeName}}{{$fv.Flag}}BitIndex
-	}
-	return
-}
-func (f *{{$OutTypeName}}) Toggle{{$fv.Flag}}() (new bool) {
-	*f ^= 1 << _{{$SourceTypeName}}{{$fv.Flag}}BitIndex
-	return *f&(1<<_{{$SourceTypeName}}{{$fv.Flag}}BitIndex) != 0
+		}
+		{{$SinkName}} = f
+	})
+	b.Run("SetToStruct", func(b *testing.B) {
+		b.ReportAllocs()
+		var s {{$SourceTypeName}}
+		for i := 0; i < b.N; i++ {
+{{- range $fv := $FlagValues}}
+			s.{{$fv.Field}} = i&1 == 0
+{{- end}}
+		}
+		{{$SinkName}} = s
+	})
+	b.Run("Toggle", func(b *testing.B) {
+		b.ReportAllocs()
+		var f {{$OutTypeName}}
+		for i := 0; i < b.N; i++ {
+{{- range $fv := $FlagValues}}
+			f.{{$fv.Toggler}}()
+{{- end}}
+		}
+		{{$SinkName}} = f
+	})
+	b.Run("ToggleStruct", func(b *testing.B) {
+		b.ReportAllocs()
+		var s {{$SourceTypeName}}
+		for i := 0; i < b.N; i++ {
+{{- range $fv := $FlagValues}}
+			s.{{$fv.Field}} = !s.{{$fv.Field}}
+{{- end}}
+		}
+		{{$SinkName}} = s
+	})
+	b.Run("Equal", func(b *testing.B) {
+		b.ReportAllocs()
+		var f, other {{$OutTypeName}}
+		n := 0
+		for i := 0; i < b.N; i++ {
+			if f.Equal(other) {
+				n++
+			}
+		}
+		{{$SinkName}} = n
+	})
+	b.Run("EqualStruct", func(b *testing.B) {
+		b.ReportAllocs()
+		var s, other {{$SourceTypeName}}
+		n := 0
+		for i := 0; i < b.N; i++ {
+			if s == other {
+				n++
+			}
+		}
+		{{$SinkName}} = n
+	})
+	b.Run("Hash", func(b *testing.B) {
+		b.ReportAllocs()
+		var f {{$OutTypeName}}
+		var h uint64
+		for i := 0; i < b.N; i++ {
+			h += f.Hash()
+		}
+		{{$SinkName}} = h
+	})
+	b.Run("AppendString", func(b *testing.B) {
+		b.ReportAllocs()
+		var f {{$OutTypeName}}
+		f.SetTypedFlags({{$SourceTypeName}}{
+{{- range $sf := .Fields}}
+			{{$sf.KeyedValue "true"}},
+{{- end}}
+		})
+		buf := f.AppendString(nil)
+		for i := 0; i < b.N; i++ {
+			buf = f.AppendString(buf[:0])
+		}
+		{{$SinkName}} = buf
+	})
+	b.Run("TypedFlags", func(b *testing.B) {
+		b.ReportAllocs()
+		var f {{$OutTypeName}}
+		var s {{$SourceTypeName}}
+		for i := 0; i < b.N; i++ {
+			s = f.TypedFlags()
+		}
+		{{$SinkName}} = s
+	})
+	b.Run("SetTypedFlags", func(b *testing.B) {
+		b.ReportAllocs()
+		var f {{$OutTypeName}}
+		var s {{$SourceTypeName}}
+		for i := 0; i < b.N; i++ {
+			f.SetTypedFlags(s)
+		}
+		{{$SinkName}} = f
+	})
+	b.Run("ToMap", func(b *testing.B) {
+		b.ReportAllocs()
+		var f {{$OutTypeName}}
+		var m map[string]bool
+		for i := 0; i < b.N; i++ {
+			m = f.ToMap()
+		}
+		{{$SinkName}} = m
+	})
+	b.Run("FromMap", func(b *testing.B) {
+		b.ReportAllocs()
+		var f {{$OutTypeName}}
+		m := f.ToMap()
+		for i := 0; i < b.N; i++ {
+			if err := f.FromMap(m); err != nil {
+				b.Fatal(err)
+			}
+		}
+		{{$SinkName}} = f
+	})
+}
+{{end}}
+{{- if .Fuzz}}
+// Fuzz{{if not (exported $OutTypeName)}}_{{end}}{{$OutTypeName}} fuzzes the round-trips between [{{$OutTypeName}}] values and the
+// other representations of their flags, starting from arbitrary values,
+// including ones with the bits not used by any flag set.
+func Fuzz{{if not (exported $OutTypeName)}}_{{end}}{{$OutTypeName}}(f *testing.F) {
+	f.Add(uint{{.OutTypeSize}}(0))
+	f.Add(uint{{.OutTypeSize}}(_{{$SourceTypeName}}DefinedMask))
+	f.Add(^uint{{.OutTypeSize}}(0))
+	f.Fuzz(func(t *testing.T, v uint{{.OutTypeSize}}) {
+		flags := {{$OutTypeName}}(v)
+
+		// The unused bits are ignored by Equal and Hash.
+		defined := flags & _{{$SourceTypeName}}DefinedMask
+		if !flags.Equal(defined) {
+			t.Fatalf("Equal(%v) = false for %v", defined, flags)
+		}
+		if flags.Hash() != defined.Hash() {
+			t.Fatalf("Hash() = %d, want %d", flags.Hash(), defined.Hash())
+		}
+
+		var typed {{$OutTypeName}}
+		typed.SetTypedFlags(flags.TypedFlags())
+		if typed != defined {
+			t.Fatalf("SetTypedFlags(TypedFlags()) = %v, want %v", typed, defined)
+		}
+
+		m := flags.ToMap()
+		var fromMap {{$OutTypeName}}
+		if err := fromMap.FromMap(m); err != nil {
+			t.Fatalf("FromMap(ToMap()) error = %v", err)
+		}
+		if fromMap != defined {
+			t.Fatalf("FromMap(ToMap()) = %v, want %v", fromMap, defined)
+		}
+
+		for name, set := range m {
+			if got, err := flags.IsNamed(name); err != nil || got != set {
+				t.Fatalf("IsNamed(%q) = %v, %v, want %v, nil", name, got, err, set)
+			}
+		}
+{{- if .SchemaVersions}}
+
+		data, err := flags.MarshalBinary()
+		if err != nil {
+			t.Fatalf("MarshalBinary() error = %v", err)
+		}
+		var fromBinary {{$OutTypeName}}
+		if err := fromBinary.UnmarshalBinary(data); err != nil {
+			t.Fatalf("UnmarshalBinary(MarshalBinary()) error = %v", err)
+		}
+		if fromBinary != defined {
+			t.Fatalf("UnmarshalBinary(MarshalBinary()) = %v, want %v", fromBinary, defined)
+		}
+{{- if not .Raw}}
+
+		// The bits after the version are encoded like the BitFlags types.
+		want, err := flagged.BitFlags{{.OutTypeSize}}(defined).MarshalBinary()
+		if err != nil {
+			t.Fatalf("BitFlags{{.OutTypeSize}}.MarshalBinary() error = %v", err)
+		}
+		if string(data[1:]) != string(want) {
+			t.Fatalf("MarshalBinary()[1:] = %x, want %x", data[1:], want)
+		}
+{{- end}}
+{{- end}}
+	})
+}
+{{end}}
+{{- if .Mock}}
+{{- $MockTypeName := printf "%sMock" $OutTypeName}}
+// {{$MockTypeName}} implements [_{{.OutInterfaceName}}], recording the calls to its methods.
+// It embeds a [{{$OutTypeName}}] value, which all the calls are forwarded to after
+// being recorded, so it behaves like a [{{$OutTypeName}}] value.
+// The methods inherited from the [flagged.BitFlags] interface, if any, are
+// forwarded without being recorded.
+type {{$MockTypeName}} struct {
+	{{$OutTypeName}}
+
+	// Calls are the recorded calls, in order.
+	Calls []{{$MockTypeName}}Call
+}
+
+// {{$MockTypeName}}Call is a single call recorded by [{{$MockTypeName}}].
+type {{$MockTypeName}}Call struct {
+	Method string
+	Args   []any
+}
+
+var _ _{{.OutInterfaceName}} = (*{{$MockTypeName}})(nil)
+
+func (m *{{$MockTypeName}}) record(method string, args ...any) {
+	m.Calls = append(m.Calls, {{$MockTypeName}}Call{Method: method, Args: args})
+}
+
+// ResetCalls clears the recorded calls.
+func (m *{{$MockTypeName}}) ResetCalls() {
+	m.Calls = nil
+}
+{{- if not .Raw}}
+
+func (m *{{$MockTypeName}}) BitFlags() flagged.BitFlags {
+	m.record("BitFlags")
+	return m.{{$OutTypeName}}.BitFlags()
+}
+{{- end}}
+
+func (m *{{$MockTypeName}}) Clone() {{$OutTypeName}} {
+	m.record("Clone")
+	return m.{{$OutTypeName}}.Clone()
+}
+
+func (m *{{$MockTypeName}}) CopyFrom(src *{{$OutTypeName}}) {
+	m.record("CopyFrom", src)
+	m.{{$OutTypeName}}.CopyFrom(src)
+}
+
+func (m *{{$MockTypeName}}) TypedFlags() {{$SourceTypeName}} {
+	m.record("TypedFlags")
+	return m.{{$OutTypeName}}.TypedFlags()
+}
+
+func (m *{{$MockTypeName}}) SetTypedFlags(flags {{$SourceTypeName}}) {
+	m.record("SetTypedFlags", flags)
+	m.{{$OutTypeName}}.SetTypedFlags(flags)
+}
+
+func (m *{{$MockTypeName}}) ToMap() map[string]bool {
+	m.record("ToMap")
+	return m.{{$OutTypeName}}.ToMap()
+}
+
+func (m *{{$MockTypeName}}) FromMap(fm map[string]bool) error {
+	m.record("FromMap", fm)
+	return m.{{$OutTypeName}}.FromMap(fm)
+}
+
+func (m *{{$MockTypeName}}) IsNamed(name string) (set bool, err error) {
+	m.record("IsNamed", name)
+	return m.{{$OutTypeName}}.IsNamed(name)
+}
+
+func (m *{{$MockTypeName}}) SetNamedTo(name string, new bool) error {
+	m.record("SetNamedTo", name, new)
+	return m.{{$OutTypeName}}.SetNamedTo(name, new)
+}
+
+func (m *{{$MockTypeName}}) Name(idx {{$BitIndexType}}) string {
+	m.record("Name", idx)
+	return m.{{$OutTypeName}}.Name(idx)
+}
+
+func (m *{{$MockTypeName}}) IndexOf(name string) (idx {{$BitIndexType}}, ok bool) {
+	m.record("IndexOf", name)
+	return m.{{$OutTypeName}}.IndexOf(name)
+}
+
+func (m *{{$MockTypeName}}) AllDefinedSet() bool {
+	m.record("AllDefinedSet")
+	return m.{{$OutTypeName}}.AllDefinedSet()
+}
+
+func (m *{{$MockTypeName}}) AnyDefinedSet() bool {
+	m.record("AnyDefinedSet")
+	return m.{{$OutTypeName}}.AnyDefinedSet()
+}
+
+func (m *{{$MockTypeName}}) Equal(other {{$OutTypeName}}) bool {
+	m.record("Equal", other)
+	return m.{{$OutTypeName}}.Equal(other)
+}
+
+func (m *{{$MockTypeName}}) Hash() uint64 {
+	m.record("Hash")
+	return m.{{$OutTypeName}}.Hash()
+}
+
+func (m *{{$MockTypeName}}) AppendString(dst []byte) []byte {
+	m.record("AppendString", dst)
+	return m.{{$OutTypeName}}.AppendString(dst)
+}
+
+func (m *{{$MockTypeName}}) GoString() string {
+	m.record("GoString")
+	return m.{{$OutTypeName}}.GoString()
+}
+{{- if .SchemaVersions}}
+
+func (m *{{$MockTypeName}}) MarshalBinary() ([]byte, error) {
+	m.record("MarshalBinary")
+	return m.{{$OutTypeName}}.MarshalBinary()
+}
+
+func (m *{{$MockTypeName}}) UnmarshalBinary(data []byte) error {
+	m.record("UnmarshalBinary", data)
+	return m.{{$OutTypeName}}.UnmarshalBinary(data)
+}
+{{- end}}
+{{- if .HasRules}}
+
+func (m *{{$MockTypeName}}) Validate() error {
+	m.record("Validate")
+	return m.{{$OutTypeName}}.Validate()
+}
+{{- end}}
+{{range $fv := $FlagValues}}
+func (m *{{$MockTypeName}}) {{$fv.Getter}}() (set bool) {
+	m.record("{{$fv.Getter}}")
+	return m.{{$OutTypeName}}.{{$fv.Getter}}()
+}
+
+func (m *{{$MockTypeName}}) {{$fv.Setter}}() (old bool) {
+	m.record("{{$fv.Setter}}")
+	return m.{{$OutTypeName}}.{{$fv.Setter}}()
+}
+
+func (m *{{$MockTypeName}}) {{$fv.Resetter}}() (old bool) {
+	m.record("{{$fv.Resetter}}")
+	return m.{{$OutTypeName}}.{{$fv.Resetter}}()
+}
+
+func (m *{{$MockTypeName}}) {{$fv.SetterTo}}(new bool) (old bool) {
+	m.record("{{$fv.SetterTo}}", new)
+	return m.{{$OutTypeName}}.{{$fv.SetterTo}}(new)
+}
+
+func (m *{{$MockTypeName}}) {{$fv.Toggler}}() (new bool) {
+	m.record("{{$fv.Toggler}}")
+	return m.{{$OutTypeName}}.{{$fv.Toggler}}()
+}
+{{end}}
+{{- end}}
+`
+
+// flaggedExampleTypeTemplate generates the runnable examples of the
+// companion _example_test.go file for a single type.
+// Examples of exported types are named after the type and its methods, so
+// they are shown in the documentation, while the ones of unexported types
+// are package examples, with the type name as a suffix.
+const flaggedExampleTypeTemplate = `
+{{ $SourceTypeName := .SourceTypeName -}}
+{{ $OutTypeName := .OutTypeName -}}
+{{ $First := index .FlagValues 0 -}}
+{{ $Prefix := $OutTypeName -}}
+{{ if not (exported $OutTypeName)}}{{$Prefix = printf "_%s" $OutTypeName}}{{end -}}
+func Example{{$Prefix}}() {
+	var f {{$OutTypeName}}
+	f.{{$First.Setter}}()
+
+	for _, name := range {{$SourceTypeName}}FlagNames() {
+		set, _ := f.IsNamed(name)
+		fmt.Println(name, set)
+	}
+	// Output:
+{{- range $i, $fv := .FlagValues}}
+	// {{$fv.Flag}} {{if eq $i 0}}true{{else}}false{{end}}
+{{- end}}
+}
+
+func Example{{$Prefix}}_{{if exported $OutTypeName}}S{{else}}s{{end}}etTypedFlags() {
+	var f {{$OutTypeName}}
+	f.SetTypedFlags({{$SourceTypeName}}{ {{- $First.KeyedValue "true"}}})
+
+	fmt.Println(f.{{$First.Getter}}())
+	fmt.Println(f.TypedFlags().{{$First.Field}})
+	// Output:
+	// true
+	// true
+}
+
+func Example{{$Prefix}}_{{if exported $OutTypeName}}F{{else}}f{{end}}romMap() {
+	var f {{$OutTypeName}}
+
+	err := f.FromMap(map[string]bool{"{{$First.Flag}}": true})
+	fmt.Println(f.{{$First.Getter}}(), err)
+
+	err = f.FromMap(map[string]bool{"-": true})
+	fmt.Println(err)
+	// Output:
+	// true <nil>
+	// unknown flag "-" for type {{$OutTypeName}}
+}
+`
+
+// flaggedDocHeaderTemplate generates the header of the markdown file written
+// to the -docOut path.
+const flaggedDocHeaderTemplate = `<!-- Code generated by "genflagged {{.CmdArgs}}"; DO NOT EDIT. -->
+
+# Flags of package {{.PackageName}}
+`
+
+// flaggedDocTypeTemplate generates the markdown table of the flags of a
+// single type, in the file written to the -docOut path.
+const flaggedDocTypeTemplate = `
+## {{.OutTypeName}}
+
+Generated from ` + "`{{.SourceTypeName}}`" + `, with {{len .FlagValues}} flags out of {{.OutTypeSize}} bits.
+
+| Flag | Field | Bit index | Mask | Description |
+|------|-------|-----------|------|-------------|
+{{- range $i, $fv := .FlagValues}}
+| {{$fv.Flag}} | {{$fv.Field}} | {{$i}} | ` + "`{{mask $i $.OutTypeSize}}`" + ` | {{cell $fv.Doc}} |
+{{- end}}
+`
+
+// TODO: add a String method that makes use of the field name somehow.
+const flaggedTypeTemplate = `
+{{ $SourceTypeName := .SourceTypeName -}}
+{{ $OutTypeName := .OutTypeName -}}
+{{ $BitIndexType := .BitIndexType -}}
+{{ $FlagValues := .FlagValues -}}
+{{ $RO := printf "f *%s" $OutTypeName -}}
+{{ $F := "*f" -}}
+{{ if .ValueReceivers}}{{$RO = printf "f %s" $OutTypeName}}{{$F = "f"}}{{end -}}
+
+// {{$OutTypeName}} combines all flags from [{{$SourceTypeName}}] as {{if .Raw}}{{.UnderlyingType}}{{else}}[{{.UnderlyingType}}]{{end}}.
+type {{$OutTypeName}} {{.UnderlyingType}}
+
+// _{{.OutInterfaceName}} includes all the methods generated for type [{{$OutTypeName}}].
+type _{{.OutInterfaceName}} interface {
+{{- if not .Raw}}
+	flagged.BitFlags
+	BitFlags() flagged.BitFlags
+{{- end}}
+	Clone() {{$OutTypeName}}
+	CopyFrom(src *{{$OutTypeName}})
+	TypedFlags() {{$SourceTypeName}}
+	SetTypedFlags(flags {{$SourceTypeName}})
+	ToMap() map[string]bool
+	FromMap(m map[string]bool) error
+	IsNamed(name string) (set bool, err error)
+	SetNamedTo(name string, new bool) error
+	Name(idx {{$BitIndexType}}) string
+	IndexOf(name string) (idx {{$BitIndexType}}, ok bool)
+	AllDefinedSet() bool
+	AnyDefinedSet() bool
+	Equal(other {{$OutTypeName}}) bool
+	Hash() uint64
+	AppendString(dst []byte) []byte
+	GoString() string
+{{- if .SchemaVersions}}
+	MarshalBinary() ([]byte, error)
+	UnmarshalBinary(data []byte) error
+{{- end}}
+{{- if .HasRules}}
+	Validate() error
+{{- end}}
+{{- if .Prometheus}}
+	Collector() prometheus.Collector
+{{- end}}
+{{- if .ProtoMessage}}
+	ToProto() *{{.ProtoMessage}}
+	FromProto(m *{{.ProtoMessage}})
+{{- end}}
+
+{{range $fv := $FlagValues}}
+	{{$fv.Getter}}() (set bool)
+	{{$fv.Setter}}() (old bool)
+	{{$fv.Resetter}}() (old bool)
+	{{$fv.SetterTo}}(new bool) (old bool)
+	{{$fv.Toggler}}() (new bool)
+{{- if $.With}}
+	With{{$fv.Flag}}(new bool) {{$OutTypeName}}
+{{- end}}
+{{end}}
+
+}
+
+// These are the indexes of the flags used by this generated code.
+// Listed in the same order their corresponding fields are listed in [{{$SourceTypeName}}].
+const (
+{{- range $fv := $FlagValues}}
+	_{{$SourceTypeName}}{{$fv.Flag}}BitIndex {{$BitIndexType}} = iota // for field {{$fv.DocLink $SourceTypeName}}
+{{- end}}
+)
+{{- if .SourceStruct}}
+
+// Make sure [{{$SourceTypeName}}] still has the fields it was generated from, in the
+// same order, so this file fails to build when they change, rather than
+// getting out of date; if so, regenerate it.
+var _ = {{$SourceTypeName}}({{.SourceStruct}}{})
+{{- end}}
+
+// _{{$SourceTypeName}}DefinedMask has the bits of all the flags of [{{$OutTypeName}}] set,
+// and the unused bits, if any, unset.
+const _{{$SourceTypeName}}DefinedMask {{$OutTypeName}} = 0 {{- range $fv := $FlagValues}} |
+	1<<_{{$SourceTypeName}}{{$fv.Flag}}BitIndex
+{{- end}}
+
+// {{$SourceTypeName}}NumFlags is the number of flags of [{{$OutTypeName}}], which can be
+// less than its bit width.
+const {{$SourceTypeName}}NumFlags = {{len $FlagValues}}
+{{- if .JSONSchema}}
+
+// {{$SourceTypeName}}JSONSchema is a JSON Schema, which OpenAPI 3.1 schema objects are
+// compatible with, of the object form of [{{$OutTypeName}}], as returned by
+// [{{$OutTypeName}}.ToMap], and accepted by [{{$OutTypeName}}.FromMap], which has a boolean
+// property for each flag, keyed by its name.
+const {{$SourceTypeName}}JSONSchema = {{.JSONSchema}}
+{{- end}}
+
+// {{$SourceTypeName}}FlagNames returns the names of all the flags of [{{$OutTypeName}}],
+// ordered by their bit indexes.
+func {{$SourceTypeName}}FlagNames() []string {
+	return []string{
+{{- range $fv := $FlagValues}}
+		"{{$fv.Flag}}",
+{{- end}}
+	}
+}
+
+// {{$SourceTypeName}}FlagIndexes returns the bit indexes of all the flags of [{{$OutTypeName}}],
+// in order.
+func {{$SourceTypeName}}FlagIndexes() []{{$BitIndexType}} {
+	return []{{$BitIndexType}}{
+{{- range $fv := $FlagValues}}
+		_{{$SourceTypeName}}{{$fv.Flag}}BitIndex,
+{{- end}}
+	}
+}
+
+// {{$SourceTypeName}}AllFlags returns an iterator over the bit indexes of all the flags
+// of [{{$OutTypeName}}], in order.
+// Unlike iterating over all the bits of [{{$OutTypeName}}], it never yields an index
+// that's not used by any flag.
+func {{$SourceTypeName}}AllFlags() iter.Seq[{{$BitIndexType}}] {
+	return func(yield func({{$BitIndexType}}) bool) {
+{{- range $fv := $FlagValues}}
+		if !yield(_{{$SourceTypeName}}{{$fv.Flag}}BitIndex) {
+			return
+		}
+{{- end}}
+	}
+}
+{{if not .Raw}}
+// BitFlags returns an interface to the underlying value.
+func (f *{{$OutTypeName}}) BitFlags() flagged.BitFlags {
+	return (*flagged.BitFlags{{.OutTypeSize}})(f)
+}
+
+// Make sure [{{$OutTypeName}}] implements [flagged.BitFlags] directly.
+var _ flagged.BitFlags = (*{{$OutTypeName}})(nil)
+
+// The following methods implement [flagged.BitFlags], by forwarding to the
+// value returned by [{{$OutTypeName}}.BitFlags].
+
+func ({{$RO}}) Is(idx flagged.BitIndex) (set bool)               { return f.BitFlags().Is(idx) }
+func (f *{{$OutTypeName}}) Set(idx flagged.BitIndex) (old bool)             { return f.BitFlags().Set(idx) }
+func (f *{{$OutTypeName}}) Reset(idx flagged.BitIndex) (old bool)           { return f.BitFlags().Reset(idx) }
+func (f *{{$OutTypeName}}) SetTo(idx flagged.BitIndex, new bool) (old bool) { return f.BitFlags().SetTo(idx, new) }
+func (f *{{$OutTypeName}}) Toggle(idx flagged.BitIndex) (new bool)          { return f.BitFlags().Toggle(idx) }
+func (f *{{$OutTypeName}}) SetAll()                                         { f.BitFlags().SetAll() }
+func (f *{{$OutTypeName}}) ResetAll()                                       { f.BitFlags().ResetAll() }
+func ({{$RO}}) AnySet() bool                                    { return f.BitFlags().AnySet() }
+func ({{$RO}}) AllSet() bool                                    { return f.BitFlags().AllSet() }
+func ({{$RO}}) AnyOf(idx ...flagged.BitIndex) bool              { return f.BitFlags().AnyOf(idx...) }
+func ({{$RO}}) AllOf(idx ...flagged.BitIndex) bool              { return f.BitFlags().AllOf(idx...) }
+func ({{$RO}}) Size() int                                       { return f.BitFlags().Size() }
+func ({{$RO}}) String() string                                  { return f.BitFlags().String() }
+func ({{$RO}}) PrettyString() string                            { return f.BitFlags().PrettyString() }
+func ({{$RO}}) CountSet() int                                   { return f.BitFlags().CountSet() }
+func ({{$RO}}) Bits() iter.Seq2[flagged.BitIndex, bool]         { return f.BitFlags().Bits() }
+func ({{$RO}}) SetBits() iter.Seq[flagged.BitIndex]             { return f.BitFlags().SetBits() }
+func ({{$RO}}) Len() int                                        { return f.BitFlags().Len() }
+func ({{$RO}}) TrailingZeros() int                              { return f.BitFlags().TrailingZeros() }
+func ({{$RO}}) LeadingZeros() int                               { return f.BitFlags().LeadingZeros() }
+func ({{$RO}}) Uint64() uint64                                  { return f.BitFlags().Uint64() }
+func (f *{{$OutTypeName}}) SetUint64(v uint64)                              { f.BitFlags().SetUint64(v) }
+{{end}}
+// Clone returns a copy of the current flags value.
+// It has a value receiver, so it can be used to snapshot non-addressable
+// values too, like map entries.
+func (f {{$OutTypeName}}) Clone() {{$OutTypeName}} {
+	return f
+}
+{{- if not .Raw}}
+
+// CloneBitFlags returns a copy of the current flags value, as a pointer to a
+// new [{{$OutTypeName}}] value, which implements [flagged.BitFlags].
+// Like [{{$OutTypeName}}.Clone], it has a value receiver.
+func (f {{$OutTypeName}}) CloneBitFlags() flagged.BitFlags {
+	return &f
+}
+{{- end}}
+
+// CopyFrom overrides the current flags value with a copy of src.
+func (f *{{$OutTypeName}}) CopyFrom(src *{{$OutTypeName}}) {
+	*f = *src
+}
+
+// TypedFlags returns a copy of the current flags value inside a typed
+// object, which is the same used to generate the flags in first place.
+func ({{$RO}}) TypedFlags() {{$SourceTypeName}} {
+	return {{$SourceTypeName}}{
+{{- range $sf := .Fields}}
+{{- if $sf.IsArray}}
+		{{$sf.Name}}: [{{len $sf.Flags}}]bool{
+{{- range $fv := $sf.Flags}}
+			f.{{$fv.Getter}}(),
+{{- end}}
+		},
+{{- else}}
+{{- with index $sf.Flags 0}}
+		{{.Field}}: f.{{.Getter}}(),
+{{- end}}
+{{- end}}
+{{- end}}
+	}
+}
+
+// SetTypedFlags overrides the current flags value based on the typed
+// object provided.
+func (f *{{$OutTypeName}}) SetTypedFlags(flags {{$SourceTypeName}}) {
+{{- range $fv := $FlagValues}}
+	f.{{$fv.SetterTo}}(flags.{{$fv.Field}})
+{{- end}}
+}
+{{if .Convert}}
+// AsBitFlags returns a copy of the current typed object as a [{{$OutTypeName}}]
+// value, which is the same as calling [{{$OutTypeName}}.SetTypedFlags] on a zero value.
+func (t {{$SourceTypeName}}) AsBitFlags() {{$OutTypeName}} {
+	var f {{$OutTypeName}}
+	f.SetTypedFlags(t)
+	return f
+}
+
+{{end -}}
+// ToMap returns a copy of the current flags value as a map, keyed by the
+// flag names.
+func ({{$RO}}) ToMap() map[string]bool {
+	return map[string]bool{
+{{- range $fv := $FlagValues}}
+		"{{$fv.Flag}}": f.{{$fv.Getter}}(),
+{{- end}}
+	}
+}
+
+// FromMap overrides the flags included in the map provided, keyed by the
+// flag names, leaving the rest of the flags unchanged.
+// It returns an error, without changing any flag, if the map includes an
+// unknown flag name.
+func (f *{{$OutTypeName}}) FromMap(m map[string]bool) error {
+	flags := *f
+	for name, v := range m {
+		if err := flags.SetNamedTo(name, v); err != nil {
+			return err
+		}
+	}
+	*f = flags
+	return nil
+}
+
+// IsNamed reports whether the flag with the given name is set to true or not.
+// It returns an error if there's no flag with that name.
+func ({{$RO}}) IsNamed(name string) (set bool, err error) {
+	switch name {
+{{- range $fv := $FlagValues}}
+	case "{{$fv.Flag}}":
+		return f.{{$fv.Getter}}(), nil
+{{- end}}
+	default:
+		return false, fmt.Errorf("unknown flag %q for type {{$OutTypeName}}", name)
+	}
+}
+
+// SetNamedTo sets the flag with the given name to the new value.
+// It returns an error, without changing any flag, if there's no flag with
+// that name.
+func (f *{{$OutTypeName}}) SetNamedTo(name string, new bool) error {
+	switch name {
+{{- range $fv := $FlagValues}}
+	case "{{$fv.Flag}}":
+		f.{{$fv.SetterTo}}(new)
+{{- end}}
+	default:
+		return fmt.Errorf("unknown flag %q for type {{$OutTypeName}}", name)
+	}
+	return nil
+}
+
+// Name returns the name of the flag at the bit index idx, or "" if there's
+// no flag at that index.
+func ({{$RO}}) Name(idx {{$BitIndexType}}) string {
+	switch idx {
+{{- range $fv := $FlagValues}}
+	case _{{$SourceTypeName}}{{$fv.Flag}}BitIndex:
+		return "{{$fv.Flag}}"
+{{- end}}
+	default:
+		return ""
+	}
+}
+
+// IndexOf returns the bit index of the flag with the given name, and
+// whether there's a flag with that name.
+func ({{$RO}}) IndexOf(name string) (idx {{$BitIndexType}}, ok bool) {
+	switch name {
+{{- range $fv := $FlagValues}}
+	case "{{$fv.Flag}}":
+		return _{{$SourceTypeName}}{{$fv.Flag}}BitIndex, true
+{{- end}}
+	default:
+		return -1, false
+	}
+}
+
+// AllDefinedSet reports whether all the flags are set to true, ignoring the
+// bits not used by any flag, unlike the AllSet method of the flags value,
+// which is never true unless all the bits of the underlying type are set.
+func ({{$RO}}) AllDefinedSet() bool {
+	return {{$F}}&_{{$SourceTypeName}}DefinedMask == _{{$SourceTypeName}}DefinedMask
+}
+
+// AnyDefinedSet reports whether any of the flags is set to true, ignoring the
+// bits not used by any flag.
+func ({{$RO}}) AnyDefinedSet() bool {
+	return {{$F}}&_{{$SourceTypeName}}DefinedMask != 0
+}
+
+// Equal reports whether the current flags value has the same flags set as
+// other, ignoring the bits not used by any flag.
+func ({{$RO}}) Equal(other {{$OutTypeName}}) bool {
+	return {{$F}}&_{{$SourceTypeName}}DefinedMask == other&_{{$SourceTypeName}}DefinedMask
+}
+
+// Hash returns a hash of the current flags value, ignoring the bits not used
+// by any flag, so values reported equal by [{{$OutTypeName}}.Equal] have the
+// same hash.
+// The hash is stable across runs, as long as the bit indexes of the flags
+// don't change.
+func ({{$RO}}) Hash() uint64 {
+	// The finalizer of splitmix64, spreading the few used bits over the
+	// whole hash.
+	h := uint64({{$F}} & _{{$SourceTypeName}}DefinedMask)
+	h = (h ^ (h >> 30)) * 0xbf58476d1ce4e5b9
+	h = (h ^ (h >> 27)) * 0x94d049bb133111eb
+	return h ^ (h >> 31)
+}
+
+// AppendString appends the names of the flags set in the current flags value
+// to dst, separated by '|', in the order of their bit indexes, and returns the
+// extended buffer.
+// Nothing is appended if no flag is set.
+// It doesn't allocate, unless dst doesn't have enough capacity.
+func ({{$RO}}) AppendString(dst []byte) []byte {
+	n := len(dst)
+{{- range $fv := $FlagValues}}
+	if f.{{$fv.Getter}}() {
+		if len(dst) > n {
+			dst = append(dst, '|')
+		}
+		dst = append(dst, "{{$fv.Flag}}"...)
+	}
+{{- end}}
+	return dst
+}
+
+// GoString returns the current flags value as a Go expression of the
+// generated type, qualified by its package name, in binary, followed by a
+// comment of the names of the set flags, if any, like:
+//
+//	{{.PackageName}}.{{$OutTypeName}}(0b101) /* {{(index $FlagValues 0).Flag}}|... */
+//
+// It implements [fmt.GoStringer], so it's used by the %#v verb, and like
+// [{{$OutTypeName}}.Clone], it has a value receiver, so it's used for both values
+// and pointers.
+func (f {{$OutTypeName}}) GoString() string {
+	buf := make([]byte, 0, 64)
+	buf = append(buf, "{{.PackageName}}.{{$OutTypeName}}(0b"...)
+	buf = strconv.AppendUint(buf, uint64(f), 2)
+	buf = append(buf, ')')
+	if f&_{{$SourceTypeName}}DefinedMask != 0 {
+		buf = append(buf, " /* "...)
+		buf = f.AppendString(buf)
+		buf = append(buf, " */"...)
+	}
+	return string(buf)
+}
+{{- if .SchemaVersions}}
+{{- $Current := index .SchemaVersions (len .SchemaVersions | add -1)}}
+// _{{$SourceTypeName}}SchemaVersion is the version of the current layout of the flags
+// of [{{$OutTypeName}}], as recorded in the lock file.
+const _{{$SourceTypeName}}SchemaVersion = {{$Current.Version}}
+
+// MarshalBinary encodes the current flags value as the version of its layout,
+// in a single byte, followed by its bits, in big-endian order, like the
+// MarshalBinary method of the BitFlags types.
+// It implements [encoding.BinaryMarshaler].
+func ({{$RO}}) MarshalBinary() ([]byte, error) {
+	v := uint64({{$F}} & _{{$SourceTypeName}}DefinedMask)
+	data := make([]byte, 1, 1+{{.OutTypeSize}}/8)
+	data[0] = _{{$SourceTypeName}}SchemaVersion
+	for i := {{.OutTypeSize}} - 8; i >= 0; i -= 8 {
+		data = append(data, byte(v>>i))
+	}
+	return data, nil
+}
+
+// UnmarshalBinary decodes the data encoded by [{{$OutTypeName}}.MarshalBinary], with
+// the current layout of the flags, or any of the older layouts recorded in
+// the lock file, mapping the flags of older layouts by their names, and
+// dropping the ones that no longer exist.
+// It implements [encoding.BinaryUnmarshaler].
+func (f *{{$OutTypeName}}) UnmarshalBinary(data []byte) error {
+	if len(data) == 0 || len(data) > 9 {
+		return fmt.Errorf("invalid encoded length %d for type {{$OutTypeName}}", len(data))
+	}
+	var v uint64
+	for _, b := range data[1:] {
+		v = v<<8 | uint64(b)
+	}
+
+	switch data[0] {
+	case _{{$SourceTypeName}}SchemaVersion:
+		*f = {{$OutTypeName}}(v) & _{{$SourceTypeName}}DefinedMask
+{{- range $sv := .SchemaVersions}}
+{{- if ne $sv.Version $Current.Version}}
+	case {{$sv.Version}}:
+		var flags {{$OutTypeName}}
+{{- range $sf := $sv.Flags}}
+		flags.{{$sf.Flag.SetterTo}}(v&(1<<{{$sf.Index}}) != 0)
+{{- end}}
+		*f = flags
+{{- end}}
+{{- end}}
+	default:
+		return fmt.Errorf("unknown schema version %d for type {{$OutTypeName}}", data[0])
+	}
+	return nil
+}
+{{end}}
+{{if .HasRules}}
+// Validate reports whether the current flags value satisfies the rules
+// declared on the fields of [{{$SourceTypeName}}], returning all the violated rules
+// joined as a single error, or nil if there's none.
+func ({{$RO}}) Validate() error {
+	var errs []error
+{{- range $fv := $FlagValues}}
+{{- range $req := $fv.Requires}}
+	if f.{{$fv.Getter}}() && !f.{{$req.Getter}}() {
+		errs = append(errs, errors.New("flag {{$fv.Flag}} of type {{$OutTypeName}} requires flag {{$req.Flag}}"))
+	}
+{{- end}}
+{{- range $exc := $fv.Excludes}}
+	if f.{{$fv.Getter}}() && f.{{$exc.Getter}}() {
+		errs = append(errs, errors.New("flag {{$fv.Flag}} of type {{$OutTypeName}} excludes flag {{$exc.Flag}}"))
+	}
+{{- end}}
+{{- end}}
+{{- range $g := .FlagGroups}}
+	// More than one bit set in the group's mask.
+	if g := {{$F}} & (0{{range $fv := $g.Flags}} | 1<<_{{$SourceTypeName}}{{$fv.Flag}}BitIndex{{end}}); g&(g-1) != 0 {
+		errs = append(errs, errors.New("at most one of flags {{range $i, $fv := $g.Flags}}{{if $i}}, {{end}}{{$fv.Flag}}{{end}} in group {{$g.Name}} of type {{$OutTypeName}} can be set"))
+	}
+{{- end}}
+	return errors.Join(errs...)
+}
+{{end}}
+{{range $fv := $FlagValues}}
+func ({{$RO}}) {{$fv.Getter}}() (set bool) {
+	return {{$F}}&(1<<_{{$SourceTypeName}}{{$fv.Flag}}BitIndex) != 0
+}
+func (f *{{$OutTypeName}}) {{$fv.Setter}}() (old bool) {
+	return f.{{$fv.SetterTo}}(true)
+}
+func (f *{{$OutTypeName}}) {{$fv.Resetter}}() (old bool) {
+	return f.{{$fv.SetterTo}}(false)
+}
+func (f *{{$OutTypeName}}) {{$fv.SetterTo}}(new bool) (old bool) {
+	old = *f&(1<<_{{$SourceTypeName}}{{$fv.Flag}}BitIndex) != 0
+	if new {
+		*f |= 1 << _{{$SourceTypeName}}{{$fv.Flag}}BitIndex
+	} else {
+		*f &^= 1 << _{{$SourceTypeName}}{{$fv.Flag}}BitIndex
+	}
+	return
+}
+func (f *{{$OutTypeName}}) {{$fv.Toggler}}() (new bool) {
+	*f ^= 1 << _{{$SourceTypeName}}{{$fv.Flag}}BitIndex
+	return *f&(1<<_{{$SourceTypeName}}{{$fv.Flag}}BitIndex) != 0
+}
+{{- if $.With}}
+// With{{$fv.Flag}} returns a copy of the current flags value, with the flag for
+// field {{$fv.DocLink $SourceTypeName}} set to the new value, leaving the current
+// flags value unchanged.
+func (f {{$OutTypeName}}) With{{$fv.Flag}}(new bool) {{$OutTypeName}} {
+	f.{{$fv.SetterTo}}(new)
+	return f
+}
+{{- end}}
+{{end}}
+{{- if .Options}}
+// {{$SourceTypeName}}Option configures a [{{$OutTypeName}}] value created by [New{{$OutTypeName}}].
+type {{$SourceTypeName}}Option func(*{{$OutTypeName}})
+
+// New{{$OutTypeName}} returns a new flags value, with all flags unset, then
+// configured by the options provided, in order.
+func New{{$OutTypeName}}(opts ...{{$SourceTypeName}}Option) {{$OutTypeName}} {
+	var f {{$OutTypeName}}
+	for _, opt := range opts {
+		opt(&f)
+	}
+	return f
+}
+{{range $fv := $FlagValues}}
+// With{{$fv.Flag}} returns an option that sets the flag for field {{$fv.DocLink $SourceTypeName}}.
+func With{{$fv.Flag}}() {{$SourceTypeName}}Option {
+	return func(f *{{$OutTypeName}}) { f.{{$fv.Setter}}() }
+}
+
+// Without{{$fv.Flag}} returns an option that resets the flag for field {{$fv.DocLink $SourceTypeName}}.
+func Without{{$fv.Flag}}() {{$SourceTypeName}}Option {
+	return func(f *{{$OutTypeName}}) { f.{{$fv.Resetter}}() }
+}
+{{end}}
+{{- end}}
+{{- if .Atomic}}
+{{- $AtomicTypeName := printf "%sAtomicBitFlags" $SourceTypeName}}
+{{- $AtomicUint := .AtomicUint}}
+// {{$AtomicTypeName}} holds a [{{$OutTypeName}}] value, which can be accessed and
+// modified atomically, by multiple goroutines concurrently.
+// The zero value has all the flags unset.
+// A {{$AtomicTypeName}} must not be copied after first use.
+type {{$AtomicTypeName}} struct {
+	v atomic.{{if eq $AtomicUint "uint64"}}Uint64{{else}}Uint32{{end}}
+}
+
+// Load atomically loads and returns the flags value.
+func (f *{{$AtomicTypeName}}) Load() {{$OutTypeName}} {
+	return {{$OutTypeName}}(f.v.Load())
+}
+
+// Store atomically stores the flags value.
+func (f *{{$AtomicTypeName}}) Store(flags {{$OutTypeName}}) {
+	f.v.Store({{$AtomicUint}}(flags))
+}
+{{range $fv := $FlagValues}}
+func (f *{{$AtomicTypeName}}) {{$fv.Getter}}() (set bool) {
+	return f.v.Load()&(1<<_{{$SourceTypeName}}{{$fv.Flag}}BitIndex) != 0
+}
+func (f *{{$AtomicTypeName}}) {{$fv.Setter}}() (old bool) {
+	return f.v.Or(1<<_{{$SourceTypeName}}{{$fv.Flag}}BitIndex)&(1<<_{{$SourceTypeName}}{{$fv.Flag}}BitIndex) != 0
+}
+func (f *{{$AtomicTypeName}}) {{$fv.Resetter}}() (old bool) {
+	return f.v.And(^{{$AtomicUint}}(1<<_{{$SourceTypeName}}{{$fv.Flag}}BitIndex))&(1<<_{{$SourceTypeName}}{{$fv.Flag}}BitIndex) != 0
+}
+func (f *{{$AtomicTypeName}}) {{$fv.SetterTo}}(new bool) (old bool) {
+	if new {
+		return f.{{$fv.Setter}}()
+	}
+	return f.{{$fv.Resetter}}()
+}
+func (f *{{$AtomicTypeName}}) {{$fv.Toggler}}() (new bool) {
+	for {
+		old := f.v.Load()
+		if f.v.CompareAndSwap(old, old^(1<<_{{$SourceTypeName}}{{$fv.Flag}}BitIndex)) {
+			return old&(1<<_{{$SourceTypeName}}{{$fv.Flag}}BitIndex) == 0
+		}
+	}
+}
+{{end}}
+{{- end}}
+{{- if .Safe}}
+{{- $SafeTypeName := printf "%sSafeBitFlags" $SourceTypeName}}
+// {{$SafeTypeName}} holds a [{{$OutTypeName}}] value guarded by a mutex, which
+// can be accessed and modified by multiple goroutines concurrently.
+// The zero value has all the flags unset.
+// A {{$SafeTypeName}} must not be copied after first use.
+type {{$SafeTypeName}} struct {
+	mu sync.RWMutex
+	f  {{$OutTypeName}}
+}
+
+// Load returns the flags value.
+func (f *{{$SafeTypeName}}) Load() {{$OutTypeName}} {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	return f.f
+}
+
+// Store stores the flags value.
+func (f *{{$SafeTypeName}}) Store(flags {{$OutTypeName}}) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.f = flags
+}
+
+// Update calls fn with a pointer to the flags value while holding the lock,
+// so all the changes made by fn are observed together by other goroutines.
+// The pointer mustn't be retained after fn returns.
+func (f *{{$SafeTypeName}}) Update(fn func(flags *{{$OutTypeName}})) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	fn(&f.f)
+}
+{{range $fv := $FlagValues}}
+func (f *{{$SafeTypeName}}) {{$fv.Getter}}() (set bool) {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	return f.f.{{$fv.Getter}}()
+}
+func (f *{{$SafeTypeName}}) {{$fv.Setter}}() (old bool) {
+	return f.{{$fv.SetterTo}}(true)
+}
+func (f *{{$SafeTypeName}}) {{$fv.Resetter}}() (old bool) {
+	return f.{{$fv.SetterTo}}(false)
+}
+func (f *{{$SafeTypeName}}) {{$fv.SetterTo}}(new bool) (old bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.f.{{$fv.SetterTo}}(new)
+}
+func (f *{{$SafeTypeName}}) {{$fv.Toggler}}() (new bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.f.{{$fv.Toggler}}()
+}
+{{end}}
+{{- end}}
+{{- if .Slice}}
+{{- $SliceTypeName := printf "%sSlice" $OutTypeName}}
+// {{$SliceTypeName}} is a slice of [{{$OutTypeName}}] values, with methods operating on
+// all of its elements at once.
+type {{$SliceTypeName}} []{{$OutTypeName}}
+
+// CountMask returns the number of elements with all the flags set in mask
+// set.
+func (s {{$SliceTypeName}}) CountMask(mask {{$OutTypeName}}) int {
+	n := 0
+	for _, f := range s {
+		if f&mask == mask {
+			n++
+		}
+	}
+	return n
+}
+
+// FilterMask returns a new slice of the elements with all the flags set in
+// mask set, in order.
+func (s {{$SliceTypeName}}) FilterMask(mask {{$OutTypeName}}) {{$SliceTypeName}} {
+	var out {{$SliceTypeName}}
+	for _, f := range s {
+		if f&mask == mask {
+			out = append(out, f)
+		}
+	}
+	return out
+}
+{{range $fv := $FlagValues}}
+// Count{{$fv.Flag}} returns the number of elements with the flag for field
+// {{$fv.DocLink $SourceTypeName}} set.
+func (s {{$SliceTypeName}}) Count{{$fv.Flag}}() int {
+	return s.CountMask(1 << _{{$SourceTypeName}}{{$fv.Flag}}BitIndex)
+}
+{{end}}
+{{- end}}
+{{- if .Context}}
+// _{{$SourceTypeName}}ContextKey is the key of the [{{$OutTypeName}}] values stored in
+// contexts by [ContextWith{{$SourceTypeName}}].
+type _{{$SourceTypeName}}ContextKey struct{}
+
+// ContextWith{{$SourceTypeName}} returns a copy of ctx holding the flags value f, which
+// can be retrieved with [{{$SourceTypeName}}FromContext].
+func ContextWith{{$SourceTypeName}}(ctx context.Context, f {{$OutTypeName}}) context.Context {
+	return context.WithValue(ctx, _{{$SourceTypeName}}ContextKey{}, f)
+}
+
+// {{$SourceTypeName}}FromContext returns the flags value stored in ctx by
+// [ContextWith{{$SourceTypeName}}], and whether ctx holds any.
+func {{$SourceTypeName}}FromContext(ctx context.Context) (f {{$OutTypeName}}, ok bool) {
+	f, ok = ctx.Value(_{{$SourceTypeName}}ContextKey{}).({{$OutTypeName}})
+	return f, ok
+}
+{{end}}
+{{- if .ProtoMessage}}
+// ToProto returns the current flags value as a [{{.ProtoMessage}}] message,
+// with each flag set to the message field with the same name.
+func ({{$RO}}) ToProto() *{{.ProtoMessage}} {
+	return &{{.ProtoMessage}}{
+{{- range $fv := $FlagValues}}
+		{{$fv.Flag}}: f.{{$fv.Getter}}(),
+{{- end}}
+	}
+}
+
+// FromProto overrides the current flags value based on the fields of the
+// [{{.ProtoMessage}}] message provided, with each flag set from the message
+// field with the same name.
+// A nil message resets all the flags.
+func (f *{{$OutTypeName}}) FromProto(m *{{.ProtoMessage}}) {
+{{- range $fv := $FlagValues}}
+	f.{{$fv.SetterTo}}(m.Get{{$fv.Flag}}())
+{{- end}}
+}
+{{end}}
+{{- if .Prometheus}}
+// Collector returns a [prometheus.Collector] exporting the current state of
+// each flag as a gauge, set to 1 if the flag is set and 0 otherwise.
+// The returned collector reads the receiver value on each collection, so it
+// mustn't be modified concurrently while being collected.
+func (f *{{$OutTypeName}}) Collector() prometheus.Collector {
+	return _{{$OutTypeName}}Collector{f: f}
+}
+
+// _{{$OutTypeName}}Descs describes the gauges exported by [{{$OutTypeName}}.Collector].
+// Listed in the same order their corresponding fields are listed in [{{$SourceTypeName}}].
+var _{{$OutTypeName}}Descs = [...]*prometheus.Desc{
+{{- range $fv := $FlagValues}}
+	prometheus.NewDesc("{{snake $SourceTypeName}}_{{snake $fv.Flag}}", "Whether the {{$fv.Flag}} flag of {{$SourceTypeName}} is set.", nil, nil),
+{{- end}}
+}
+
+type _{{$OutTypeName}}Collector struct {
+	f *{{$OutTypeName}}
+}
+
+func (c _{{$OutTypeName}}Collector) Describe(ch chan<- *prometheus.Desc) {
+	for _, desc := range _{{$OutTypeName}}Descs {
+		ch <- desc
+	}
+}
+
+func (c _{{$OutTypeName}}Collector) Collect(ch chan<- prometheus.Metric) {
+{{- range $i, $fv := $FlagValues}}
+	ch <- prometheus.MustNewConstMetric(_{{$OutTypeName}}Descs[{{$i}}], prometheus.GaugeValue, _{{$OutTypeName}}Gauge(c.f.{{$fv.Getter}}()))
+{{- end}}
+}
+
+func _{{$OutTypeName}}Gauge(set bool) float64 {
+	if set {
+		return 1
+	}
+	return 0
 }
 {{end}}
 `
@@ -0,0 +1,10 @@
+package array_options
+
+//go:generate genflagged -type=Options -tests -benchmarks -fuzz -examples -mock -with -options -slice -convert -docOut=flags.md -outFile=array_options_flagged.go
+type Options struct {
+	Enabled bool
+	// Shards are the shards the option is enabled on.
+	Shards   [3]bool `flagged:"group=shard"`
+	Replicas [2]bool `flagged:"requires=Shards[0]"`
+	Debug    bool
+}
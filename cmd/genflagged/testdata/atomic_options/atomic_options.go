@@ -0,0 +1,14 @@
+package atomic_options
+
+//go:generate genflagged -type=State,wideState -atomic -outFile=atomic_options_flagged.go
+type State struct {
+	Ready    bool
+	Draining bool
+}
+
+type wideState struct {
+	Flag0, Flag1, Flag2, Flag3, Flag4, Flag5, Flag6, Flag7, Flag8, Flag9,
+	Flag10, Flag11, Flag12, Flag13, Flag14, Flag15, Flag16, Flag17, Flag18, Flag19,
+	Flag20, Flag21, Flag22, Flag23, Flag24, Flag25, Flag26, Flag27, Flag28, Flag29,
+	Flag30, Flag31, Flag32 bool
+}
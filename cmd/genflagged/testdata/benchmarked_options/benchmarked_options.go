@@ -0,0 +1,8 @@
+package benchmarked_options
+
+//go:generate genflagged -type=Permissions -benchmarks -outFile=benchmarked_options_flagged.go
+type Permissions struct {
+	Read  bool
+	Write bool
+	Exec  bool
+}
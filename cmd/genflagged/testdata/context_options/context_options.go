@@ -0,0 +1,8 @@
+package context_options
+
+//go:generate genflagged -type=Permissions -context -raw -outFile=context_options_flagged.go
+type Permissions struct {
+	Read  bool
+	Write bool
+	Exec  bool
+}
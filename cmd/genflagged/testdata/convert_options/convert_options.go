@@ -0,0 +1,8 @@
+package convert_options
+
+//go:generate genflagged -type=Permissions -convert -outFile=convert_options_flagged.go
+type Permissions struct {
+	Read  bool
+	Write bool
+	Exec  bool
+}
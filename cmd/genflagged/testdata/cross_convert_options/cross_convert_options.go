@@ -0,0 +1,18 @@
+package cross_convert_options
+
+//go:generate genflagged -type=OptionsV1,OptionsV2,unrelated -crossConvert -outFile=cross_convert_options_flagged.go
+type OptionsV1 struct {
+	Verbose bool
+	DryRun  bool
+	Force   bool
+}
+
+type OptionsV2 struct {
+	Verbose bool
+	Force   bool
+	Color   bool
+}
+
+type unrelated struct {
+	Enabled bool
+}
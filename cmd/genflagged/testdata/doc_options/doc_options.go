@@ -0,0 +1,16 @@
+package doc_options
+
+//go:generate genflagged -type=Permissions,Features -docOut=flags.md -outFile=doc_options_flagged.go
+type Permissions struct {
+	// Read allows reading the resource.
+	Read bool
+	// Write allows writing the resource,
+	// including deleting it.
+	Write bool
+	Exec  bool // Exec allows running the resource | if executable.
+	Owner string
+}
+
+type Features struct {
+	Flag0, Flag1, Flag2, Flag3, Flag4, Flag5, Flag6, Flag7, Flag8 bool // A flag.
+}
@@ -0,0 +1,9 @@
+package prometheus_reserved_options
+
+// The getter of the Collector field collides with the Collector method,
+// which is generated with -prometheus.
+//
+//go:generate genflagged -type=reservedOptions -raw -prometheus -getterName={{.Flag}} -outFile=reserved_options_flagged.go
+type reservedOptions struct {
+	Collector bool
+}
@@ -0,0 +1,9 @@
+package reserved_options
+
+// The All field collides with the SetAll method of flagged.BitFlags, which
+// is generated without -raw.
+//
+//go:generate genflagged -type=reservedOptions -outFile=reserved_options_flagged.go
+type reservedOptions struct {
+	All bool
+}
@@ -0,0 +1,8 @@
+package size_options
+
+// 12 isn't a valid size, even as the minimum one.
+//
+//go:generate genflagged -type=sizeOptions -size=auto-min:12 -outFile=size_options_flagged.go
+type sizeOptions struct {
+	Flag0, Flag1 bool
+}
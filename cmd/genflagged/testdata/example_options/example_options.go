@@ -0,0 +1,13 @@
+package example_options
+
+//go:generate genflagged -type=Permissions,settings -outType=_,settingsFlags -examples -trimprefix=Can -outFile=example_options_flagged.go
+type Permissions struct {
+	CanRead  bool
+	CanWrite bool
+	CanExec  bool
+}
+
+type settings struct {
+	enabled bool
+	debug   bool
+}
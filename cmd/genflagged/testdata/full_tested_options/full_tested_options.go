@@ -0,0 +1,12 @@
+package full_tested_options
+
+//go:generate genflagged -type=Options,settings -outType=_,settingsFlags -tests -with -options -atomic -safe -slice -context -convert -raw -outFile=full_tested_options_flagged.go
+type Options struct {
+	Verbose bool
+	Debug   bool `flagged:"requires=Verbose"`
+	Quiet   bool `flagged:"excludes=Verbose"`
+}
+
+type settings struct {
+	enabled bool
+}
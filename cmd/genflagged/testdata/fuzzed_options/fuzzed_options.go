@@ -0,0 +1,12 @@
+package fuzzed_options
+
+//go:generate genflagged -type=Permissions,wideOptions -fuzz -outFile=fuzzed_options_flagged.go
+type Permissions struct {
+	Read  bool
+	Write bool
+	Exec  bool
+}
+
+type wideOptions struct {
+	Flag0, Flag1, Flag2, Flag3, Flag4, Flag5, Flag6, Flag7, Flag8 bool
+}
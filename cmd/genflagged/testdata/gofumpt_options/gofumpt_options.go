@@ -0,0 +1,7 @@
+package gofumpt_options
+
+//go:generate genflagged -type=Permissions -format=gofumpt -tests -outFile=gofumpt_options_flagged.go
+type Permissions struct {
+	Read  bool
+	Write bool
+}
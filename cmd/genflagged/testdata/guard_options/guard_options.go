@@ -0,0 +1,19 @@
+package guard_options
+
+import (
+	"io"
+	"time"
+)
+
+type enabled = bool
+
+//go:generate genflagged -type=Options -guard -raw -outFile=guard_options_flagged.go
+type Options struct {
+	io.Reader
+
+	Verbose bool
+	Debug   enabled `flagged:"requires=Verbose"`
+	Shards  [2]bool
+	Timeout time.Duration
+	name    string
+}
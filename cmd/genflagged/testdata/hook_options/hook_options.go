@@ -0,0 +1,11 @@
+package hook_options
+
+// The hook writes the model of the generated type to model.json.
+//
+//go:generate genflagged -type=Permissions "-hook=tee model.json" -getterName=Has{{.Flag}} -outFile=hook_options_flagged.go
+type Permissions struct {
+	// Read allows reading.
+	Read  bool `flagged:"group=access"`
+	Write bool `flagged:"group=access,requires=Audit"`
+	Audit bool
+}
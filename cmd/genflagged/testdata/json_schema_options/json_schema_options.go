@@ -0,0 +1,9 @@
+package json_schema_options
+
+//go:generate genflagged -type=Permissions -jsonSchema -tests -outFile=json_schema_options_flagged.go
+type Permissions struct {
+	// Read allows reading the "data" files.
+	Read  bool
+	Write bool // Write allows writing.
+	Exec  bool
+}
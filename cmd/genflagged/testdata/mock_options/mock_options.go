@@ -0,0 +1,11 @@
+package mock_options
+
+//go:generate genflagged -type=Options,Flags -mock -tests -outFile=mock_options_flagged.go
+type Options struct {
+	Verbose bool
+	DryRun  bool
+}
+
+type Flags struct {
+	Force bool
+}
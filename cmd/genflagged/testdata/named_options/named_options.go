@@ -0,0 +1,11 @@
+package named_options
+
+//go:generate genflagged -type=Permissions -getterName=Has{{.Flag}} -setterName=Enable{{.Flag}} -resetterName=Disable{{.Flag}} -setterToName=Enable{{.Flag}}If -togglerName=Flip{{.Field}} -tests -mock -benchmarks -fuzz -examples -atomic -safe -slice -options -with -crossConvert -outFile=named_options_flagged.go
+type Permissions struct {
+	Read  bool
+	Write bool `flagged:"requires=Read"`
+}
+
+type Grants struct {
+	Read bool
+}
@@ -0,0 +1,8 @@
+package options_constructor
+
+//go:generate genflagged -type=Permissions -options -outFile=options_constructor_flagged.go
+type Permissions struct {
+	Read  bool
+	Write bool
+	Exec  bool
+}
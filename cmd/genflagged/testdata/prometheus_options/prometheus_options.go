@@ -0,0 +1,9 @@
+package prometheus_options
+
+//go:generate genflagged -type=ServerOptions -prometheus -outFile=prometheus_options_flagged.go
+type ServerOptions struct {
+	EnableTLS   bool
+	HTTP2       bool
+	AccessLogs  bool
+	maintenance bool
+}
@@ -0,0 +1,12 @@
+package proto_options
+
+//go:generate genflagged -type=Options,legacyOptions -proto=example.com/gen/optionspb.Options,_ -outFile=proto_options_flagged.go
+type Options struct {
+	Verbose bool
+	DryRun  bool
+	Force   bool
+}
+
+type legacyOptions struct {
+	Verbose bool
+}
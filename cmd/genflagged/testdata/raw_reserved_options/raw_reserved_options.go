@@ -0,0 +1,11 @@
+package raw_reserved_options
+
+// The fields are named like methods of flagged.BitFlags, which aren't
+// generated in raw mode, so they don't collide.
+//
+//go:generate genflagged -type=rawReservedOptions -raw -tests -outFile=raw_reserved_options_flagged.go
+type rawReservedOptions struct {
+	All  bool
+	Bits bool
+	Size bool
+}
@@ -0,0 +1,11 @@
+package rules_options
+
+//go:generate genflagged -type=Permissions -mock -tests -outFile=rules_options_flagged.go
+type Permissions struct {
+	Read   bool `flagged:"group=access"`
+	Write  bool `flagged:"group=access,requires=Audit"`
+	Admin  bool `json:"admin" flagged:"group=access,excludes=Guest"`
+	Audit  bool
+	Guest  bool `flagged:"excludes=Audit,excludes=Admin"`
+	Legacy bool
+}
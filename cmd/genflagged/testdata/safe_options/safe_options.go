@@ -0,0 +1,7 @@
+package safe_options
+
+//go:generate genflagged -type=State -safe -outFile=safe_options_flagged.go
+type State struct {
+	Ready    bool
+	Draining bool
+}
@@ -0,0 +1,13 @@
+package size_auto_min_options
+
+// Small fits in the requested size, while Large is generated with the next
+// valid size, instead of failing.
+//
+//go:generate genflagged -type=Small,Large -size=auto-min:8 -raw -outFile=size_auto_min_options_flagged.go
+type Small struct {
+	Flag0, Flag1 bool
+}
+
+type Large struct {
+	Flag0, Flag1, Flag2, Flag3, Flag4, Flag5, Flag6, Flag7, Flag8 bool
+}
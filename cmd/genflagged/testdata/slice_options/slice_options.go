@@ -0,0 +1,8 @@
+package slice_options
+
+//go:generate genflagged -type=Permissions -slice -outFile=slice_options_flagged.go
+type Permissions struct {
+	Read  bool
+	Write bool
+	Exec  bool
+}
@@ -0,0 +1,3 @@
+module example.com/gen/optionspb
+
+go 1.23
@@ -0,0 +1,13 @@
+// Package optionspb is a stub of the protobuf Go package of the
+// proto_options fixture, so the golden tests can build its output.
+package optionspb
+
+type Options struct {
+	Verbose bool
+	DryRun  bool
+	Force   bool
+}
+
+func (m *Options) GetVerbose() bool { return m != nil && m.Verbose }
+func (m *Options) GetDryRun() bool  { return m != nil && m.DryRun }
+func (m *Options) GetForce() bool   { return m != nil && m.Force }
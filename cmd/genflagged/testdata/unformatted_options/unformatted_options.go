@@ -0,0 +1,7 @@
+package unformatted_options
+
+//go:generate genflagged -type=Permissions -format=none -raw -outFile=unformatted_options_flagged.go
+type Permissions struct {
+	Read  bool
+	Write bool
+}
@@ -0,0 +1,13 @@
+package value_receivers_options
+
+//go:generate genflagged -type=Permissions -valueReceivers -tests -mock -outFile=value_receivers_options_flagged.go
+type Permissions struct {
+	Read  bool
+	Write bool `flagged:"requires=Read"`
+	Exec  bool
+}
+
+// isReadable uses the generated methods on a non-addressable map entry.
+func isReadable(m map[string]PermissionsBitFlags, key string) bool {
+	return m[key].IsRead() && m[key].TypedFlags().Read && m[key].String() != ""
+}
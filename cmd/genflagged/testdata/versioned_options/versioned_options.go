@@ -0,0 +1,11 @@
+package versioned_options
+
+// Permissions had an Exec flag, and no Admin flag, in the first version, and
+// the Write flag was moved before the Read flag in the second version.
+//
+//go:generate genflagged -type=Permissions -lockFile=flagged.lock.json -tests -fuzz -mock -outFile=versioned_options_flagged.go
+type Permissions struct {
+	Write bool
+	Read  bool
+	Admin bool
+}
@@ -0,0 +1,7 @@
+package with_options
+
+//go:generate genflagged -type=Config -with -outFile=with_options_flagged.go
+type Config struct {
+	Debug   bool
+	Metrics bool
+}
@@ -6,8 +6,12 @@ import (
 	"go/token"
 	"log"
 	"os"
+	"os/exec"
+	"path"
 	"path/filepath"
+	"strconv"
 	"strings"
+	"unicode"
 )
 
 type input struct {
@@ -16,13 +20,37 @@ type input struct {
 	trimPrefix      string
 	trimSuffix      string
 	flagsSize       int
+	sizeAutoMin     bool
+	methodNames     methodNames
 	raw             bool
+	valueReceivers  bool
 	genTests        bool
+	benchmarks      bool
+	fuzz            bool
+	examples        bool
+	mock            bool
+	with            bool
+	options         bool
+	atomic          bool
+	safe            bool
+	slice           bool
+	context         bool
+	convert         bool
+	crossConvert    bool
+	prometheus      bool
+	guard           bool
+	jsonSchema      bool
+	protoMessages   map[string]protoMessage
 
 	outFile string
+	docOut  string
 	outDir  string
 
+	lockFile string
+	hook     []string
+
 	buildTags string
+	formatter string
 
 	patterns []string
 }
@@ -52,12 +80,58 @@ func validateFlags() *input {
 		}
 	}
 
+	// Validate the proto argument, if passed, and match each message to its
+	// source type.
+	var protoMessages map[string]protoMessage
+	if len(*protoFlag) != 0 {
+		protoArgs := strings.Split(*protoFlag, ",")
+		if len(protoArgs) != len(sourceTypeNames) {
+			log.Fatalf("error: type argument doesn't match proto argument: %s", *protoFlag)
+		}
+		protoMessages = make(map[string]protoMessage, len(protoArgs))
+		for idx, arg := range protoArgs {
+			if arg == "_" {
+				continue
+			}
+			msg, err := parseProtoMessage(arg)
+			if err != nil {
+				log.Fatalf("error: invalid proto argument: %s", err)
+			}
+			protoMessages[sourceTypeNames[idx]] = msg
+		}
+	}
+
+	// Validate the method name templates.
+	methodNames, err := parseMethodNames(
+		*getterNameFlag,
+		*setterNameFlag,
+		*resetterNameFlag,
+		*setterToNameFlag,
+		*togglerNameFlag,
+	)
+	if err != nil {
+		log.Fatalf("error: invalid method name argument: %s", err)
+	}
+
 	// Validate the size argument, if passed.
-	if *sizeFlag != 0 {
-		switch *sizeFlag {
-		case 8, 16, 32, 64:
-		default:
-			log.Fatalf("error: invalid size argument %d; supported values are 8,16,32,64", *sizeFlag)
+	flagsSize, sizeAutoMin, err := parseSize(*sizeFlag)
+	if err != nil {
+		log.Fatalf("error: invalid size argument %s: %s", *sizeFlag, err)
+	}
+
+	// Validate the format argument.
+	switch *formatFlag {
+	case formatGofmt, formatGofumpt, formatNone:
+	default:
+		log.Fatalf("error: invalid format argument %q; supported values are gofmt,gofumpt,none", *formatFlag)
+	}
+
+	// Validate the hook argument, if passed, and make sure its command can
+	// be run before generating anything.
+	hook := strings.Fields(*hookFlag)
+	if len(hook) > 0 {
+		if _, err := exec.LookPath(hook[0]); err != nil {
+			log.Fatalf("error: invalid hook argument %q: %s", *hookFlag, err)
 		}
 	}
 
@@ -76,12 +150,35 @@ func validateFlags() *input {
 		outTypeNames:    outTypeNames,
 		trimPrefix:      *trimprefixFlag,
 		trimSuffix:      *trimsuffixFlag,
-		flagsSize:       *sizeFlag,
+		flagsSize:       flagsSize,
+		sizeAutoMin:     sizeAutoMin,
+		methodNames:     methodNames,
 		raw:             *rawFlag,
+		valueReceivers:  *valueReceiversFlag,
 		genTests:        *testsFlag,
+		benchmarks:      *benchmarksFlag,
+		fuzz:            *fuzzFlag,
+		examples:        *examplesFlag,
+		mock:            *mockFlag,
+		with:            *withFlag,
+		options:         *optionsFlag,
+		atomic:          *atomicFlag,
+		safe:            *safeFlag,
+		slice:           *sliceFlag,
+		context:         *contextFlag,
+		convert:         *convertFlag,
+		crossConvert:    *crossConvertFlag,
+		prometheus:      *prometheusFlag,
+		guard:           *guardFlag,
+		jsonSchema:      *jsonSchemaFlag,
+		protoMessages:   protoMessages,
 		outFile:         *outFileFlag,
+		docOut:          *docOutFlag,
+		lockFile:        *lockFileFlag,
+		hook:            hook,
 		outDir:          outputDir,
 		buildTags:       *buildTagsFlag,
+		formatter:       *formatFlag,
 		patterns:        args,
 	}
 }
@@ -95,6 +192,86 @@ func validateTypeNames(typeNames []string) error {
 	return nil
 }
 
+// sizeAutoMinPrefix prefixes the -size values that are the minimum size,
+// rather than the exact size, of the generated types.
+const sizeAutoMinPrefix = "auto-min"
+
+// parseSize parses a -size flag value, which is one of 8, 16, 32 or 64,
+// optionally in the form "auto-min:<size>", like "auto-min:16", making it
+// the minimum size, or just "auto-min", which is the same as "auto-min:8".
+// It returns a zero size for an empty value.
+func parseSize(arg string) (size int, autoMin bool, err error) {
+	if arg == "" {
+		return 0, false, nil
+	}
+	if rest, ok := strings.CutPrefix(arg, sizeAutoMinPrefix); ok {
+		if rest == "" {
+			return 8, true, nil
+		}
+		if arg, ok = strings.CutPrefix(rest, ":"); !ok {
+			return 0, false, fmt.Errorf("expected %s or %s:<size>", sizeAutoMinPrefix, sizeAutoMinPrefix)
+		}
+		autoMin = true
+	}
+	switch arg {
+	case "8", "16", "32", "64":
+		size, _ = strconv.Atoi(arg)
+		return size, autoMin, nil
+	default:
+		return 0, false, fmt.Errorf("supported sizes are 8,16,32,64")
+	}
+}
+
+// protoMessage is a protobuf Go message type, from the -proto flag.
+type protoMessage struct {
+	importPath string // the Go import path of the message's package.
+	importName string // the name the package is imported with, if not its last path element.
+	pkgName    string // the name the package is referenced with in the generated code.
+	message    string // the message type name.
+}
+
+// qualifiedName returns the message type name as referenced in the
+// generated code, or "" for the zero protoMessage.
+func (m protoMessage) qualifiedName() string {
+	if m.message == "" {
+		return ""
+	}
+	return m.pkgName + "." + m.message
+}
+
+// parseProtoMessage parses a message in the form 'importpath.Message'.
+func parseProtoMessage(arg string) (protoMessage, error) {
+	dot := strings.LastIndexByte(arg, '.')
+	if dot <= 0 || strings.HasSuffix(arg[:dot], "/") {
+		return protoMessage{}, fmt.Errorf("invalid proto message %q; want importpath.Message", arg)
+	}
+	importPath, message := arg[:dot], arg[dot+1:]
+	if !token.IsExported(message) || !token.IsIdentifier(message) {
+		return protoMessage{}, fmt.Errorf("invalid proto message type %q in %q", message, arg)
+	}
+
+	// Assume the package name is the last path element, and import it with
+	// an explicit name if it can't be used as is.
+	msg := protoMessage{
+		importPath: importPath,
+		pkgName:    path.Base(importPath),
+		message:    message,
+	}
+	if !token.IsIdentifier(msg.pkgName) {
+		msg.pkgName = strings.Map(func(r rune) rune {
+			if r == '_' || unicode.IsLetter(r) || unicode.IsDigit(r) {
+				return r
+			}
+			return -1
+		}, msg.pkgName)
+		if !token.IsIdentifier(msg.pkgName) {
+			return protoMessage{}, fmt.Errorf("invalid proto import path %q in %q", importPath, arg)
+		}
+		msg.importName = msg.pkgName
+	}
+	return msg, nil
+}
+
 func getDirFromArgs(args []string, tags string) string {
 	var dir string
 	if len(args) == 1 && isDirectory(args[0]) {
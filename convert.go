@@ -0,0 +1,215 @@
+package flagged
+
+import "errors"
+
+// ErrOverflow is returned when a value has set bits beyond the size of
+// the BitFlags type it's converted to.
+var ErrOverflow = errors.New("set bits overflow the flags size")
+
+// OverflowPolicy decides what happens when a value has set bits beyond
+// the size of the BitFlags type it's copied to.
+type OverflowPolicy int
+
+const (
+	// OverflowError returns [ErrOverflow], without changing the destination.
+	OverflowError OverflowPolicy = iota
+
+	// OverflowTruncate drops the set bits beyond the destination size.
+	OverflowTruncate
+
+	// OverflowPanic panics, without changing the destination.
+	OverflowPanic
+)
+
+// FromBools returns a value of one of the BitFlags types with the bit at
+// each index i set to b[i], like:
+//
+//	f := flagged.FromBools[flagged.BitFlags8]([]bool{true, false, true})
+//
+// It panics if len(b) is more than the size of T.
+func FromBools[T BitFlags8 | BitFlags16 | BitFlags32 | BitFlags64](b []bool) (f T) {
+	size := sizeOf[T]()
+	// Check the length even with the flagged_unsafe tag, which only drops
+	// the validation of the indexes, as b isn't an index.
+	if len(b) > size {
+		panic(checkBitIndex(size, len(b)-1))
+	}
+	for i, set := range b {
+		if set {
+			f |= 1 << i
+		}
+	}
+	return f
+}
+
+func toBools[T bitFlagsTypes](f T, size int) []bool {
+	b := make([]bool, size)
+	for i := range b {
+		b[i] = isUint(f, i)
+	}
+	return b
+}
+
+// ToBools returns the bits as a []bool of length 8, with the element at
+// each index i set to the bit at index i. It's the inverse of [FromBools].
+func (f BitFlags8) ToBools() []bool { return toBools(f, 8) }
+
+// ToBools returns the bits as a []bool of length 16, with the element at
+// each index i set to the bit at index i. It's the inverse of [FromBools].
+func (f BitFlags16) ToBools() []bool { return toBools(f, 16) }
+
+// ToBools returns the bits as a []bool of length 32, with the element at
+// each index i set to the bit at index i. It's the inverse of [FromBools].
+func (f BitFlags32) ToBools() []bool { return toBools(f, 32) }
+
+// ToBools returns the bits as a []bool of length 64, with the element at
+// each index i set to the bit at index i. It's the inverse of [FromBools].
+func (f BitFlags64) ToBools() []bool { return toBools(f, 64) }
+
+func copyFromBitFlags[T bitFlags](f *T, size int, src BitFlags, policy OverflowPolicy) error {
+	v := src.Uint64()
+	if size < 64 && v>>size != 0 {
+		switch policy {
+		case OverflowTruncate:
+			v &= 1<<size - 1
+		case OverflowPanic:
+			panic(ValueOverflowError{Value: v, Size: size})
+		default:
+			return ErrOverflow
+		}
+	}
+	*f = T(v)
+	return nil
+}
+
+// CopyFrom overrides the bits with those of src, which can be of any size,
+// applying policy if src has set bits beyond index 7.
+func (f *BitFlags8) CopyFrom(src BitFlags, policy OverflowPolicy) error {
+	return copyFromBitFlags(f, 8, src, policy)
+}
+
+// CopyFrom overrides the bits with those of src, which can be of any size,
+// applying policy if src has set bits beyond index 15.
+func (f *BitFlags16) CopyFrom(src BitFlags, policy OverflowPolicy) error {
+	return copyFromBitFlags(f, 16, src, policy)
+}
+
+// CopyFrom overrides the bits with those of src, which can be of any size,
+// applying policy if src has set bits beyond index 31.
+func (f *BitFlags32) CopyFrom(src BitFlags, policy OverflowPolicy) error {
+	return copyFromBitFlags(f, 32, src, policy)
+}
+
+// CopyFrom overrides the bits with those of src, which can be of any size,
+// applying policy if src has set bits beyond index 63.
+func (f *BitFlags64) CopyFrom(src BitFlags, policy OverflowPolicy) error {
+	return copyFromBitFlags(f, 64, src, policy)
+}
+
+// narrow converts f to the narrower type T, returning [ErrOverflow], and
+// the zero value, if f has set bits beyond the size of T.
+func narrow[T bitFlags, F bitFlags](f F) (T, error) {
+	if size := sizeOf[T](); uint64(f)>>size != 0 {
+		return 0, ErrOverflow
+	}
+	return T(f), nil
+}
+
+// To16 returns f as a [BitFlags16], with the same bits set.
+func (f BitFlags8) To16() BitFlags16 { return BitFlags16(f) }
+
+// To32 returns f as a [BitFlags32], with the same bits set.
+func (f BitFlags8) To32() BitFlags32 { return BitFlags32(f) }
+
+// To64 returns f as a [BitFlags64], with the same bits set.
+func (f BitFlags8) To64() BitFlags64 { return BitFlags64(f) }
+
+// To8 returns f as a [BitFlags8], or [ErrOverflow] if f has set bits beyond
+// index 7, instead of silently dropping them.
+func (f BitFlags16) To8() (BitFlags8, error) { return narrow[BitFlags8](f) }
+
+// To32 returns f as a [BitFlags32], with the same bits set.
+func (f BitFlags16) To32() BitFlags32 { return BitFlags32(f) }
+
+// To64 returns f as a [BitFlags64], with the same bits set.
+func (f BitFlags16) To64() BitFlags64 { return BitFlags64(f) }
+
+// To8 returns f as a [BitFlags8], or [ErrOverflow] if f has set bits beyond
+// index 7, instead of silently dropping them.
+func (f BitFlags32) To8() (BitFlags8, error) { return narrow[BitFlags8](f) }
+
+// To16 returns f as a [BitFlags16], or [ErrOverflow] if f has set bits beyond
+// index 15, instead of silently dropping them.
+func (f BitFlags32) To16() (BitFlags16, error) { return narrow[BitFlags16](f) }
+
+// To64 returns f as a [BitFlags64], with the same bits set.
+func (f BitFlags32) To64() BitFlags64 { return BitFlags64(f) }
+
+// To8 returns f as a [BitFlags8], or [ErrOverflow] if f has set bits beyond
+// index 7, instead of silently dropping them.
+func (f BitFlags64) To8() (BitFlags8, error) { return narrow[BitFlags8](f) }
+
+// To16 returns f as a [BitFlags16], or [ErrOverflow] if f has set bits beyond
+// index 15, instead of silently dropping them.
+func (f BitFlags64) To16() (BitFlags16, error) { return narrow[BitFlags16](f) }
+
+// To32 returns f as a [BitFlags32], or [ErrOverflow] if f has set bits beyond
+// index 31, instead of silently dropping them.
+func (f BitFlags64) To32() (BitFlags32, error) { return narrow[BitFlags32](f) }
+
+// Split returns the bits of f as 2 [BitFlags8] chunks, from the lowest
+// one, so the bit at index i is the bit at index i%8 of the chunk i/8.
+// It's the inverse of [Join16].
+func (f BitFlags16) Split() (chunks [2]BitFlags8) {
+	for i := range chunks {
+		chunks[i] = BitFlags8(f >> (8 * i))
+	}
+	return chunks
+}
+
+// Join16 returns the [BitFlags16] value of the 2 [BitFlags8] chunks,
+// from the lowest one. It's the inverse of [BitFlags16.Split].
+func Join16(chunks [2]BitFlags8) (f BitFlags16) {
+	for i, c := range chunks {
+		f |= BitFlags16(c) << (8 * i)
+	}
+	return f
+}
+
+// Split returns the bits of f as 4 [BitFlags8] chunks, from the lowest
+// one, so the bit at index i is the bit at index i%8 of the chunk i/8.
+// It's the inverse of [Join32].
+func (f BitFlags32) Split() (chunks [4]BitFlags8) {
+	for i := range chunks {
+		chunks[i] = BitFlags8(f >> (8 * i))
+	}
+	return chunks
+}
+
+// Join32 returns the [BitFlags32] value of the 4 [BitFlags8] chunks,
+// from the lowest one. It's the inverse of [BitFlags32.Split].
+func Join32(chunks [4]BitFlags8) (f BitFlags32) {
+	for i, c := range chunks {
+		f |= BitFlags32(c) << (8 * i)
+	}
+	return f
+}
+
+// Split returns the bits of f as 8 [BitFlags8] chunks, from the lowest
+// one, so the bit at index i is the bit at index i%8 of the chunk i/8.
+// It's the inverse of [Join64].
+func (f BitFlags64) Split() (chunks [8]BitFlags8) {
+	for i := range chunks {
+		chunks[i] = BitFlags8(f >> (8 * i))
+	}
+	return chunks
+}
+
+// Join64 returns the [BitFlags64] value of the 8 [BitFlags8] chunks,
+// from the lowest one. It's the inverse of [BitFlags64.Split].
+func Join64(chunks [8]BitFlags8) (f BitFlags64) {
+	for i, c := range chunks {
+		f |= BitFlags64(c) << (8 * i)
+	}
+	return f
+}
@@ -0,0 +1,246 @@
+package flagged
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"testing"
+)
+
+func helperRunTestBools[T bitFlags, TP interface {
+	ptrBitFlags[T]
+	ToBools() []bool
+}](t *testing.T) {
+	var (
+		zero   T
+		allset = ^zero
+		size   = TP(&zero).Size()
+	)
+	type testCase struct {
+		name   string
+		bools  []bool
+		want   T
+		panics bool
+	}
+	tests := []testCase{
+		{
+			name:  "empty",
+			bools: nil,
+			want:  zero,
+		},
+		{
+			name:  "short",
+			bools: []bool{true, false, true},
+			want:  zero | 0b0101,
+		},
+		{
+			name:  "allset",
+			bools: slices.Repeat([]bool{true}, size),
+			want:  allset,
+		},
+		{
+			name:   "too long",
+			bools:  make([]bool, size+1),
+			panics: true,
+		},
+	}
+	t.Run(fmt.Sprintf("%T", zero), func(t *testing.T) {
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				defer func() {
+					if r := recover(); (r != nil) != tt.panics {
+						t.Errorf("FromBools() panic = %v, want panic = %v", r, tt.panics)
+					}
+				}()
+
+				f := FromBools[T](tt.bools)
+				if f != tt.want {
+					t.Errorf("FromBools() = %v, want = %v", TP(&f).String(), TP(&tt.want).String())
+				}
+
+				want := make([]bool, size)
+				copy(want, tt.bools)
+				if got := TP(&f).ToBools(); !slices.Equal(got, want) {
+					t.Errorf("ToBools() = %v, want = %v", got, want)
+				}
+			})
+		}
+	})
+}
+
+func TestBitFlags_Bools(t *testing.T) {
+	helperRunTestBools[BitFlags8](t)
+	helperRunTestBools[BitFlags16](t)
+	helperRunTestBools[BitFlags32](t)
+	helperRunTestBools[BitFlags64](t)
+}
+
+func helperRunTestCopyFrom[T bitFlags, TP interface {
+	ptrBitFlags[T]
+	CopyFrom(src BitFlags, policy OverflowPolicy) error
+}](t *testing.T) {
+	var (
+		zero T
+		size = TP(&zero).Size()
+	)
+	type testCase struct {
+		name    string
+		initial T
+		src     BitFlags
+		policy  OverflowPolicy
+		want    T
+		wantErr error
+		panics  bool
+	}
+	tests := []testCase{
+		{
+			name:    "fits",
+			initial: zero | 0b1000,
+			src:     New[BitFlags64](0b0101),
+			policy:  OverflowError,
+			want:    zero | 0b0101,
+		},
+		{
+			name:    "smaller src",
+			initial: zero | 0b1000,
+			src:     New[BitFlags8](0xFF),
+			policy:  OverflowError,
+			want:    zero | 0xFF,
+		},
+	}
+	if size < 64 {
+		overflow := New[BitFlags64](0b0101 | 1<<size)
+		tests = append(tests,
+			testCase{
+				name:    "overflow error",
+				initial: zero | 0b1000,
+				src:     overflow,
+				policy:  OverflowError,
+				want:    zero | 0b1000,
+				wantErr: ErrOverflow,
+			},
+			testCase{
+				name:    "overflow truncate",
+				initial: zero | 0b1000,
+				src:     overflow,
+				policy:  OverflowTruncate,
+				want:    zero | 0b0101,
+			},
+			testCase{
+				name:    "overflow panic",
+				initial: zero | 0b1000,
+				src:     overflow,
+				policy:  OverflowPanic,
+				want:    zero | 0b1000,
+				panics:  true,
+			},
+		)
+	}
+	t.Run(fmt.Sprintf("%T", zero), func(t *testing.T) {
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				f := tt.initial
+				func() {
+					defer func() {
+						r := recover()
+						if (r != nil) != tt.panics {
+							t.Errorf("CopyFrom() panic = %v, want panic = %v", r, tt.panics)
+						}
+						var overflowErr ValueOverflowError
+						if err, _ := r.(error); r != nil && !errors.As(err, &overflowErr) {
+							t.Errorf("CopyFrom() panic = %v, want a ValueOverflowError", r)
+						}
+					}()
+					if err := TP(&f).CopyFrom(tt.src, tt.policy); err != tt.wantErr {
+						t.Errorf("CopyFrom() error = %v, want = %v", err, tt.wantErr)
+					}
+				}()
+				if f != tt.want {
+					t.Errorf("CopyFrom() = %v, want = %v", TP(&f).String(), TP(&tt.want).String())
+				}
+			})
+		}
+	})
+}
+
+func TestBitFlags_CopyFrom(t *testing.T) {
+	helperRunTestCopyFrom[BitFlags8](t)
+	helperRunTestCopyFrom[BitFlags16](t)
+	helperRunTestCopyFrom[BitFlags32](t)
+	helperRunTestCopyFrom[BitFlags64](t)
+}
+
+func TestBitFlags_Widen(t *testing.T) {
+	f8 := BitFlags8(0b1000_0101)
+	if got := f8.To16(); got != 0b1000_0101 {
+		t.Errorf("To16() = %v", got)
+	}
+	if got := f8.To32(); got != 0b1000_0101 {
+		t.Errorf("To32() = %v", got)
+	}
+	if got := f8.To64(); got != 0b1000_0101 {
+		t.Errorf("To64() = %v", got)
+	}
+	f16 := BitFlags16(0x8005)
+	if got := f16.To32(); got != 0x8005 {
+		t.Errorf("To32() = %v", got)
+	}
+	if got := f16.To64(); got != 0x8005 {
+		t.Errorf("To64() = %v", got)
+	}
+	f32 := BitFlags32(0x8000_0005)
+	if got := f32.To64(); got != 0x8000_0005 {
+		t.Errorf("To64() = %v", got)
+	}
+}
+
+func TestBitFlags_Narrow(t *testing.T) {
+	tests := []struct {
+		name    string
+		narrow  func() (uint64, error)
+		want    uint64
+		wantErr error
+	}{
+		{"16 to 8", func() (uint64, error) { f, err := BitFlags16(0x85).To8(); return uint64(f), err }, 0x85, nil},
+		{"16 to 8 overflow", func() (uint64, error) { f, err := BitFlags16(0x185).To8(); return uint64(f), err }, 0, ErrOverflow},
+		{"32 to 8", func() (uint64, error) { f, err := BitFlags32(0x85).To8(); return uint64(f), err }, 0x85, nil},
+		{"32 to 16 overflow", func() (uint64, error) { f, err := BitFlags32(1 << 31).To16(); return uint64(f), err }, 0, ErrOverflow},
+		{"64 to 32", func() (uint64, error) { f, err := BitFlags64(0x8000_0005).To32(); return uint64(f), err }, 0x8000_0005, nil},
+		{"64 to 16", func() (uint64, error) { f, err := BitFlags64(0x8005).To16(); return uint64(f), err }, 0x8005, nil},
+		{"64 to 8 overflow", func() (uint64, error) { f, err := BitFlags64(1 << 63).To8(); return uint64(f), err }, 0, ErrOverflow},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.narrow()
+			if got != tt.want || err != tt.wantErr {
+				t.Errorf("got = %#x, %v, want = %#x, %v", got, err, tt.want, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestBitFlags_SplitJoin(t *testing.T) {
+	f16 := BitFlags16(0x8001)
+	if got, want := f16.Split(), [2]BitFlags8{0x01, 0x80}; got != want {
+		t.Errorf("BitFlags16.Split() = %v, want = %v", got, want)
+	}
+	if got := Join16(f16.Split()); got != f16 {
+		t.Errorf("Join16() = %v, want = %v", got, f16)
+	}
+
+	f32 := BitFlags32(0x8004_0201)
+	if got, want := f32.Split(), [4]BitFlags8{0x01, 0x02, 0x04, 0x80}; got != want {
+		t.Errorf("BitFlags32.Split() = %v, want = %v", got, want)
+	}
+	if got := Join32(f32.Split()); got != f32 {
+		t.Errorf("Join32() = %v, want = %v", got, f32)
+	}
+
+	f64 := BitFlags64(0x8040_2010_0804_0201)
+	if got, want := f64.Split(), [8]BitFlags8{0x01, 0x02, 0x04, 0x08, 0x10, 0x20, 0x40, 0x80}; got != want {
+		t.Errorf("BitFlags64.Split() = %v, want = %v", got, want)
+	}
+	if got := Join64(f64.Split()); got != f64 {
+		t.Errorf("Join64() = %v, want = %v", got, f64)
+	}
+}
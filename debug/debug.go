@@ -0,0 +1,205 @@
+// Package debug provides a [flagged.BitFlags] wrapper that detects
+// unsynchronized concurrent writes, for finding races in code that assumes
+// its flags are read-only, or otherwise guarded.
+//
+// Like the runtime checks of concurrent map writes, the detection is best
+// effort: it only catches the accesses that overlap in time. It's meant for
+// development and tests, not for production.
+package debug
+
+import (
+	"iter"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync/atomic"
+
+	"github.com/asmsh/flagged"
+)
+
+// Flags wraps a [flagged.BitFlags] value, and panics when a write to it
+// overlaps with another write, or with a read, from a different goroutine.
+// The panic message includes the stack of the last writer.
+type Flags struct {
+	f       flagged.BitFlags
+	writing atomic.Bool
+	writer  atomic.Pointer[[]uintptr] // the stack of the last writer.
+}
+
+// Make sure [Flags] implements [flagged.BitFlags].
+var _ flagged.BitFlags = (*Flags)(nil)
+
+// Wrap returns a [Flags] wrapping f.
+// All the accesses to f must go through the returned [Flags].
+func Wrap(f flagged.BitFlags) *Flags {
+	return &Flags{f: f}
+}
+
+// Unwrap returns the wrapped [flagged.BitFlags] value.
+func (d *Flags) Unwrap() flagged.BitFlags {
+	return d.f
+}
+
+func (d *Flags) beginWrite() {
+	if !d.writing.CompareAndSwap(false, true) {
+		d.throw("concurrent BitFlags writes")
+	}
+	pcs := make([]uintptr, 32)
+	pcs = pcs[:runtime.Callers(2, pcs)]
+	d.writer.Store(&pcs)
+}
+
+func (d *Flags) endWrite() {
+	d.writing.Store(false)
+}
+
+func (d *Flags) checkRead() {
+	if d.writing.Load() {
+		d.throw("concurrent BitFlags read and write")
+	}
+}
+
+// throw panics with msg, followed by the stack of the last writer.
+func (d *Flags) throw(msg string) {
+	var sb strings.Builder
+	sb.WriteString(msg)
+	if pcs := d.writer.Load(); pcs != nil {
+		sb.WriteString("\n\nlast writer:\n")
+		frames := runtime.CallersFrames(*pcs)
+		for {
+			frame, more := frames.Next()
+			sb.WriteString(frame.Function)
+			sb.WriteString("\n\t")
+			sb.WriteString(frame.File)
+			sb.WriteByte(':')
+			sb.WriteString(strconv.Itoa(frame.Line))
+			sb.WriteByte('\n')
+			if !more {
+				break
+			}
+		}
+	}
+	panic(sb.String())
+}
+
+func (d *Flags) Is(idx flagged.BitIndex) (set bool) {
+	d.checkRead()
+	return d.f.Is(idx)
+}
+
+func (d *Flags) Set(idx flagged.BitIndex) (old bool) {
+	d.beginWrite()
+	defer d.endWrite()
+	return d.f.Set(idx)
+}
+
+func (d *Flags) Reset(idx flagged.BitIndex) (old bool) {
+	d.beginWrite()
+	defer d.endWrite()
+	return d.f.Reset(idx)
+}
+
+func (d *Flags) SetTo(idx flagged.BitIndex, new bool) (old bool) {
+	d.beginWrite()
+	defer d.endWrite()
+	return d.f.SetTo(idx, new)
+}
+
+func (d *Flags) Toggle(idx flagged.BitIndex) (new bool) {
+	d.beginWrite()
+	defer d.endWrite()
+	return d.f.Toggle(idx)
+}
+
+func (d *Flags) SetAll() {
+	d.beginWrite()
+	defer d.endWrite()
+	d.f.SetAll()
+}
+
+func (d *Flags) ResetAll() {
+	d.beginWrite()
+	defer d.endWrite()
+	d.f.ResetAll()
+}
+
+func (d *Flags) SetUint64(v uint64) {
+	d.beginWrite()
+	defer d.endWrite()
+	d.f.SetUint64(v)
+}
+
+func (d *Flags) AnySet() bool {
+	d.checkRead()
+	return d.f.AnySet()
+}
+
+func (d *Flags) AllSet() bool {
+	d.checkRead()
+	return d.f.AllSet()
+}
+
+func (d *Flags) AnyOf(idx ...flagged.BitIndex) bool {
+	d.checkRead()
+	return d.f.AnyOf(idx...)
+}
+
+func (d *Flags) AllOf(idx ...flagged.BitIndex) bool {
+	d.checkRead()
+	return d.f.AllOf(idx...)
+}
+
+func (d *Flags) Size() int {
+	return d.f.Size()
+}
+
+func (d *Flags) String() string {
+	d.checkRead()
+	return d.f.String()
+}
+
+func (d *Flags) PrettyString() string {
+	d.checkRead()
+	return d.f.PrettyString()
+}
+
+func (d *Flags) CountSet() int {
+	d.checkRead()
+	return d.f.CountSet()
+}
+
+func (d *Flags) Bits() iter.Seq2[flagged.BitIndex, bool] {
+	d.checkRead()
+	return d.f.Bits()
+}
+
+func (d *Flags) SetBits() iter.Seq[flagged.BitIndex] {
+	d.checkRead()
+	return d.f.SetBits()
+}
+
+func (d *Flags) Len() int {
+	d.checkRead()
+	return d.f.Len()
+}
+
+func (d *Flags) TrailingZeros() int {
+	d.checkRead()
+	return d.f.TrailingZeros()
+}
+
+func (d *Flags) LeadingZeros() int {
+	d.checkRead()
+	return d.f.LeadingZeros()
+}
+
+func (d *Flags) Uint64() uint64 {
+	d.checkRead()
+	return d.f.Uint64()
+}
+
+// CloneBitFlags returns a new [Flags] wrapping a clone of the wrapped value.
+func (d *Flags) CloneBitFlags() flagged.BitFlags {
+	d.checkRead()
+	return Wrap(d.f.CloneBitFlags())
+}
@@ -0,0 +1,71 @@
+package debug
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/asmsh/flagged"
+)
+
+func TestFlags(t *testing.T) {
+	f := Wrap(flagged.New[flagged.BitFlags16](0))
+	f.Set(1)
+	f.Toggle(3)
+	f.SetTo(5, true)
+	f.Reset(5)
+	if got, want := f.Uint64(), uint64(0b1010); got != want {
+		t.Errorf("Uint64() = %b, want = %b", got, want)
+	}
+	if got, want := f.String(), "0000000000001010"; got != want {
+		t.Errorf("String() = %v, want = %v", got, want)
+	}
+
+	c := f.CloneBitFlags()
+	c.ResetAll()
+	if !f.AnySet() || c.AnySet() {
+		t.Error("CloneBitFlags() is not independent of the original")
+	}
+	if _, ok := c.(*Flags); !ok {
+		t.Errorf("CloneBitFlags() = %T, want *Flags", c)
+	}
+}
+
+func TestFlags_concurrent(t *testing.T) {
+	tests := []struct {
+		name string
+		fn   func(f *Flags)
+		want string
+	}{
+		{
+			name: "write",
+			fn:   func(f *Flags) { f.Set(0) },
+			want: "concurrent BitFlags writes",
+		},
+		{
+			name: "read",
+			fn:   func(f *Flags) { f.Is(0) },
+			want: "concurrent BitFlags read and write",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := Wrap(flagged.New[flagged.BitFlags8](0))
+
+			// Simulate a write in progress in another goroutine.
+			f.Set(1)
+			f.beginWrite()
+			defer f.endWrite()
+
+			defer func() {
+				msg, _ := recover().(string)
+				if !strings.HasPrefix(msg, tt.want) {
+					t.Errorf("panic = %q, want prefix %q", msg, tt.want)
+				}
+				if !strings.Contains(msg, "last writer:") || !strings.Contains(msg, "TestFlags_concurrent") {
+					t.Errorf("panic = %q, want the stack of the last writer", msg)
+				}
+			}()
+			tt.fn(f)
+		})
+	}
+}
@@ -0,0 +1,38 @@
+package flagged
+
+import "math/bits"
+
+// diffString renders the bits that changed from old to new, in increasing
+// order of index, like "+bit3 -bit7", where the bit at index i is named
+// names[i] instead, if it's there and not empty.
+func diffString[T bitFlagsTypes](old, new T, names []string) string {
+	var str stringBuilder
+	for v := uint64(old ^ new); v != 0; v &= v - 1 {
+		i := bits.TrailingZeros64(v)
+		if len(str) != 0 {
+			str.WriteByte(' ')
+		}
+		if isUint(new, i) {
+			str.WriteByte('+')
+		} else {
+			str.WriteByte('-')
+		}
+		if i < len(names) && names[i] != "" {
+			str.WriteString(names[i])
+		} else {
+			str.WriteString("bit")
+			str = appendItoa(str, i)
+		}
+	}
+	return str.String()
+}
+
+// DiffString returns the changes from old to new, as the space separated
+// indexes of the bits that changed, in increasing order, each prefixed by
+// '+' if it got set, or '-' if it got reset, like "+bit3 -bit7", or by
+// their names, if they're registered for T by [RegisterNames], like
+// "+Write -Exec".
+// It returns "" if old and new are equal.
+func DiffString[T underlyingBitFlags](old, new T) string {
+	return diffString(uint64(old), uint64(new), registeredNames[T]())
+}
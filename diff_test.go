@@ -0,0 +1,33 @@
+package flagged
+
+import "testing"
+
+func TestDiffString(t *testing.T) {
+	tests := []struct {
+		name string
+		got  string
+		want string
+	}{
+		{"equal", DiffString(BitFlags8(0b101), 0b101), ""},
+		{"set", DiffString(BitFlags8(0), 0b1000), "+bit3"},
+		{"reset", DiffString(BitFlags16(1<<15), 0), "-bit15"},
+		{"both", DiffString(BitFlags32(1<<7|1), 1<<3|1), "+bit3 -bit7"},
+		{"edges", DiffString(BitFlags64(1<<63), 1), "+bit0 -bit63"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.got != tt.want {
+				t.Errorf("DiffString() = %q, want = %q", tt.got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_diffString_names(t *testing.T) {
+	names := []string{"Read", "Write", "", "Exec"}
+	got := diffString(BitFlags8(0b0001_1010), 0b0010_0101, names)
+	want := "+Read -Write +bit2 -Exec -bit4 +bit5"
+	if got != want {
+		t.Errorf("diffString() = %q, want = %q", got, want)
+	}
+}
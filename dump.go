@@ -0,0 +1,78 @@
+package flagged
+
+// dump renders each bit of f on its own line, from index 0 up, like
+// "bit  0: 1 Read", where the name of the bit at index i is names[i],
+// if it's there and not empty.
+func dump[T bitFlagsTypes](f T, size int, names []string) string {
+	str := make(stringBuilder, 0, size*len("bit 00: 0\n"))
+	for i := range size {
+		str.WriteString("bit ")
+		if i < 10 {
+			str.WriteByte(' ')
+		}
+		str = appendItoa(str, i)
+		if isUint(f, i) {
+			str.WriteString(": 1")
+		} else {
+			str.WriteString(": 0")
+		}
+		if i < len(names) && names[i] != "" {
+			str.WriteByte(' ')
+			str.WriteString(names[i])
+		}
+		str.WriteByte('\n')
+	}
+	return str.String()
+}
+
+// Dump returns a multi-line representation of f, with each of its 8 bits
+// on its own line, from index 0 up, like:
+//
+//	bit  0: 1
+//	bit  1: 0
+//	bit  2: 1
+//	...
+//
+// Each bit is followed by its name, if names are registered for
+// BitFlags8 by [RegisterNames]; use [Registered.Dump] for the types
+// defined on it.
+func (f BitFlags8) Dump() string { return dump(f, 8, registeredNames[BitFlags8]()) }
+
+// Dump returns a multi-line representation of f, with each of its 16 bits
+// on its own line, from index 0 up, like:
+//
+//	bit  0: 1
+//	bit  1: 0
+//	bit  2: 1
+//	...
+//
+// Each bit is followed by its name, if names are registered for
+// BitFlags16 by [RegisterNames]; use [Registered.Dump] for the types
+// defined on it.
+func (f BitFlags16) Dump() string { return dump(f, 16, registeredNames[BitFlags16]()) }
+
+// Dump returns a multi-line representation of f, with each of its 32 bits
+// on its own line, from index 0 up, like:
+//
+//	bit  0: 1
+//	bit  1: 0
+//	bit  2: 1
+//	...
+//
+// Each bit is followed by its name, if names are registered for
+// BitFlags32 by [RegisterNames]; use [Registered.Dump] for the types
+// defined on it.
+func (f BitFlags32) Dump() string { return dump(f, 32, registeredNames[BitFlags32]()) }
+
+// Dump returns a multi-line representation of f, with each of its 64 bits
+// on its own line, from index 0 up, like:
+//
+//	bit  0: 1
+//	bit  1: 0
+//	bit  2: 1
+//	...
+//
+// Each bit is followed by its name, if names are registered for
+// BitFlags64 by [RegisterNames]; use [Registered.Dump] for the types
+// defined on it.
+func (f BitFlags64) Dump() string { return dump(f, 64, registeredNames[BitFlags64]()) }
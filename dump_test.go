@@ -0,0 +1,47 @@
+package flagged
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestBitFlags_Dump(t *testing.T) {
+	want := "" +
+		"bit  0: 1\n" +
+		"bit  1: 0\n" +
+		"bit  2: 1\n" +
+		"bit  3: 0\n" +
+		"bit  4: 0\n" +
+		"bit  5: 0\n" +
+		"bit  6: 0\n" +
+		"bit  7: 1\n"
+	if got := BitFlags8(0b1000_0101).Dump(); got != want {
+		t.Errorf("Dump() = %q, want = %q", got, want)
+	}
+
+	lines := strings.Split(BitFlags64(1<<63).Dump(), "\n")
+	if len(lines) != 65 || lines[64] != "" {
+		t.Fatalf("Dump() has %v lines, want = 64 and a trailing newline", len(lines))
+	}
+	if got, want := lines[10], "bit 10: 0"; got != want {
+		t.Errorf("Dump() line 10 = %q, want = %q", got, want)
+	}
+	if got, want := lines[63], "bit 63: 1"; got != want {
+		t.Errorf("Dump() line 63 = %q, want = %q", got, want)
+	}
+}
+
+func Test_dump_names(t *testing.T) {
+	want := "" +
+		"bit  0: 1 Read\n" +
+		"bit  1: 0\n" +
+		"bit  2: 1 Exec\n" +
+		"bit  3: 0\n" +
+		"bit  4: 0\n" +
+		"bit  5: 0\n" +
+		"bit  6: 0\n" +
+		"bit  7: 0\n"
+	if got := dump(BitFlags8(0b101), 8, []string{"Read", "", "Exec"}); got != want {
+		t.Errorf("dump() = %q, want = %q", got, want)
+	}
+}
@@ -0,0 +1,380 @@
+package flagged
+
+import "unicode/utf8"
+
+// unmarshalText implements the UnmarshalText method of the BitFlags types.
+func unmarshalText[T BitFlags8 | BitFlags16 | BitFlags32 | BitFlags64](f *T, text []byte) error {
+	v, err := parseText[T]("UnmarshalText", string(text))
+	if err != nil {
+		return err
+	}
+	*f = v
+	return nil
+}
+
+// parseText returns the value of s, in the binary format returned by
+// MarshalText, with all the bits of T, or the decimal format in which the
+// BitFlags types were encoded as map keys before they implemented
+// [encoding.TextMarshaler], so the old payloads keep decoding.
+// The binary format can't be confused with a decimal number, since a
+// number with that many digits is out of the range of T.
+func parseText[T BitFlags8 | BitFlags16 | BitFlags32 | BitFlags64](fn, s string) (T, error) {
+	if len(s) == sizeOf[T]() {
+		return parseBinary[T](fn, s)
+	}
+	return parseDecimal[T](fn, s)
+}
+
+// parseDecimal returns the value of s, as a decimal number.
+func parseDecimal[T BitFlags8 | BitFlags16 | BitFlags32 | BitFlags64](fn, s string) (T, error) {
+	if len(s) == 0 {
+		return 0, &ParseError{fn, s, ErrSyntax}
+	}
+	maxV := uint64(^T(0))
+	var v uint64
+	for i := range len(s) {
+		c := s[i]
+		if c < '0' || c > '9' {
+			return 0, &ParseError{fn, s, ErrSyntax}
+		}
+		d := uint64(c - '0')
+		if v > (maxV-d)/10 {
+			return 0, &ParseError{fn, s, ErrLength}
+		}
+		v = v*10 + d
+	}
+	return T(v), nil
+}
+
+// unmarshalJSON implements the UnmarshalJSON method of the BitFlags types.
+// Besides the JSON strings encoded by MarshalText, it accepts the JSON
+// numbers encoded before the BitFlags types implemented
+// [encoding.TextMarshaler], so the old payloads keep decoding.
+func unmarshalJSON[T BitFlags8 | BitFlags16 | BitFlags32 | BitFlags64](f *T, data []byte) error {
+	if string(data) == "null" {
+		return nil
+	}
+	var v T
+	var err error
+	if len(data) > 0 && data[0] == '"' {
+		s, rest, ok := cutJSONString(data)
+		if !ok || len(rest) != 0 {
+			return &ParseError{"UnmarshalJSON", string(data), ErrSyntax}
+		}
+		v, err = parseText[T]("UnmarshalJSON", s)
+	} else {
+		v, err = parseDecimal[T]("UnmarshalJSON", string(data))
+	}
+	if err != nil {
+		return err
+	}
+	*f = v
+	return nil
+}
+
+// MarshalText implements [encoding.TextMarshaler], encoding f in the binary
+// format returned by [BitFlags8.String], like "00000101".
+func (f BitFlags8) MarshalText() ([]byte, error) { return appendBinaryString(nil, f, 8), nil }
+
+// MarshalText implements [encoding.TextMarshaler], encoding f in the binary
+// format returned by [BitFlags16.String], like "0000000000000101".
+func (f BitFlags16) MarshalText() ([]byte, error) { return appendBinaryString(nil, f, 16), nil }
+
+// MarshalText implements [encoding.TextMarshaler], encoding f in the binary
+// format returned by [BitFlags32.String], like "0...0101".
+func (f BitFlags32) MarshalText() ([]byte, error) { return appendBinaryString(nil, f, 32), nil }
+
+// MarshalText implements [encoding.TextMarshaler], encoding f in the binary
+// format returned by [BitFlags64.String], like "0...0101".
+func (f BitFlags64) MarshalText() ([]byte, error) { return appendBinaryString(nil, f, 64), nil }
+
+// UnmarshalText implements [encoding.TextUnmarshaler], decoding text in the
+// format returned by [BitFlags8.MarshalText] into f, or a decimal number,
+// in which f was encoded as a map key before it implemented
+// [encoding.TextMarshaler].
+// If text is neither, the returned error is a *[ParseError], and f isn't
+// changed.
+func (f *BitFlags8) UnmarshalText(text []byte) error { return unmarshalText(f, text) }
+
+// UnmarshalText implements [encoding.TextUnmarshaler], decoding text in the
+// format returned by [BitFlags16.MarshalText] into f, or a decimal number,
+// in which f was encoded as a map key before it implemented
+// [encoding.TextMarshaler].
+// If text is neither, the returned error is a *[ParseError], and f isn't
+// changed.
+func (f *BitFlags16) UnmarshalText(text []byte) error { return unmarshalText(f, text) }
+
+// UnmarshalText implements [encoding.TextUnmarshaler], decoding text in the
+// format returned by [BitFlags32.MarshalText] into f, or a decimal number,
+// in which f was encoded as a map key before it implemented
+// [encoding.TextMarshaler].
+// If text is neither, the returned error is a *[ParseError], and f isn't
+// changed.
+func (f *BitFlags32) UnmarshalText(text []byte) error { return unmarshalText(f, text) }
+
+// UnmarshalText implements [encoding.TextUnmarshaler], decoding text in the
+// format returned by [BitFlags64.MarshalText] into f, or a decimal number,
+// in which f was encoded as a map key before it implemented
+// [encoding.TextMarshaler].
+// If text is neither, the returned error is a *[ParseError], and f isn't
+// changed.
+func (f *BitFlags64) UnmarshalText(text []byte) error { return unmarshalText(f, text) }
+
+// UnmarshalJSON implements [encoding/json.Unmarshaler], decoding a JSON
+// string in the format accepted by [BitFlags8.UnmarshalText] into f, or a
+// JSON number, in which f was encoded before it implemented
+// [encoding.TextMarshaler].
+// If data is neither, the returned error is a *[ParseError], and f isn't
+// changed.
+func (f *BitFlags8) UnmarshalJSON(data []byte) error { return unmarshalJSON(f, data) }
+
+// UnmarshalJSON implements [encoding/json.Unmarshaler], decoding a JSON
+// string in the format accepted by [BitFlags16.UnmarshalText] into f, or a
+// JSON number, in which f was encoded before it implemented
+// [encoding.TextMarshaler].
+// If data is neither, the returned error is a *[ParseError], and f isn't
+// changed.
+func (f *BitFlags16) UnmarshalJSON(data []byte) error { return unmarshalJSON(f, data) }
+
+// UnmarshalJSON implements [encoding/json.Unmarshaler], decoding a JSON
+// string in the format accepted by [BitFlags32.UnmarshalText] into f, or a
+// JSON number, in which f was encoded before it implemented
+// [encoding.TextMarshaler].
+// If data is neither, the returned error is a *[ParseError], and f isn't
+// changed.
+func (f *BitFlags32) UnmarshalJSON(data []byte) error { return unmarshalJSON(f, data) }
+
+// UnmarshalJSON implements [encoding/json.Unmarshaler], decoding a JSON
+// string in the format accepted by [BitFlags64.UnmarshalText] into f, or a
+// JSON number, in which f was encoded before it implemented
+// [encoding.TextMarshaler].
+// If data is neither, the returned error is a *[ParseError], and f isn't
+// changed.
+func (f *BitFlags64) UnmarshalJSON(data []byte) error { return unmarshalJSON(f, data) }
+
+// appendBinary appends the size/8 bytes of f to dst, in big-endian order.
+func appendBinary[T bitFlagsTypes](dst []byte, f T, size int) []byte {
+	for i := size - 8; i >= 0; i -= 8 {
+		dst = append(dst, byte(f>>i))
+	}
+	return dst
+}
+
+// unmarshalBinary implements the UnmarshalBinary method of the BitFlags
+// types.
+func unmarshalBinary[T bitFlags](f *T, size int, data []byte) error {
+	if len(data) != size/8 {
+		return &ParseError{"UnmarshalBinary", string(data), ErrLength}
+	}
+	var v uint64
+	for _, b := range data {
+		v = v<<8 | uint64(b)
+	}
+	*f = T(v)
+	return nil
+}
+
+// MarshalBinary implements [encoding.BinaryMarshaler], encoding f in a
+// single byte.
+func (f BitFlags8) MarshalBinary() ([]byte, error) { return appendBinary(nil, f, 8), nil }
+
+// MarshalBinary implements [encoding.BinaryMarshaler], encoding f in 2
+// bytes, in big-endian order.
+func (f BitFlags16) MarshalBinary() ([]byte, error) { return appendBinary(nil, f, 16), nil }
+
+// MarshalBinary implements [encoding.BinaryMarshaler], encoding f in 4
+// bytes, in big-endian order.
+func (f BitFlags32) MarshalBinary() ([]byte, error) { return appendBinary(nil, f, 32), nil }
+
+// MarshalBinary implements [encoding.BinaryMarshaler], encoding f in 8
+// bytes, in big-endian order.
+func (f BitFlags64) MarshalBinary() ([]byte, error) { return appendBinary(nil, f, 64), nil }
+
+// UnmarshalBinary implements [encoding.BinaryUnmarshaler], decoding the
+// data encoded by [BitFlags8.MarshalBinary] into f.
+// If data isn't a single byte, the returned error is a *[ParseError]
+// wrapping [ErrLength], and f isn't changed.
+func (f *BitFlags8) UnmarshalBinary(data []byte) error { return unmarshalBinary(f, 8, data) }
+
+// UnmarshalBinary implements [encoding.BinaryUnmarshaler], decoding the
+// data encoded by [BitFlags16.MarshalBinary] into f.
+// If data isn't 2 bytes, the returned error is a *[ParseError] wrapping
+// [ErrLength], and f isn't changed.
+func (f *BitFlags16) UnmarshalBinary(data []byte) error { return unmarshalBinary(f, 16, data) }
+
+// UnmarshalBinary implements [encoding.BinaryUnmarshaler], decoding the
+// data encoded by [BitFlags32.MarshalBinary] into f.
+// If data isn't 4 bytes, the returned error is a *[ParseError] wrapping
+// [ErrLength], and f isn't changed.
+func (f *BitFlags32) UnmarshalBinary(data []byte) error { return unmarshalBinary(f, 32, data) }
+
+// UnmarshalBinary implements [encoding.BinaryUnmarshaler], decoding the
+// data encoded by [BitFlags64.MarshalBinary] into f.
+// If data isn't 8 bytes, the returned error is a *[ParseError] wrapping
+// [ErrLength], and f isn't changed.
+func (f *BitFlags64) UnmarshalBinary(data []byte) error { return unmarshalBinary(f, 64, data) }
+
+// AppendText implements [encoding.TextAppender], appending f to b in the
+// format returned by [BitFlags8.MarshalText].
+func (f BitFlags8) AppendText(b []byte) ([]byte, error) { return appendBinaryString(b, f, 8), nil }
+
+// AppendText implements [encoding.TextAppender], appending f to b in the
+// format returned by [BitFlags16.MarshalText].
+func (f BitFlags16) AppendText(b []byte) ([]byte, error) { return appendBinaryString(b, f, 16), nil }
+
+// AppendText implements [encoding.TextAppender], appending f to b in the
+// format returned by [BitFlags32.MarshalText].
+func (f BitFlags32) AppendText(b []byte) ([]byte, error) { return appendBinaryString(b, f, 32), nil }
+
+// AppendText implements [encoding.TextAppender], appending f to b in the
+// format returned by [BitFlags64.MarshalText].
+func (f BitFlags64) AppendText(b []byte) ([]byte, error) { return appendBinaryString(b, f, 64), nil }
+
+// AppendBinary implements [encoding.BinaryAppender], appending f to b in
+// the format returned by [BitFlags8.MarshalBinary].
+func (f BitFlags8) AppendBinary(b []byte) ([]byte, error) { return appendBinary(b, f, 8), nil }
+
+// AppendBinary implements [encoding.BinaryAppender], appending f to b in
+// the format returned by [BitFlags16.MarshalBinary].
+func (f BitFlags16) AppendBinary(b []byte) ([]byte, error) { return appendBinary(b, f, 16), nil }
+
+// AppendBinary implements [encoding.BinaryAppender], appending f to b in
+// the format returned by [BitFlags32.MarshalBinary].
+func (f BitFlags32) AppendBinary(b []byte) ([]byte, error) { return appendBinary(b, f, 32), nil }
+
+// AppendBinary implements [encoding.BinaryAppender], appending f to b in
+// the format returned by [BitFlags64.MarshalBinary].
+func (f BitFlags64) AppendBinary(b []byte) ([]byte, error) { return appendBinary(b, f, 64), nil }
+
+// appendJSONString appends s to dst as a JSON string, escaped like
+// encoding/json does, but without depending on it.
+// The invalid UTF-8 bytes are replaced with U+FFFD, and '<', '>' and '&'
+// are left for encoding/json to escape, when it's used.
+func appendJSONString(dst []byte, s string) []byte {
+	dst = append(dst, '"')
+	for i := 0; i < len(s); {
+		c := s[i]
+		if c >= utf8.RuneSelf {
+			r, n := utf8.DecodeRuneInString(s[i:])
+			switch {
+			case r == utf8.RuneError && n == 1:
+				dst = append(dst, "\ufffd"...)
+			case r == '\u2028' || r == '\u2029':
+				dst = append(dst, `\u202`...)
+				dst = append(dst, digits[r&0xf])
+			default:
+				dst = append(dst, s[i:i+n]...)
+			}
+			i += n
+			continue
+		}
+		switch c {
+		case '"', '\\':
+			dst = append(dst, '\\', c)
+		case '\b':
+			dst = append(dst, `\b`...)
+		case '\f':
+			dst = append(dst, `\f`...)
+		case '\n':
+			dst = append(dst, `\n`...)
+		case '\r':
+			dst = append(dst, `\r`...)
+		case '\t':
+			dst = append(dst, `\t`...)
+		default:
+			if c < ' ' {
+				dst = append(dst, `\u00`...)
+				dst = append(dst, digits[c>>4], digits[c&0xf])
+			} else {
+				dst = append(dst, c)
+			}
+		}
+		i++
+	}
+	return append(dst, '"')
+}
+
+// cutJSONString returns the unescaped value of the JSON string at the
+// start of data, and the rest of data after it, or ok false if data
+// doesn't start with a valid JSON string.
+// Like encoding/json, it replaces the invalid surrogate escapes with
+// U+FFFD.
+func cutJSONString(data []byte) (s string, rest []byte, ok bool) {
+	if len(data) == 0 || data[0] != '"' {
+		return "", data, false
+	}
+	var buf []byte
+	for i := 1; i < len(data); {
+		c := data[i]
+		switch {
+		case c == '"':
+			return string(buf), data[i+1:], true
+		case c < ' ':
+			return "", data, false
+		case c != '\\':
+			buf = append(buf, c)
+			i++
+			continue
+		case i+1 == len(data):
+			return "", data, false
+		}
+		switch e := data[i+1]; e {
+		case '"', '\\', '/':
+			buf = append(buf, e)
+		case 'b':
+			buf = append(buf, '\b')
+		case 'f':
+			buf = append(buf, '\f')
+		case 'n':
+			buf = append(buf, '\n')
+		case 'r':
+			buf = append(buf, '\r')
+		case 't':
+			buf = append(buf, '\t')
+		case 'u':
+			r, ok := cutJSONRune(data[i:])
+			if !ok {
+				return "", data, false
+			}
+			i += 6
+			if 0xD800 <= r && r < 0xE000 {
+				r2, ok := cutJSONRune(data[i:])
+				if r < 0xDC00 && ok && 0xDC00 <= r2 && r2 < 0xE000 {
+					r = (r-0xD800)<<10 | (r2 - 0xDC00) + 0x10000
+					i += 6
+				} else {
+					r = utf8.RuneError
+				}
+			}
+			buf = utf8.AppendRune(buf, r)
+			continue
+		default:
+			return "", data, false
+		}
+		i += 2
+	}
+	return "", data, false
+}
+
+// cutJSONRune returns the rune escaped as \uXXXX at the start of data.
+func cutJSONRune(data []byte) (r rune, ok bool) {
+	if len(data) < 6 || data[0] != '\\' || data[1] != 'u' {
+		return 0, false
+	}
+	for _, c := range data[2:6] {
+		d, ok := unhex(c)
+		if !ok {
+			return 0, false
+		}
+		r = r<<4 | rune(d)
+	}
+	return r, true
+}
+
+// trimJSONSpace returns data without its leading JSON whitespace.
+func trimJSONSpace(data []byte) []byte {
+	for len(data) > 0 && (data[0] == ' ' || data[0] == '\t' || data[0] == '\n' || data[0] == '\r') {
+		data = data[1:]
+	}
+	return data
+}
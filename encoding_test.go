@@ -0,0 +1,383 @@
+package flagged
+
+import (
+	"encoding"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func helperRunTestText[T BitFlags8 | BitFlags16 | BitFlags32 | BitFlags64, TP interface {
+	ptrBitFlags[T]
+	encoding.TextMarshaler
+	encoding.TextUnmarshaler
+}](t *testing.T) {
+	var (
+		zero   T
+		allset = ^zero
+		size   = TP(&zero).Size()
+	)
+	tests := []struct {
+		name    string
+		initial T
+	}{
+		{
+			name:    "zero",
+			initial: zero,
+		},
+		{
+			name:    "allset",
+			initial: allset,
+		},
+		{
+			name:    "partial",
+			initial: zero | 0b0101 | T(1)<<(size-1),
+		},
+	}
+	t.Run(fmt.Sprintf("%T", zero), func(t *testing.T) {
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				var f TP = &tt.initial
+				text, err := f.MarshalText()
+				if err != nil {
+					t.Fatalf("MarshalText() error = %v", err)
+				}
+				if got, want := string(text), f.String(); got != want {
+					t.Errorf("MarshalText() = %v, want = %v", got, want)
+				}
+
+				var got T
+				if err := TP(&got).UnmarshalText(text); err != nil || got != tt.initial {
+					t.Errorf("UnmarshalText(%s) = %v, %v, want = %v, nil", text, got, err, tt.initial)
+				}
+			})
+		}
+
+		// The decimal form, encoded before the BitFlags types implemented
+		// encoding.TextMarshaler, still decodes.
+		var got T
+		if err := TP(&got).UnmarshalText([]byte("12")); err != nil || got != 12 {
+			t.Errorf("UnmarshalText(\"12\") = %v, %v, want = %v, nil", got, err, T(12))
+		}
+
+		for _, text := range []string{"01a", "", "2" + TP(&zero).String()[1:]} {
+			got := allset
+			err := TP(&got).UnmarshalText([]byte(text))
+			var perr *ParseError
+			if !errors.As(err, &perr) || !errors.Is(err, ErrSyntax) || perr.Func != "UnmarshalText" {
+				t.Errorf("UnmarshalText(%q) error = %#v, want = %v", text, err, ErrSyntax)
+			}
+			if got != allset {
+				t.Errorf("UnmarshalText(%q) changed f to %v", text, got)
+			}
+		}
+	})
+}
+
+func TestBitFlags_Text(t *testing.T) {
+	helperRunTestText[BitFlags8](t)
+	helperRunTestText[BitFlags16](t)
+	helperRunTestText[BitFlags32](t)
+	helperRunTestText[BitFlags64](t)
+}
+
+func TestBitFlags_Text_json(t *testing.T) {
+	m := map[BitFlags8]BitFlags16{0b101: 0b11}
+	b, err := json.Marshal(m)
+	if err != nil {
+		t.Fatalf("Marshal() error = %v", err)
+	}
+	if got, want := string(b), `{"00000101":"0000000000000011"}`; got != want {
+		t.Errorf("Marshal() = %s, want = %s", got, want)
+	}
+
+	var got map[BitFlags8]BitFlags16
+	if err := json.Unmarshal(b, &got); err != nil || len(got) != 1 || got[0b101] != 0b11 {
+		t.Errorf("Unmarshal(%s) = %v, %v, want = %v, nil", b, got, err, m)
+	}
+
+	// The keys and values encoded in decimal, before the BitFlags types
+	// implemented encoding.TextMarshaler, still decode, and re-encode in
+	// binary.
+	old := `{"10":3,"0":65535,"255":0}`
+	got = nil
+	if err := json.Unmarshal([]byte(old), &got); err != nil {
+		t.Fatalf("Unmarshal(%s) error = %v", old, err)
+	}
+	want := map[BitFlags8]BitFlags16{10: 3, 0: 65535, 255: 0}
+	if len(got) != len(want) || got[10] != 3 || got[0] != 65535 || got[255] != 0 {
+		t.Errorf("Unmarshal(%s) = %v, want = %v", old, got, want)
+	}
+	b, err = json.Marshal(got)
+	if err != nil {
+		t.Fatalf("Marshal() error = %v", err)
+	}
+	if got, want := string(b), `{"00000000":"1111111111111111","00001010":"0000000000000011","11111111":"0000000000000000"}`; got != want {
+		t.Errorf("Marshal() = %s, want = %s", got, want)
+	}
+	if err := json.Unmarshal([]byte(`{"256":0}`), &got); !errors.Is(err, ErrLength) {
+		t.Errorf("Unmarshal({\"256\":0}) error = %v, want = %v", err, ErrLength)
+	}
+}
+
+func TestBitFlags_UnmarshalJSON(t *testing.T) {
+	type payload struct {
+		Perms BitFlags8
+		Opts  BitFlags64
+	}
+
+	// The numeric form, encoded before the BitFlags types implemented
+	// encoding.TextMarshaler, still decodes, and re-encodes as strings.
+	var old payload
+	if err := json.Unmarshal([]byte(`{"Perms":5,"Opts":18446744073709551615}`), &old); err != nil {
+		t.Fatalf("Unmarshal(numbers) error = %v", err)
+	}
+	if old.Perms != 0b101 || old.Opts != ^BitFlags64(0) {
+		t.Errorf("Unmarshal(numbers) = %+v, want = {Perms:00000101 Opts:1...1}", old)
+	}
+	b, err := json.Marshal(old)
+	if err != nil {
+		t.Fatalf("Marshal() error = %v", err)
+	}
+	if got, want := string(b), `{"Perms":"00000101","Opts":"`+old.Opts.String()+`"}`; got != want {
+		t.Errorf("Marshal() = %s, want = %s", got, want)
+	}
+	var got payload
+	if err := json.Unmarshal(b, &got); err != nil || got != old {
+		t.Errorf("Unmarshal(%s) = %+v, %v, want = %+v, nil", b, got, err, old)
+	}
+
+	tests := []struct {
+		data    string
+		wantErr error
+	}{
+		{`256`, ErrLength},
+		{`-1`, ErrSyntax},
+		{`1.5`, ErrSyntax},
+		{`"2a"`, ErrSyntax},
+		{`"256"`, ErrLength},
+		{`"0000000a"`, ErrSyntax},
+		{`"00000101`, ErrSyntax},
+		{`"00000101"x`, ErrSyntax},
+		{`"\x0000101"`, ErrSyntax},
+	}
+	for _, tt := range tests {
+		f := BitFlags8(0b11)
+		err := f.UnmarshalJSON([]byte(tt.data))
+		var perr *ParseError
+		if !errors.As(err, &perr) || !errors.Is(err, tt.wantErr) || perr.Func != "UnmarshalJSON" {
+			t.Errorf("UnmarshalJSON(%s) error = %#v, want = %v", tt.data, err, tt.wantErr)
+		}
+		if f != 0b11 {
+			t.Errorf("UnmarshalJSON(%s) changed f to %v", tt.data, f)
+		}
+	}
+
+	f := BitFlags8(0b11)
+	if err := f.UnmarshalJSON([]byte(`"\u00300000101"`)); err != nil || f != 0b101 {
+		t.Errorf("UnmarshalJSON(escaped) = %v, %v, want = 00000101, nil", f, err)
+	}
+	if err := f.UnmarshalJSON([]byte(`null`)); err != nil || f != 0b101 {
+		t.Errorf("UnmarshalJSON(null) = %v, %v, want = 00000101, nil", f, err)
+	}
+}
+
+func helperRunTestBinary[T bitFlags, TP interface {
+	ptrBitFlags[T]
+	encoding.BinaryMarshaler
+	encoding.BinaryUnmarshaler
+}](t *testing.T) {
+	var (
+		zero   T
+		allset = ^zero
+		size   = TP(&zero).Size()
+	)
+	tests := []struct {
+		name    string
+		initial T
+	}{
+		{
+			name:    "zero",
+			initial: zero,
+		},
+		{
+			name:    "allset",
+			initial: allset,
+		},
+		{
+			name:    "partial",
+			initial: zero | 0b0101 | T(1)<<(size-1),
+		},
+	}
+	t.Run(fmt.Sprintf("%T", zero), func(t *testing.T) {
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				var f TP = &tt.initial
+				data, err := f.MarshalBinary()
+				if err != nil {
+					t.Fatalf("MarshalBinary() error = %v", err)
+				}
+				if len(data) != size/8 {
+					t.Fatalf("MarshalBinary() has %v bytes, want = %v", len(data), size/8)
+				}
+				// Big-endian, so the last byte has the bit at index 0.
+				if got, want := data[len(data)-1]&1 != 0, f.Is(0); got != want {
+					t.Errorf("MarshalBinary() bit 0 = %v, want = %v", got, want)
+				}
+				if got, want := data[0]&0x80 != 0, f.Is(size-1); got != want {
+					t.Errorf("MarshalBinary() bit %v = %v, want = %v", size-1, got, want)
+				}
+
+				var got T
+				if err := TP(&got).UnmarshalBinary(data); err != nil || got != tt.initial {
+					t.Errorf("UnmarshalBinary(%x) = %v, %v, want = %v, nil", data, got, err, tt.initial)
+				}
+			})
+		}
+
+		for _, n := range []int{0, size/8 - 1, size/8 + 1} {
+			got := allset
+			err := TP(&got).UnmarshalBinary(make([]byte, n))
+			if !errors.Is(err, ErrLength) {
+				t.Errorf("UnmarshalBinary(%v bytes) error = %v, want = %v", n, err, ErrLength)
+			}
+			var perr *ParseError
+			if !errors.As(err, &perr) || perr.Func != "UnmarshalBinary" || perr.Input != string(make([]byte, n)) {
+				t.Errorf("UnmarshalBinary(%v bytes) error = %#v, want = *ParseError", n, err)
+			}
+			if got != allset {
+				t.Errorf("UnmarshalBinary(%v bytes) changed f to %v", n, got)
+			}
+		}
+	})
+}
+
+func TestBitFlags_Binary(t *testing.T) {
+	helperRunTestBinary[BitFlags8](t)
+	helperRunTestBinary[BitFlags16](t)
+	helperRunTestBinary[BitFlags32](t)
+	helperRunTestBinary[BitFlags64](t)
+}
+
+func TestBitFlags_MarshalBinary_bigEndian(t *testing.T) {
+	data, _ := BitFlags32(0x01020304).MarshalBinary()
+	if got, want := fmt.Sprintf("%x", data), "01020304"; got != want {
+		t.Errorf("MarshalBinary() = %v, want = %v", got, want)
+	}
+}
+
+func helperRunTestAppenders[T bitFlags, TP interface {
+	ptrBitFlags[T]
+	encoding.TextMarshaler
+	encoding.BinaryMarshaler
+	AppendText(b []byte) ([]byte, error)
+	AppendBinary(b []byte) ([]byte, error)
+}](t *testing.T) {
+	var (
+		zero   T
+		allset = ^zero
+	)
+	tests := []struct {
+		name    string
+		initial T
+	}{
+		{
+			name:    "zero",
+			initial: zero,
+		},
+		{
+			name:    "allset",
+			initial: allset,
+		},
+		{
+			name:    "partial",
+			initial: zero | 0b0101,
+		},
+	}
+	t.Run(fmt.Sprintf("%T", zero), func(t *testing.T) {
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				var f TP = &tt.initial
+				prefix := []byte("prefix")
+
+				text, _ := f.MarshalText()
+				got, err := f.AppendText(prefix)
+				if err != nil || string(got) != "prefix"+string(text) {
+					t.Errorf("AppendText() = %q, %v, want = %q, nil", got, err, "prefix"+string(text))
+				}
+
+				data, _ := f.MarshalBinary()
+				got, err = f.AppendBinary(prefix)
+				if err != nil || string(got) != "prefix"+string(data) {
+					t.Errorf("AppendBinary() = %q, %v, want = %q, nil", got, err, "prefix"+string(data))
+				}
+
+				buf := make([]byte, 0, 64)
+				allocs := testing.AllocsPerRun(10, func() {
+					buf, _ = f.AppendText(buf[:0])
+					buf, _ = f.AppendBinary(buf[:0])
+				})
+				if allocs != 0 {
+					t.Errorf("Append allocs = %v, want = 0", allocs)
+				}
+			})
+		}
+	})
+}
+
+func TestBitFlags_Appenders(t *testing.T) {
+	helperRunTestAppenders[BitFlags8](t)
+	helperRunTestAppenders[BitFlags16](t)
+	helperRunTestAppenders[BitFlags32](t)
+	helperRunTestAppenders[BitFlags64](t)
+}
+
+func Test_appendJSONString(t *testing.T) {
+	for _, s := range []string{
+		"",
+		"Read",
+		`say "hi"`,
+		`C:\dir`,
+		"a\b\f\n\r\tb",
+		"\x00\x1f\x7f",
+		"héllo, 世界",
+		"\u2028\u2029",
+		"\xff\xe4\xb8",
+	} {
+		want, _ := json.Marshal(s)
+		if got := appendJSONString(nil, s); string(got) != string(want) {
+			t.Errorf("appendJSONString(%q) = %s, want = %s", s, got, want)
+		}
+	}
+}
+
+func Test_cutJSONString(t *testing.T) {
+	for _, data := range []string{
+		`""`,
+		`"Read"`,
+		`"say \"hi\""`,
+		`"C:\\dir\/"`,
+		`"\b\f\n\r\t"`,
+		`"\u0052ead \u00e9 \u4e16"`,
+		`"\ud83d\ude00"`,
+		`"\ud83d"`,
+		`"\ude00\ud83d"`,
+		`"\ud83d\u0041"`,
+		`"héllo"`,
+	} {
+		var want string
+		if err := json.Unmarshal([]byte(data), &want); err != nil {
+			t.Fatalf("json.Unmarshal(%s) error = %v", data, err)
+		}
+		s, rest, ok := cutJSONString([]byte(data + `:true`))
+		if !ok || s != want || string(rest) != ":true" {
+			t.Errorf("cutJSONString(%s) = %q, %q, %v, want = %q, %q, true", data, s, rest, ok, want, ":true")
+		}
+	}
+	for _, data := range []string{``, `Read`, `"Read`, `"a\"`, `"\x"`, `"\u00"`, `"\u00zz"`, "\"a\nb\""} {
+		if s, _, ok := cutJSONString([]byte(data)); ok {
+			t.Errorf("cutJSONString(%s) = %q, true, want = false", data, s)
+		}
+	}
+}
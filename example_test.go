@@ -39,3 +39,24 @@ func ExampleBitFlags() {
 	// Output:
 	// 00000000000000000000000000100000
 }
+
+func ExampleBuilder() {
+	f := Build8().Set(0).Set(3).Reset(1).Value()
+	fmt.Println(f)
+	// Output:
+	// 00001001
+}
+
+func ExampleNamedBitFlags() {
+	f := NewNamed[BitFlags8]("Read", "Write", "Exec")
+	f.SetByName("Read")
+	f.SetByName("Exec")
+	fmt.Println(f)
+	fmt.Println(f.IsByName("Write"))
+	fmt.Println(f.Flags)
+
+	// Output:
+	// Read|Exec
+	// false
+	// 00000101
+}
@@ -2,6 +2,12 @@
 // inspecting compact bitflags, while remaining dependency- and allocation-free.
 package flagged
 
+import (
+	"iter"
+	"math/bits"
+	"unicode/utf8"
+)
+
 // BitIndex is a marker type denoting that its values should be used
 // as bit indexes, passed to the different [BitFlags] methods.
 // If the value is outside [BitFlags] range, the methods will panic.
@@ -71,10 +77,15 @@ type BitFlags interface {
 
 	// AnyOf reports whether any of the bits at indexes idx are set to true.
 	// If no indexes are passed, it acts as [BitFlags.AnySet].
+	// All the indexes are validated before any bit is checked, so it panics
+	// if any of them is out of the allowed range [0, Size-1], regardless of
+	// their order or the values of the bits.
 	AnyOf(idx ...BitIndex) bool
 
 	// AllOf reports whether all the bits at indexes idx are set to true.
 	// If no indexes are passed, it acts as [BitFlags.AllSet].
+	// Like [BitFlags.AnyOf], it validates all the indexes first, so it panics
+	// if any of them is out of the allowed range [0, Size-1].
 	AllOf(idx ...BitIndex) bool
 
 	// Size is the number of bits included in this [BitFlags] value.
@@ -100,6 +111,45 @@ type BitFlags interface {
 	//  String() // "0000010001000100"
 	//  PrettyString() // "O|O|O|O|O|I|O|O_O|I|O|O|O|I|O|O"
 	PrettyString() string
+
+	// CountSet returns the number of bits set to true.
+	CountSet() int
+
+	// Bits returns an iterator over all the bits, from index 0 to Size-1,
+	// yielding each index along with whether it's set to true or not.
+	// The bits are those of the value at the time Bits is called.
+	Bits() iter.Seq2[BitIndex, bool]
+
+	// SetBits returns an iterator over the indices of the bits set to true,
+	// in increasing order.
+	// The bits are those of the value at the time SetBits is called.
+	SetBits() iter.Seq[BitIndex]
+
+	// Len returns the minimum number of bits required to represent this
+	// value, which is the index of the highest bit set to true plus 1,
+	// or 0 if no bits are set.
+	Len() int
+
+	// TrailingZeros returns the number of trailing zero bits, starting
+	// from index 0, which is Size if no bits are set.
+	TrailingZeros() int
+
+	// LeadingZeros returns the number of leading zero bits, starting
+	// from index Size-1, which is Size if no bits are set.
+	LeadingZeros() int
+
+	// CloneBitFlags returns an independent copy of this [BitFlags] value,
+	// as a pointer to a new value of the same type, so changing one of them
+	// doesn't affect the other.
+	CloneBitFlags() BitFlags
+
+	// Uint64 returns the raw value of the bits, as an uint64.
+	Uint64() uint64
+
+	// SetUint64 sets the raw value of the bits to v.
+	// It panics with a [ValueOverflowError] if v doesn't fit in Size bits,
+	// without changing the bits.
+	SetUint64(v uint64)
 }
 
 var (
@@ -115,6 +165,25 @@ func New[T BitFlags8 | BitFlags16 | BitFlags32 | BitFlags64](f T) *T {
 	return &f
 }
 
+// SmallestFor returns a pointer to a zero value of the smallest BitFlags
+// type that can carry n flags, for when the number of flags is only known
+// at runtime.
+// It panics with a [CountError] if n is out of the allowed range [0, 64].
+func SmallestFor(n int) BitFlags {
+	switch {
+	case n < 0 || n > 64:
+		panic(CountError{Count: n})
+	case n <= 8:
+		return new(BitFlags8)
+	case n <= 16:
+		return new(BitFlags16)
+	case n <= 32:
+		return new(BitFlags32)
+	default:
+		return new(BitFlags64)
+	}
+}
+
 func (f BitFlags8) Is(idx BitIndex) (set bool)               { return is(f, 8, idx) }
 func (f *BitFlags8) Set(idx BitIndex) (old bool)             { return set(f, 8, idx, true) }
 func (f *BitFlags8) Reset(idx BitIndex) (old bool)           { return set(f, 8, idx, false) }
@@ -129,6 +198,15 @@ func (f BitFlags8) AllOf(idx ...BitIndex) bool               { return allSet(f,
 func (BitFlags8) Size() int                                  { return 8 }
 func (f BitFlags8) String() string                           { return getBinaryString(f, 8) }
 func (f BitFlags8) PrettyString() string                     { return getPrettyString(f, 8) }
+func (f BitFlags8) CountSet() int                            { return bits.OnesCount8(uint8(f)) }
+func (f BitFlags8) Bits() iter.Seq2[BitIndex, bool]          { return bitsSeq(f, 8) }
+func (f BitFlags8) SetBits() iter.Seq[BitIndex]              { return setBitsSeq(f) }
+func (f BitFlags8) Len() int                                 { return bits.Len8(uint8(f)) }
+func (f BitFlags8) TrailingZeros() int                       { return bits.TrailingZeros8(uint8(f)) }
+func (f BitFlags8) LeadingZeros() int                        { return bits.LeadingZeros8(uint8(f)) }
+func (f BitFlags8) CloneBitFlags() BitFlags                  { return &f }
+func (f BitFlags8) Uint64() uint64                           { return uint64(f) }
+func (f *BitFlags8) SetUint64(v uint64)                      { setUint64(f, 8, v) }
 func (f *BitFlags8) BitFlags() BitFlags                      { return f }
 
 func (f BitFlags16) Is(idx BitIndex) (set bool)               { return is(f, 16, idx) }
@@ -145,6 +223,15 @@ func (f BitFlags16) AllOf(idx ...BitIndex) bool               { return allSet(f,
 func (BitFlags16) Size() int                                  { return 16 }
 func (f BitFlags16) String() string                           { return getBinaryString(f, 16) }
 func (f BitFlags16) PrettyString() string                     { return getPrettyString(f, 16) }
+func (f BitFlags16) CountSet() int                            { return bits.OnesCount16(uint16(f)) }
+func (f BitFlags16) Bits() iter.Seq2[BitIndex, bool]          { return bitsSeq(f, 16) }
+func (f BitFlags16) SetBits() iter.Seq[BitIndex]              { return setBitsSeq(f) }
+func (f BitFlags16) Len() int                                 { return bits.Len16(uint16(f)) }
+func (f BitFlags16) TrailingZeros() int                       { return bits.TrailingZeros16(uint16(f)) }
+func (f BitFlags16) LeadingZeros() int                        { return bits.LeadingZeros16(uint16(f)) }
+func (f BitFlags16) CloneBitFlags() BitFlags                  { return &f }
+func (f BitFlags16) Uint64() uint64                           { return uint64(f) }
+func (f *BitFlags16) SetUint64(v uint64)                      { setUint64(f, 16, v) }
 func (f *BitFlags16) BitFlags() BitFlags                      { return f }
 
 func (f BitFlags32) Is(idx BitIndex) (set bool)               { return is(f, 32, idx) }
@@ -161,6 +248,15 @@ func (f BitFlags32) AllOf(idx ...BitIndex) bool               { return allSet(f,
 func (BitFlags32) Size() int                                  { return 32 }
 func (f BitFlags32) String() string                           { return getBinaryString(f, 32) }
 func (f BitFlags32) PrettyString() string                     { return getPrettyString(f, 32) }
+func (f BitFlags32) CountSet() int                            { return bits.OnesCount32(uint32(f)) }
+func (f BitFlags32) Bits() iter.Seq2[BitIndex, bool]          { return bitsSeq(f, 32) }
+func (f BitFlags32) SetBits() iter.Seq[BitIndex]              { return setBitsSeq(f) }
+func (f BitFlags32) Len() int                                 { return bits.Len32(uint32(f)) }
+func (f BitFlags32) TrailingZeros() int                       { return bits.TrailingZeros32(uint32(f)) }
+func (f BitFlags32) LeadingZeros() int                        { return bits.LeadingZeros32(uint32(f)) }
+func (f BitFlags32) CloneBitFlags() BitFlags                  { return &f }
+func (f BitFlags32) Uint64() uint64                           { return uint64(f) }
+func (f *BitFlags32) SetUint64(v uint64)                      { setUint64(f, 32, v) }
 func (f *BitFlags32) BitFlags() BitFlags                      { return f }
 
 func (f BitFlags64) Is(idx BitIndex) (set bool)               { return is(f, 64, idx) }
@@ -177,6 +273,15 @@ func (f BitFlags64) AllOf(idx ...BitIndex) bool               { return allSet(f,
 func (BitFlags64) Size() int                                  { return 64 }
 func (f BitFlags64) String() string                           { return getBinaryString(f, 64) }
 func (f BitFlags64) PrettyString() string                     { return getPrettyString(f, 64) }
+func (f BitFlags64) CountSet() int                            { return bits.OnesCount64(uint64(f)) }
+func (f BitFlags64) Bits() iter.Seq2[BitIndex, bool]          { return bitsSeq(f, 64) }
+func (f BitFlags64) SetBits() iter.Seq[BitIndex]              { return setBitsSeq(f) }
+func (f BitFlags64) Len() int                                 { return bits.Len64(uint64(f)) }
+func (f BitFlags64) TrailingZeros() int                       { return bits.TrailingZeros64(uint64(f)) }
+func (f BitFlags64) LeadingZeros() int                        { return bits.LeadingZeros64(uint64(f)) }
+func (f BitFlags64) CloneBitFlags() BitFlags                  { return &f }
+func (f BitFlags64) Uint64() uint64                           { return uint64(f) }
+func (f *BitFlags64) SetUint64(v uint64)                      { setUint64(f, 64, v) }
 func (f *BitFlags64) BitFlags() BitFlags                      { return f }
 
 type bitFlags interface {
@@ -188,33 +293,103 @@ type bitFlagsTypes interface {
 		uint8 | uint16 | uint32 | uint64
 }
 
-func validateBitIndex(size int, idx BitIndex) {
-	if idx >= 0 && idx < size {
-		return
+// IndexError describes a [BitIndex] out of the allowed range [0, Size-1]
+// of one of the BitFlags types.
+// It's the value the methods panic with when passed such an index, and the
+// error returned by their Try variants instead, like [BitFlags8.TryIs].
+type IndexError struct {
+	Idx  BitIndex // the invalid index.
+	Size int      // the size of the BitFlags type.
+}
+
+// Error returns a message like "index 16 out of range [0..15]", which
+// doesn't include the index if it has more than 2 digits.
+func (e IndexError) Error() string {
+	return indexErrorString(e.Size, e.Idx)
+}
+
+// RangeError describes a bit range [From, To) out of the allowed range
+// [0, Size] of one of the BitFlags types, or ending before it starts.
+// It's the value the range methods, like [BitFlags8.SetRange], panic with
+// when passed such a range.
+type RangeError struct {
+	From, To BitIndex // the invalid range.
+	Size     int      // the size of the BitFlags type.
+}
+
+// Error returns a message like "range [3:17) out of range [0..16]", which
+// doesn't include the range if any of its ends has more than 2 digits.
+func (e RangeError) Error() string {
+	return rangeErrorString(e.Size, e.From, e.To)
+}
+
+// ValueOverflowError describes a value with set bits beyond the size of
+// one of the BitFlags types.
+// It's the value [BitFlags8.SetUint64], and the CopyFrom methods with
+// [OverflowPanic], panic with when passed such a value.
+type ValueOverflowError struct {
+	Value uint64 // the overflowing value.
+	Size  int    // the size of the BitFlags type.
+}
+
+// Error returns a message like "value overflows 8 bits".
+func (e ValueOverflowError) Error() string {
+	return "value overflows " + small(e.Size) + " bits"
+}
+
+// Unwrap returns [ErrOverflow], which the CopyFrom methods return instead
+// of panicking, with [OverflowError], so [errors.Is] matches both.
+func (e ValueOverflowError) Unwrap() error { return ErrOverflow }
+
+// CountError describes a number of flags out of the allowed range [0, 64].
+// It's the value [SmallestFor] panics with when passed such a number.
+type CountError struct {
+	Count int // the invalid number of flags.
+}
+
+// Error returns the message "flags count out of range [0..64]".
+func (e CountError) Error() string { return "flags count out of range [0..64]" }
+
+// EmptyIndexesError describes an empty list of bit indexes, passed where
+// at least one index is required.
+// It's the value the Strict methods, like [BitFlags8.AnyOfStrict], panic
+// with when passed no indexes.
+type EmptyIndexesError struct{}
+
+// Error returns the message "no indexes passed".
+func (EmptyIndexesError) Error() string { return "no indexes passed" }
+
+func validateBitIndexes(size int, idx ...BitIndex) {
+	for _, bi := range idx {
+		validateBitIndex(size, bi)
 	}
-	validateBitIndexSlow(size, idx)
 }
 
 func validateBitIndexSlow(size int, idx BitIndex) {
-	// print a helpful panic message without using fmt or strconv.
+	panic(IndexError{Idx: idx, Size: size})
+}
+
+// indexErrorString returns the message of an out of range idx.
+func indexErrorString(size int, idx BitIndex) string {
+	// print a helpful message without using fmt or strconv.
 	strLen := 30 // of "index -00 out of range [0..00]"
-	panicStr := make(stringBuilder, 0, strLen)
-	panicStr.WriteString("index ")
+	str := make(stringBuilder, 0, strLen)
+	str.WriteString("index ")
 
 	// only print the idx if it's between -nSmalls and nSmalls.
 	if -nSmalls < idx && idx < nSmalls { // 2-digit number
 		if idx < 0 {
 			idx = -idx
-			panicStr.WriteByte('-')
+			str.WriteByte('-')
 		}
-		panicStr.WriteString(small(idx))
-		panicStr.WriteByte(' ')
+		str.WriteString(small(idx))
+		str.WriteByte(' ')
 	}
 
-	panicStr.WriteString("out of range [0..")
-	panicStr.WriteString(sizeIndexString(size))
-	panicStr.WriteString("]")
-	panic(panicStr.String())
+	str.WriteString("out of range [0..")
+	str.WriteString(sizeIndexString(size))
+	str.WriteString("]")
+	return str.String()
 }
 
 func isUint[T bitFlagsTypes](f T, idx BitIndex) (set bool) {
@@ -228,6 +403,10 @@ func is[T bitFlags](f T, size int, idx BitIndex) (set bool) {
 
 func set[T bitFlags](f *T, size int, idx BitIndex, new bool) (old bool) {
 	validateBitIndex(size, idx)
+	return setUnchecked(f, idx, new)
+}
+
+func setUnchecked[T bitFlags](f *T, idx BitIndex, new bool) (old bool) {
 	old = isUint(*f, idx)
 	if new {
 		*f |= 1 << idx
@@ -239,10 +418,21 @@ func set[T bitFlags](f *T, size int, idx BitIndex, new bool) (old bool) {
 
 func toggle[T bitFlags](f *T, size int, idx BitIndex) (new bool) {
 	validateBitIndex(size, idx)
+	return toggleUnchecked(f, idx)
+}
+
+func toggleUnchecked[T bitFlags](f *T, idx BitIndex) (new bool) {
 	*f ^= 1 << idx
 	return isUint(*f, idx)
 }
 
+func setUint64[T bitFlags](f *T, size int, v uint64) {
+	if size < 64 && v>>size != 0 {
+		panic(ValueOverflowError{Value: v, Size: size})
+	}
+	*f = T(v)
+}
+
 func setAll[T bitFlags](f *T) {
 	var all = ^T(0)
 	*f = all
@@ -259,15 +449,16 @@ func anySet[T bitFlagsTypes](f T, size int, idx ...BitIndex) bool {
 	return anySetSlow(f, size, idx...)
 }
 
+// anySetSlow validates all the indexes first, then stops at the first set
+// bit, so the result, or the panic, doesn't depend on the order of idx.
 func anySetSlow[T bitFlagsTypes](f T, size int, idx ...BitIndex) bool {
-	foundSet := false
+	validateBitIndexes(size, idx...)
 	for _, bi := range idx {
-		validateBitIndex(size, bi)
-		if (f & (1 << bi)) != 0 {
-			foundSet = true
+		if isUint(f, bi) {
+			return true
 		}
 	}
-	return foundSet
+	return false
 }
 
 func allSet[T bitFlagsTypes](f T, size int, idx ...BitIndex) bool {
@@ -277,52 +468,61 @@ func allSet[T bitFlagsTypes](f T, size int, idx ...BitIndex) bool {
 	return allSetSlow(f, size, idx...)
 }
 
+// allSetSlow validates all the indexes first, then stops at the first
+// unset bit, so the result, or the panic, doesn't depend on the order of idx.
 func allSetSlow[T bitFlagsTypes](f T, size int, idx ...BitIndex) bool {
-	foundUnset := true
+	validateBitIndexes(size, idx...)
 	for _, bi := range idx {
-		validateBitIndex(size, bi)
-		if (f & (1 << bi)) == 0 {
-			foundUnset = false
+		if !isUint(f, bi) {
+			return false
 		}
 	}
-	return foundUnset
+	return true
 }
 
 func getBinaryString[T bitFlagsTypes](f T, size int) string {
-	str := make(stringBuilder, 0, size)
+	return string(appendBinaryString(make([]byte, 0, size), f, size))
+}
+
+// appendBinaryString appends f to dst like "0100010001000100".
+func appendBinaryString[T bitFlagsTypes](dst []byte, f T, size int) []byte {
 	for i := range size {
 		if (f & (1 << (size - i - 1))) != 0 {
-			str.WriteByte('1')
+			dst = append(dst, '1')
 		} else {
-			str.WriteByte('0')
+			dst = append(dst, '0')
 		}
 	}
-	return str.String()
+	return dst
 }
 
 // getPrettyString prints f like "O|I|O|O|O|I|O|O_O|I|O|O|O|I|O|O"
 func getPrettyString[T bitFlagsTypes](f T, size int) string {
-	str := make(stringBuilder, 0, size+(size-1)+(size/8-1))
+	return string(appendPrettyString(make([]byte, 0, prettyStringLen(size)), f, size))
+}
+
+// prettyStringLen returns the length of the PrettyString of size bits.
+func prettyStringLen(size int) int {
+	return size + (size - 1) + (size/8 - 1)
+}
+
+// appendPrettyString appends f to dst like "O|I|O|O|O|I|O|O_O|I|O|O|O|I|O|O"
+func appendPrettyString[T bitFlagsTypes](dst []byte, f T, size int) []byte {
 	for i := range size {
 		if (f & (1 << (size - i - 1))) != 0 {
-			if i == size-1 {
-				str.WriteString("I")
-			} else if (i+1)%8 == 0 && i != 0 {
-				str.WriteString("I_")
-			} else {
-				str.WriteString("I|")
-			}
+			dst = append(dst, 'I')
 		} else {
-			if i == size-1 {
-				str.WriteString("O")
-			} else if (i+1)%8 == 0 && i != 0 {
-				str.WriteString("O_")
-			} else {
-				str.WriteString("O|")
-			}
+			dst = append(dst, 'O')
+		}
+		if i == size-1 {
+			break
+		} else if (i+1)%8 == 0 {
+			dst = append(dst, '_')
+		} else {
+			dst = append(dst, '|')
 		}
 	}
-	return str.String()
+	return dst
 }
 
 // stringBuilder is a simplified version of [strings.Builder],
@@ -332,8 +532,9 @@ func getPrettyString[T bitFlagsTypes](f T, size int) string {
 // the strings package.
 type stringBuilder []byte
 
-func (sb *stringBuilder) WriteByte(b byte) {
+func (sb *stringBuilder) WriteByte(b byte) error {
 	*sb = append(*sb, b)
+	return nil
 }
 func (sb *stringBuilder) WriteString(s string) {
 	*sb = append(*sb, s...)
@@ -342,6 +543,94 @@ func (sb *stringBuilder) String() string {
 	return string(*sb)
 }
 
+// appendItoa appends the decimal form of the non-negative i to dst, like
+// strconv.AppendInt, but without depending on the strconv package.
+func appendItoa(dst []byte, i int) []byte {
+	if i < nSmalls {
+		return append(dst, small(i)...)
+	}
+	r := i % nSmalls
+	return append(appendItoa(dst, i/nSmalls), smallsString[r*2:r*2+2]...)
+}
+
+// itoa returns the decimal form of the non-negative i.
+func itoa(i int) string {
+	return string(appendItoa(nil, i))
+}
+
+// atoi returns the value of s, if it's a non-empty string of decimal
+// digits, like strconv.Atoi, but without depending on the strconv package.
+// Values above maxAtoi, which is out of the range of any bit index, are
+// returned as maxAtoi, instead of overflowing.
+func atoi(s string) (n int, ok bool) {
+	if s == "" {
+		return 0, false
+	}
+	for i := range len(s) {
+		c := s[i]
+		if c < '0' || c > '9' {
+			return 0, false
+		}
+		n = min(n*10+int(c-'0'), maxAtoi)
+	}
+	return n, true
+}
+
+// maxAtoi is the largest value returned by atoi.
+const maxAtoi = 1 << 24
+
+// appendQuote appends s to dst as a double-quoted Go string literal, like
+// strconv.Quote, but without depending on the strconv package.
+// Unlike strconv.Quote, it only escapes the ASCII control characters and
+// the bytes of invalid UTF-8, leaving the other runes as they are.
+func appendQuote(dst []byte, s string) []byte {
+	dst = append(dst, '"')
+	for i := 0; i < len(s); {
+		c := s[i]
+		if c >= utf8.RuneSelf {
+			r, n := utf8.DecodeRuneInString(s[i:])
+			if r == utf8.RuneError && n == 1 {
+				dst = append(dst, '\\', 'x', digits[c>>4], digits[c&0xf])
+			} else {
+				dst = append(dst, s[i:i+n]...)
+			}
+			i += n
+			continue
+		}
+		switch c {
+		case '"', '\\':
+			dst = append(dst, '\\', c)
+		case '\a':
+			dst = append(dst, `\a`...)
+		case '\b':
+			dst = append(dst, `\b`...)
+		case '\f':
+			dst = append(dst, `\f`...)
+		case '\n':
+			dst = append(dst, `\n`...)
+		case '\r':
+			dst = append(dst, `\r`...)
+		case '\t':
+			dst = append(dst, `\t`...)
+		case '\v':
+			dst = append(dst, `\v`...)
+		default:
+			if c < ' ' || c == 0x7f {
+				dst = append(dst, '\\', 'x', digits[c>>4], digits[c&0xf])
+			} else {
+				dst = append(dst, c)
+			}
+		}
+		i++
+	}
+	return append(dst, '"')
+}
+
+// quote returns s as a double-quoted Go string literal, like appendQuote.
+func quote(s string) string {
+	return string(appendQuote(nil, s))
+}
+
 // sizeIndexString returns size-1 as a string.
 func sizeIndexString(size int) string {
 	switch size {
@@ -315,7 +315,7 @@ func helperRunBenchmarkString[T bitFlags, TP ptrBitFlags[T]](b *testing.B) {
 				b.ReportAllocs()
 				var f TP = &tt.initial
 				for i := 0; i < b.N; i++ {
-					f.String()
+					_ = f.String()
 				}
 			})
 		}
@@ -466,3 +466,90 @@ func BenchmarkBitFlags_BitFlags(b *testing.B) {
 		}
 	})
 }
+
+func BenchmarkBitFlags_SetMask(b *testing.B) {
+	idx := []BitIndex{1, 3, 5, 7, 11, 13, 17, 19}
+	var mask BitFlags64
+	mask.SetIndices(idx...)
+
+	b.Run("SetMask", func(b *testing.B) {
+		b.ReportAllocs()
+		var f BitFlags64
+		for i := 0; i < b.N; i++ {
+			f.SetMask(mask)
+			f.ResetMask(mask)
+		}
+	})
+	b.Run("Set per index", func(b *testing.B) {
+		b.ReportAllocs()
+		var f BitFlags64
+		for i := 0; i < b.N; i++ {
+			for _, bi := range idx {
+				f.Set(bi)
+			}
+			for _, bi := range idx {
+				f.Reset(bi)
+			}
+		}
+	})
+}
+
+func BenchmarkBitFlags_IsUnchecked(b *testing.B) {
+	f := ^BitFlags64(0)
+
+	b.Run("Is", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			_ = f.Is(i & 63)
+		}
+	})
+	b.Run("IsUnchecked", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			_ = f.IsUnchecked(i & 63)
+		}
+	})
+}
+
+func helperRunBenchmarkAppendPrettyString[T bitFlags, TP interface {
+	ptrBitFlags[T]
+	AppendPrettyString(dst []byte) []byte
+}](b *testing.B) {
+	var (
+		zero   T
+		allset = ^zero
+	)
+	type testCase struct {
+		name    string
+		initial T
+	}
+	tests := []testCase{
+		{
+			name:    "zero",
+			initial: zero,
+		},
+		{
+			name:    "allset",
+			initial: allset,
+		},
+	}
+	b.Run(fmt.Sprintf("%T", zero), func(b *testing.B) {
+		for _, tt := range tests {
+			b.Run(tt.name, func(b *testing.B) {
+				b.ReportAllocs()
+				var f TP = &tt.initial
+				buf := make([]byte, 0, 128)
+				for i := 0; i < b.N; i++ {
+					buf = f.AppendPrettyString(buf[:0])
+				}
+			})
+		}
+	})
+}
+
+func BenchmarkBitFlags_AppendPrettyString(b *testing.B) {
+	helperRunBenchmarkAppendPrettyString[BitFlags8](b)
+	helperRunBenchmarkAppendPrettyString[BitFlags16](b)
+	helperRunBenchmarkAppendPrettyString[BitFlags32](b)
+	helperRunBenchmarkAppendPrettyString[BitFlags64](b)
+}
@@ -1,7 +1,9 @@
 package flagged
 
 import (
+	"errors"
 	"fmt"
+	"strconv"
 	"testing"
 )
 
@@ -168,6 +170,9 @@ func helperRunTestIs[T bitFlags, TP ptrBitFlags[T]](t *testing.T) {
 				var f TP = &tt.initial
 
 				for ti, tr := range tt.runs {
+					if tr.panics && !validatesIndexes {
+						continue
+					}
 					func() {
 						defer func() {
 							v := recover()
@@ -306,6 +311,9 @@ func helperRunTestSet[T bitFlags, TP ptrBitFlags[T]](t *testing.T) {
 				var f TP = &tt.initial
 
 				for ti, tr := range tt.runs {
+					if tr.panics && !validatesIndexes {
+						continue
+					}
 					func() {
 						defer func() {
 							v := recover()
@@ -453,6 +461,9 @@ func helperRunTestReset[T bitFlags, TP ptrBitFlags[T]](t *testing.T) {
 				var f TP = &tt.initial
 
 				for ti, tr := range tt.runs {
+					if tr.panics && !validatesIndexes {
+						continue
+					}
 					func() {
 						defer func() {
 							v := recover()
@@ -600,6 +611,9 @@ func helperRunTestToggle[T bitFlags, TP ptrBitFlags[T]](t *testing.T) {
 				var f TP = &tt.initial
 
 				for ti, tr := range tt.runs {
+					if tr.panics && !validatesIndexes {
+						continue
+					}
 					func() {
 						defer func() {
 							v := recover()
@@ -981,6 +995,9 @@ func helperRunTestAnyOf[T bitFlags, TP ptrBitFlags[T]](t *testing.T) {
 				var f TP = &tt.initial
 
 				for ti, tr := range tt.runs {
+					if tr.panics && !validatesIndexes {
+						continue
+					}
 					func() {
 						defer func() {
 							v := recover()
@@ -1125,6 +1142,9 @@ func helperRunTestAllOf[T bitFlags, TP ptrBitFlags[T]](t *testing.T) {
 				var f TP = &tt.initial
 
 				for ti, tr := range tt.runs {
+					if tr.panics && !validatesIndexes {
+						continue
+					}
 					func() {
 						defer func() {
 							v := recover()
@@ -1320,54 +1340,353 @@ func TestBitFlags_PrettyString(t *testing.T) {
 	)
 }
 
-func Test_validateBitIndex_panic(t *testing.T) {
-	tests := []struct {
-		name   string
-		size   int
-		idx    BitIndex
-		panicV any
-	}{
+func helperRunTestCountSet[T bitFlags, TP ptrBitFlags[T]](t *testing.T) {
+	var (
+		zero   T
+		allset = ^zero
+		size   = TP(&zero).Size()
+	)
+	type testCase struct {
+		name    string
+		initial T
+		want    int
+	}
+	tests := []testCase{
+		{
+			name:    "zero",
+			initial: zero,
+			want:    0,
+		},
+		{
+			name:    "allset",
+			initial: allset,
+			want:    size,
+		},
+		{
+			name:    "partial",
+			initial: zero | T(1)<<1 | T(1)<<(size-1),
+			want:    2,
+		},
+	}
+	t.Run(fmt.Sprintf("%T", zero), func(t *testing.T) {
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				var f TP = &tt.initial
+
+				if got := f.CountSet(); got != tt.want {
+					t.Errorf("CountSet() = %v, want = %v", got, tt.want)
+				}
+			})
+		}
+	})
+}
+
+func TestBitFlags_CountSet(t *testing.T) {
+	helperRunTestCountSet[BitFlags8](t)
+	helperRunTestCountSet[BitFlags16](t)
+	helperRunTestCountSet[BitFlags32](t)
+	helperRunTestCountSet[BitFlags64](t)
+}
+
+func helperRunTestLenZeros[T bitFlags, TP ptrBitFlags[T]](t *testing.T) {
+	var (
+		zero   T
+		allset = ^zero
+		size   = TP(&zero).Size()
+	)
+	type testCase struct {
+		name              string
+		initial           T
+		wantLen           int
+		wantTrailingZeros int
+		wantLeadingZeros  int
+	}
+	tests := []testCase{
 		{
-			name:   "no panic",
-			size:   8,
-			idx:    7,
-			panicV: nil,
+			name:              "zero",
+			initial:           zero,
+			wantLen:           0,
+			wantTrailingZeros: size,
+			wantLeadingZeros:  size,
 		},
 		{
-			name:   "positive panic - small idx",
-			size:   16,
-			idx:    16,
-			panicV: "index 16 out of range [0..15]",
+			name:              "allset",
+			initial:           allset,
+			wantLen:           size,
+			wantTrailingZeros: 0,
+			wantLeadingZeros:  0,
 		},
 		{
-			name:   "negative panic - small idx",
-			size:   64,
-			idx:    -99,
-			panicV: "index -99 out of range [0..63]",
+			name:              "partial",
+			initial:           zero | T(1)<<2 | T(1)<<(size-3),
+			wantLen:           size - 2,
+			wantTrailingZeros: 2,
+			wantLeadingZeros:  2,
+		},
+	}
+	t.Run(fmt.Sprintf("%T", zero), func(t *testing.T) {
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				var f TP = &tt.initial
+
+				if got := f.Len(); got != tt.wantLen {
+					t.Errorf("Len() = %v, want = %v", got, tt.wantLen)
+				}
+				if got := f.TrailingZeros(); got != tt.wantTrailingZeros {
+					t.Errorf("TrailingZeros() = %v, want = %v", got, tt.wantTrailingZeros)
+				}
+				if got := f.LeadingZeros(); got != tt.wantLeadingZeros {
+					t.Errorf("LeadingZeros() = %v, want = %v", got, tt.wantLeadingZeros)
+				}
+			})
+		}
+	})
+}
+
+func TestBitFlags_LenZeros(t *testing.T) {
+	helperRunTestLenZeros[BitFlags8](t)
+	helperRunTestLenZeros[BitFlags16](t)
+	helperRunTestLenZeros[BitFlags32](t)
+	helperRunTestLenZeros[BitFlags64](t)
+}
+
+func helperRunTestCloneBitFlags[T bitFlags, TP ptrBitFlags[T]](t *testing.T) {
+	var (
+		zero   T
+		allset = ^zero
+		size   = TP(&zero).Size()
+	)
+	type testCase struct {
+		name    string
+		initial T
+	}
+	tests := []testCase{
+		{
+			name:    "zero",
+			initial: zero,
 		},
 		{
-			name:   "positive panic - big idx",
-			size:   32,
-			idx:    100,
-			panicV: "index out of range [0..31]",
+			name:    "allset",
+			initial: allset,
 		},
 		{
-			name:   "negative panic - big idx",
-			size:   64,
-			idx:    -9999,
-			panicV: "index out of range [0..63]",
+			name:    "partial",
+			initial: zero | T(1)<<1 | T(1)<<(size-1),
 		},
 	}
+	t.Run(fmt.Sprintf("%T", zero), func(t *testing.T) {
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				var f TP = &tt.initial
+
+				c := f.CloneBitFlags()
+				cp, ok := c.(TP)
+				if !ok {
+					t.Fatalf("CloneBitFlags() = %T, want = %T", c, f)
+				}
+				if *cp != *f {
+					t.Errorf("CloneBitFlags() = %v, want = %v", c.String(), f.String())
+				}
+				if cp == f {
+					t.Fatal("CloneBitFlags() returned the same pointer")
+				}
+
+				c.Toggle(0)
+				if *cp == *f {
+					t.Error("CloneBitFlags() is not independent of the original")
+				}
+			})
+		}
+	})
+}
+
+func TestBitFlags_CloneBitFlags(t *testing.T) {
+	helperRunTestCloneBitFlags[BitFlags8](t)
+	helperRunTestCloneBitFlags[BitFlags16](t)
+	helperRunTestCloneBitFlags[BitFlags32](t)
+	helperRunTestCloneBitFlags[BitFlags64](t)
+}
+
+func helperRunTestUint64[T bitFlags, TP ptrBitFlags[T]](t *testing.T) {
+	var (
+		zero   T
+		allset = ^zero
+		size   = TP(&zero).Size()
+	)
+	type testCase struct {
+		name    string
+		initial T
+		v       uint64
+		panicV  any
+	}
+	tests := []testCase{
+		{
+			name:    "zero",
+			initial: allset,
+			v:       0,
+		},
+		{
+			name:    "allset",
+			initial: zero,
+			v:       uint64(allset),
+		},
+		{
+			name:    "partial",
+			initial: zero,
+			v:       1<<1 | 1<<(size-1),
+		},
+	}
+	if size < 64 {
+		tests = append(tests, testCase{
+			name:    "overflow",
+			initial: zero | 0b0101,
+			v:       1 << size,
+			panicV:  ValueOverflowError{Value: 1 << size, Size: size},
+		})
+	}
+	t.Run(fmt.Sprintf("%T", zero), func(t *testing.T) {
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				f := tt.initial
+				func() {
+					defer func() {
+						r := recover()
+						if r != tt.panicV {
+							t.Errorf("SetUint64() panic = %v, want = %v", r, tt.panicV)
+						}
+						if r == nil {
+							return
+						}
+						var overflowErr ValueOverflowError
+						if err, _ := r.(error); !errors.As(err, &overflowErr) || !errors.Is(err, ErrOverflow) {
+							t.Errorf("SetUint64() panic = %v, want a ValueOverflowError", r)
+						}
+					}()
+					TP(&f).SetUint64(tt.v)
+				}()
+
+				want := tt.v
+				if tt.panicV != nil {
+					want = uint64(tt.initial)
+				}
+				if got := TP(&f).Uint64(); got != want {
+					t.Errorf("Uint64() = %v, want = %v", got, want)
+				}
+			})
+		}
+	})
+}
+
+func TestBitFlags_Uint64(t *testing.T) {
+	helperRunTestUint64[BitFlags8](t)
+	helperRunTestUint64[BitFlags16](t)
+	helperRunTestUint64[BitFlags32](t)
+	helperRunTestUint64[BitFlags64](t)
+}
+
+func TestSmallestFor(t *testing.T) {
+	tests := []struct {
+		n      int
+		want   BitFlags
+		panicV any
+	}{
+		{n: 0, want: new(BitFlags8)},
+		{n: 8, want: new(BitFlags8)},
+		{n: 9, want: new(BitFlags16)},
+		{n: 16, want: new(BitFlags16)},
+		{n: 17, want: new(BitFlags32)},
+		{n: 32, want: new(BitFlags32)},
+		{n: 33, want: new(BitFlags64)},
+		{n: 64, want: new(BitFlags64)},
+		{n: 65, panicV: CountError{Count: 65}},
+		{n: -1, panicV: CountError{Count: -1}},
+	}
 	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
+		t.Run(fmt.Sprint(tt.n), func(t *testing.T) {
 			defer func() {
-				v := recover()
-				if v != tt.panicV {
-					t.Errorf("got panicV: %v; want: %v", v, tt.panicV)
+				r := recover()
+				if r != tt.panicV {
+					t.Errorf("SmallestFor() panic = %v, want = %v", r, tt.panicV)
+				}
+				if r == nil {
+					return
+				}
+				var countErr CountError
+				if err, _ := r.(error); !errors.As(err, &countErr) {
+					t.Errorf("SmallestFor() panic = %v, want a CountError", r)
 				}
 			}()
-
-			validateBitIndex(tt.size, tt.idx)
+			got := SmallestFor(tt.n)
+			if fmt.Sprintf("%T", got) != fmt.Sprintf("%T", tt.want) {
+				t.Errorf("SmallestFor() = %T, want = %T", got, tt.want)
+			}
+			if got.AnySet() {
+				t.Errorf("SmallestFor() = %v, want zero", got)
+			}
 		})
 	}
 }
+
+// skipUnvalidated skips t if it expects out of range bit indexes to panic,
+// while they aren't validated, with the flagged_unsafe tag.
+func skipUnvalidated(t *testing.T, panics bool) {
+	t.Helper()
+	if panics && !validatesIndexes {
+		t.Skip("bit indexes aren't validated with the flagged_unsafe tag")
+	}
+}
+
+func Test_itoa(t *testing.T) {
+	for _, i := range []int{0, 7, 10, 63, 99, 100, 101, 1000, 12345678} {
+		want := strconv.Itoa(i)
+		if got := itoa(i); got != want {
+			t.Errorf("itoa(%v) = %q, want = %q", i, got, want)
+		}
+		if got, ok := atoi(want); !ok || got != i {
+			t.Errorf("atoi(%q) = %v, %v, want = %v, true", want, got, ok, i)
+		}
+	}
+}
+
+func Test_atoi(t *testing.T) {
+	tests := []struct {
+		s    string
+		want int
+		ok   bool
+	}{
+		{"0", 0, true},
+		{"007", 7, true},
+		{"63", 63, true},
+		{"99999999999999999999999", maxAtoi, true},
+		{"", 0, false},
+		{"-1", 0, false},
+		{"+1", 0, false},
+		{"1a", 0, false},
+		{" 1", 0, false},
+	}
+	for _, tt := range tests {
+		if got, ok := atoi(tt.s); got != tt.want || ok != tt.ok {
+			t.Errorf("atoi(%q) = %v, %v, want = %v, %v", tt.s, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func Test_quote(t *testing.T) {
+	for _, s := range []string{
+		"",
+		"Read",
+		"Read|Write",
+		`say "hi"`,
+		`C:\dir`,
+		"a\a\b\f\n\r\t\vb",
+		"\x00\x1f\x7f",
+		"héllo, 世界",
+		"\xff\xfe",
+		"\xe4\xb8",
+	} {
+		want := strconv.Quote(s)
+		if got := quote(s); got != want {
+			t.Errorf("quote(%q) = %s, want = %s", s, got, want)
+		}
+	}
+}
@@ -0,0 +1,174 @@
+package flagged
+
+import (
+	"fmt"
+	"math/bits"
+)
+
+// format implements [fmt.Formatter] for the BitFlags types.
+// The integer verbs (%b, %o, %O, %d, %x, %X) format the underlying value,
+// with all the flags, like the width, precision, '#' and '0', while the
+// %v and %s verbs use the binary String representation, and %q quotes it,
+// and %#v uses the GoString representation.
+func format[T bitFlagsTypes](f T, size int, s fmt.State, verb rune) {
+	switch {
+	case verb == 'v' && s.Flag('#'):
+		fmt.Fprintf(s, fmt.FormatString(s, 's'), goString(f, size))
+	case verb == 'v', verb == 's', verb == 'q':
+		fmt.Fprintf(s, fmt.FormatString(s, verb), getBinaryString(f, size))
+	default:
+		// Including the invalid verbs, which are reported as for uint64.
+		fmt.Fprintf(s, fmt.FormatString(s, verb), uint64(f))
+	}
+}
+
+// goString returns f as a Go expression, like "flagged.BitFlags8(0b00000101)".
+func goString[T bitFlagsTypes](f T, size int) string {
+	return "flagged.BitFlags" + small(size) + "(0b" + getBinaryString(f, size) + ")"
+}
+
+// Format implements [fmt.Formatter], so the integer verbs, like %08b and
+// %#x, format the underlying value, while %v and %s use [BitFlags8.String].
+func (f BitFlags8) Format(s fmt.State, verb rune) { format(f, 8, s, verb) }
+
+// Format implements [fmt.Formatter], so the integer verbs, like %016b and
+// %#x, format the underlying value, while %v and %s use [BitFlags16.String].
+func (f BitFlags16) Format(s fmt.State, verb rune) { format(f, 16, s, verb) }
+
+// Format implements [fmt.Formatter], so the integer verbs, like %032b and
+// %#x, format the underlying value, while %v and %s use [BitFlags32.String].
+func (f BitFlags32) Format(s fmt.State, verb rune) { format(f, 32, s, verb) }
+
+// Format implements [fmt.Formatter], so the integer verbs, like %064b and
+// %#x, format the underlying value, while %v and %s use [BitFlags64.String].
+func (f BitFlags64) Format(s fmt.State, verb rune) { format(f, 64, s, verb) }
+
+// GoString returns f as a Go expression, in binary, zero-padded to all its
+// 8 bits, like, for 0b101:
+//
+//	flagged.BitFlags8(0b00000101)
+//
+// It implements [fmt.GoStringer], and it's used by the %#v verb.
+func (f BitFlags8) GoString() string { return goString(f, 8) }
+
+// GoString returns f as a Go expression, in binary, zero-padded to all its
+// 16 bits, like, for 0b101:
+//
+//	flagged.BitFlags16(0b0000000000000101)
+//
+// It implements [fmt.GoStringer], and it's used by the %#v verb.
+func (f BitFlags16) GoString() string { return goString(f, 16) }
+
+// GoString returns f as a Go expression, in binary, zero-padded to all its
+// 32 bits, like, for 0b101:
+//
+//	flagged.BitFlags32(0b00000000000000000000000000000101)
+//
+// It implements [fmt.GoStringer], and it's used by the %#v verb.
+func (f BitFlags32) GoString() string { return goString(f, 32) }
+
+// GoString returns f as a Go expression, in binary, zero-padded to all its
+// 64 bits, like, for 0b101:
+//
+//	flagged.BitFlags64(0b0000000000000000000000000000000000000000000000000000000000000101)
+//
+// It implements [fmt.GoStringer], and it's used by the %#v verb.
+func (f BitFlags64) GoString() string { return goString(f, 64) }
+
+// AppendString appends the binary representation of f, as returned by
+// [BitFlags8.String], to dst and returns the extended buffer.
+func (f BitFlags8) AppendString(dst []byte) []byte { return appendBinaryString(dst, f, 8) }
+
+// AppendString appends the binary representation of f, as returned by
+// [BitFlags16.String], to dst and returns the extended buffer.
+func (f BitFlags16) AppendString(dst []byte) []byte { return appendBinaryString(dst, f, 16) }
+
+// AppendString appends the binary representation of f, as returned by
+// [BitFlags32.String], to dst and returns the extended buffer.
+func (f BitFlags32) AppendString(dst []byte) []byte { return appendBinaryString(dst, f, 32) }
+
+// AppendString appends the binary representation of f, as returned by
+// [BitFlags64.String], to dst and returns the extended buffer.
+func (f BitFlags64) AppendString(dst []byte) []byte { return appendBinaryString(dst, f, 64) }
+
+// AppendPrettyString appends the representation of f returned by
+// [BitFlags8.PrettyString] to dst and returns the extended buffer.
+func (f BitFlags8) AppendPrettyString(dst []byte) []byte { return appendPrettyString(dst, f, 8) }
+
+// AppendPrettyString appends the representation of f returned by
+// [BitFlags16.PrettyString] to dst and returns the extended buffer.
+func (f BitFlags16) AppendPrettyString(dst []byte) []byte { return appendPrettyString(dst, f, 16) }
+
+// AppendPrettyString appends the representation of f returned by
+// [BitFlags32.PrettyString] to dst and returns the extended buffer.
+func (f BitFlags32) AppendPrettyString(dst []byte) []byte { return appendPrettyString(dst, f, 32) }
+
+// AppendPrettyString appends the representation of f returned by
+// [BitFlags64.PrettyString] to dst and returns the extended buffer.
+func (f BitFlags64) AppendPrettyString(dst []byte) []byte { return appendPrettyString(dst, f, 64) }
+
+// hexString returns f in hex, with all its size/4 digits, like "0x05".
+func hexString[T bitFlagsTypes](f T, size int) string {
+	const digits = "0123456789abcdef"
+	str := make(stringBuilder, 0, 2+size/4)
+	str.WriteString("0x")
+	for i := size - 4; i >= 0; i -= 4 {
+		str.WriteByte(digits[(f>>i)&0xf])
+	}
+	return str.String()
+}
+
+// Hex returns f in hex, with all its 2 digits, like "0x05".
+// It's the inverse of [ParseHex].
+func (f BitFlags8) Hex() string { return hexString(f, 8) }
+
+// Hex returns f in hex, with all its 4 digits, like "0x0005".
+// It's the inverse of [ParseHex].
+func (f BitFlags16) Hex() string { return hexString(f, 16) }
+
+// Hex returns f in hex, with all its 8 digits, like "0x00000005".
+// It's the inverse of [ParseHex].
+func (f BitFlags32) Hex() string { return hexString(f, 32) }
+
+// Hex returns f in hex, with all its 16 digits, like "0x0000000000000005".
+// It's the inverse of [ParseHex].
+func (f BitFlags64) Hex() string { return hexString(f, 64) }
+
+// appendRanges appends the indexes of the set bits of f to dst, as comma
+// separated ranges, like "0-3,7,12-15".
+func appendRanges[T bitFlagsTypes](dst []byte, f T) []byte {
+	for v := uint64(f); v != 0; {
+		lo := bits.TrailingZeros64(v)
+		n := bits.TrailingZeros64(^(v >> lo))
+		if v != uint64(f) {
+			dst = append(dst, ',')
+		}
+		dst = appendItoa(dst, lo)
+		if n > 1 {
+			dst = append(dst, '-')
+			dst = appendItoa(dst, lo+n-1)
+		}
+		v &^= (1<<n - 1) << lo
+	}
+	return dst
+}
+
+// FormatRanges returns the indexes of the set bits of f as comma separated
+// ranges, in increasing order, like "0-3,7", or "" if no bit is set.
+// It's the inverse of [ParseRanges].
+func (f BitFlags8) FormatRanges() string { return string(appendRanges(nil, f)) }
+
+// FormatRanges returns the indexes of the set bits of f as comma separated
+// ranges, in increasing order, like "0-3,7,12-15", or "" if no bit is set.
+// It's the inverse of [ParseRanges].
+func (f BitFlags16) FormatRanges() string { return string(appendRanges(nil, f)) }
+
+// FormatRanges returns the indexes of the set bits of f as comma separated
+// ranges, in increasing order, like "0-3,7,12-15", or "" if no bit is set.
+// It's the inverse of [ParseRanges].
+func (f BitFlags32) FormatRanges() string { return string(appendRanges(nil, f)) }
+
+// FormatRanges returns the indexes of the set bits of f as comma separated
+// ranges, in increasing order, like "0-3,7,12-15", or "" if no bit is set.
+// It's the inverse of [ParseRanges].
+func (f BitFlags64) FormatRanges() string { return string(appendRanges(nil, f)) }
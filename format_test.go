@@ -0,0 +1,143 @@
+package flagged
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestBitFlags_Format(t *testing.T) {
+	tests := []struct {
+		format string
+		arg    any
+		want   string
+	}{
+		{"%v", BitFlags8(0b101), "00000101"},
+		{"%s", BitFlags8(0b101), "00000101"},
+		{"%q", BitFlags8(0b101), `"00000101"`},
+		{"%10v", BitFlags8(0b101), "  00000101"},
+		{"%-10s|", BitFlags8(0b101), "00000101  |"},
+		{"%b", BitFlags8(0b101), "101"},
+		{"%08b", BitFlags8(0b101), "00000101"},
+		{"%#b", BitFlags8(0b101), "0b101"},
+		{"%o", BitFlags16(8), "10"},
+		{"%#o", BitFlags16(8), "010"},
+		{"%O", BitFlags16(8), "0o10"},
+		{"%d", BitFlags32(255), "255"},
+		{"%5d", BitFlags32(255), "  255"},
+		{"%x", BitFlags64(255), "ff"},
+		{"%#x", BitFlags64(255), "0xff"},
+		{"%#04X", BitFlags64(255), "0X00FF"},
+		{"%v", New[BitFlags16](0b11), "0000000000000011"},
+		{"%#v", BitFlags8(0b101), "flagged.BitFlags8(0b00000101)"},
+		{"%#v", BitFlags16(0b101), "flagged.BitFlags16(0b0000000000000101)"},
+		{"%#v", []BitFlags8{1}, "[]flagged.BitFlags8{flagged.BitFlags8(0b00000001)}"},
+		{"%z", BitFlags8(1), "%!z(uint64=1)"},
+	}
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("%T %s", tt.arg, tt.format), func(t *testing.T) {
+			if got := fmt.Sprintf(tt.format, tt.arg); got != tt.want {
+				t.Errorf("Sprintf(%q) = %q, want = %q", tt.format, got, tt.want)
+			}
+		})
+	}
+}
+
+func helperRunTestAppend[T bitFlags, TP interface {
+	ptrBitFlags[T]
+	AppendString(dst []byte) []byte
+	AppendPrettyString(dst []byte) []byte
+}](t *testing.T) {
+	var (
+		zero   T
+		allset = ^zero
+	)
+	tests := []struct {
+		name    string
+		initial T
+	}{
+		{
+			name:    "zero",
+			initial: zero,
+		},
+		{
+			name:    "allset",
+			initial: allset,
+		},
+		{
+			name:    "partial",
+			initial: zero | 0b0101,
+		},
+	}
+	t.Run(fmt.Sprintf("%T", zero), func(t *testing.T) {
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				var f TP = &tt.initial
+				prefix := []byte("flags=")
+
+				if got, want := string(f.AppendString(prefix)), "flags="+f.String(); got != want {
+					t.Errorf("AppendString() = %v, want = %v", got, want)
+				}
+				if got, want := string(f.AppendPrettyString(prefix)), "flags="+f.PrettyString(); got != want {
+					t.Errorf("AppendPrettyString() = %v, want = %v", got, want)
+				}
+
+				buf := make([]byte, 0, 128)
+				allocs := testing.AllocsPerRun(10, func() {
+					buf = f.AppendString(buf[:0])
+					buf = f.AppendPrettyString(buf[:0])
+				})
+				if allocs != 0 {
+					t.Errorf("Append allocs = %v, want = 0", allocs)
+				}
+			})
+		}
+	})
+}
+
+func TestBitFlags_Append(t *testing.T) {
+	helperRunTestAppend[BitFlags8](t)
+	helperRunTestAppend[BitFlags16](t)
+	helperRunTestAppend[BitFlags32](t)
+	helperRunTestAppend[BitFlags64](t)
+}
+
+func TestBitFlags_Hex(t *testing.T) {
+	tests := []struct {
+		got  string
+		want string
+	}{
+		{BitFlags8(0x5).Hex(), "0x05"},
+		{BitFlags8(0xff).Hex(), "0xff"},
+		{BitFlags16(0xab).Hex(), "0x00ab"},
+		{BitFlags32(0x1234abcd).Hex(), "0x1234abcd"},
+		{BitFlags64(0x5).Hex(), "0x0000000000000005"},
+		{BitFlags64(1 << 63).Hex(), "0x8000000000000000"},
+	}
+	for _, tt := range tests {
+		if tt.got != tt.want {
+			t.Errorf("Hex() = %v, want = %v", tt.got, tt.want)
+		}
+	}
+}
+
+func TestBitFlags_FormatRanges(t *testing.T) {
+	tests := []struct {
+		got  string
+		want string
+	}{
+		{BitFlags8(0).FormatRanges(), ""},
+		{BitFlags8(0b1000_1111).FormatRanges(), "0-3,7"},
+		{BitFlags8(0b1010).FormatRanges(), "1,3"},
+		{BitFlags8(0b0110).FormatRanges(), "1-2"},
+		{BitFlags8(0xff).FormatRanges(), "0-7"},
+		{BitFlags16(0b1111_0000_1000_1111).FormatRanges(), "0-3,7,12-15"},
+		{BitFlags32(1 << 31).FormatRanges(), "31"},
+		{(^BitFlags64(0)).FormatRanges(), "0-63"},
+		{BitFlags64(1<<63 | 1).FormatRanges(), "0,63"},
+	}
+	for _, tt := range tests {
+		if tt.got != tt.want {
+			t.Errorf("FormatRanges() = %q, want = %q", tt.got, tt.want)
+		}
+	}
+}
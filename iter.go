@@ -0,0 +1,60 @@
+package flagged
+
+import (
+	"iter"
+	"math/bits"
+)
+
+// bitsSeq returns an iterator over all the bits of f, from index 0 to
+// size-1, yielding each index along with whether it's set.
+func bitsSeq[T bitFlagsTypes](f T, size int) iter.Seq2[BitIndex, bool] {
+	return func(yield func(BitIndex, bool) bool) {
+		for i := range size {
+			if !yield(i, isUint(f, i)) {
+				return
+			}
+		}
+	}
+}
+
+// setBitsSeq returns an iterator over the indices of the set bits of f,
+// in increasing order, skipping each run of unset bits at once.
+func setBitsSeq[T bitFlagsTypes](f T) iter.Seq[BitIndex] {
+	return func(yield func(BitIndex) bool) {
+		for v := uint64(f); v != 0; v &= v - 1 {
+			if !yield(bits.TrailingZeros64(v)) {
+				return
+			}
+		}
+	}
+}
+
+// forEachSet calls fn with the index of each set bit of f, in increasing
+// order, until fn returns false.
+func forEachSet[T bitFlagsTypes](f T, fn func(idx BitIndex) bool) {
+	for v := uint64(f); v != 0; v &= v - 1 {
+		if !fn(bits.TrailingZeros64(v)) {
+			return
+		}
+	}
+}
+
+// ForEachSet calls fn with the [BitIndex] of each bit set to true, in
+// increasing order, and stops as soon as fn returns false.
+// It's the callback form of [BitFlags8.SetBits], and doesn't allocate.
+func (f BitFlags8) ForEachSet(fn func(idx BitIndex) bool) { forEachSet(f, fn) }
+
+// ForEachSet calls fn with the [BitIndex] of each bit set to true, in
+// increasing order, and stops as soon as fn returns false.
+// It's the callback form of [BitFlags16.SetBits], and doesn't allocate.
+func (f BitFlags16) ForEachSet(fn func(idx BitIndex) bool) { forEachSet(f, fn) }
+
+// ForEachSet calls fn with the [BitIndex] of each bit set to true, in
+// increasing order, and stops as soon as fn returns false.
+// It's the callback form of [BitFlags32.SetBits], and doesn't allocate.
+func (f BitFlags32) ForEachSet(fn func(idx BitIndex) bool) { forEachSet(f, fn) }
+
+// ForEachSet calls fn with the [BitIndex] of each bit set to true, in
+// increasing order, and stops as soon as fn returns false.
+// It's the callback form of [BitFlags64.SetBits], and doesn't allocate.
+func (f BitFlags64) ForEachSet(fn func(idx BitIndex) bool) { forEachSet(f, fn) }
@@ -0,0 +1,208 @@
+package flagged
+
+import (
+	"fmt"
+	"slices"
+	"testing"
+)
+
+func helperRunTestBits[T bitFlags, TP ptrBitFlags[T]](t *testing.T) {
+	var (
+		zero   T
+		allset = ^zero
+		size   = TP(&zero).Size()
+	)
+	type testCase struct {
+		name    string
+		initial T
+	}
+	tests := []testCase{
+		{
+			name:    "zero",
+			initial: zero,
+		},
+		{
+			name:    "allset",
+			initial: allset,
+		},
+		{
+			name:    "partial",
+			initial: zero | T(1)<<1 | T(1)<<(size-1),
+		},
+	}
+	t.Run(fmt.Sprintf("%T", zero), func(t *testing.T) {
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				var f TP = &tt.initial
+
+				want := 0
+				for idx, set := range f.Bits() {
+					if idx != want {
+						t.Fatalf("Bits() yielded index %v, want = %v", idx, want)
+					}
+					if set != f.Is(idx) {
+						t.Errorf("Bits() yielded %v for index %v, want = %v", set, idx, f.Is(idx))
+					}
+					want++
+				}
+				if want != size {
+					t.Errorf("Bits() yielded %v bits, want = %v", want, size)
+				}
+
+				// Stop early, after the first bit.
+				n := 0
+				for range f.Bits() {
+					n++
+					break
+				}
+				if n != 1 {
+					t.Errorf("Bits() yielded %v bits after break, want = %v", n, 1)
+				}
+			})
+		}
+	})
+}
+
+func TestBitFlags_Bits(t *testing.T) {
+	helperRunTestBits[BitFlags8](t)
+	helperRunTestBits[BitFlags16](t)
+	helperRunTestBits[BitFlags32](t)
+	helperRunTestBits[BitFlags64](t)
+}
+
+func helperRunTestSetBits[T bitFlags, TP ptrBitFlags[T]](t *testing.T) {
+	var (
+		zero   T
+		allset = ^zero
+		size   = TP(&zero).Size()
+	)
+	type testCase struct {
+		name    string
+		initial T
+		want    []BitIndex
+	}
+	all := make([]BitIndex, size)
+	for i := range all {
+		all[i] = i
+	}
+	tests := []testCase{
+		{
+			name:    "zero",
+			initial: zero,
+			want:    nil,
+		},
+		{
+			name:    "allset",
+			initial: allset,
+			want:    all,
+		},
+		{
+			name:    "partial",
+			initial: zero | T(1)<<1 | T(1)<<(size-1),
+			want:    []BitIndex{1, size - 1},
+		},
+	}
+	t.Run(fmt.Sprintf("%T", zero), func(t *testing.T) {
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				var f TP = &tt.initial
+
+				var got []BitIndex
+				for idx := range f.SetBits() {
+					got = append(got, idx)
+				}
+				if !slices.Equal(got, tt.want) {
+					t.Errorf("SetBits() yielded %v, want = %v", got, tt.want)
+				}
+
+				// Stop early, after the first set bit.
+				n := 0
+				for range f.SetBits() {
+					n++
+					break
+				}
+				if want := min(len(tt.want), 1); n != want {
+					t.Errorf("SetBits() yielded %v bits after break, want = %v", n, want)
+				}
+			})
+		}
+	})
+}
+
+func TestBitFlags_SetBits(t *testing.T) {
+	helperRunTestSetBits[BitFlags8](t)
+	helperRunTestSetBits[BitFlags16](t)
+	helperRunTestSetBits[BitFlags32](t)
+	helperRunTestSetBits[BitFlags64](t)
+}
+
+func helperRunTestForEachSet[T bitFlags, TP interface {
+	ptrBitFlags[T]
+	ForEachSet(fn func(idx BitIndex) bool)
+}](t *testing.T) {
+	var (
+		zero T
+		size = TP(&zero).Size()
+	)
+	type testCase struct {
+		name    string
+		initial T
+		stop    int
+		want    []BitIndex
+	}
+	tests := []testCase{
+		{
+			name:    "zero",
+			initial: zero,
+			want:    nil,
+		},
+		{
+			name:    "partial",
+			initial: zero | T(1)<<1 | T(1)<<3 | T(1)<<(size-1),
+			want:    []BitIndex{1, 3, size - 1},
+		},
+		{
+			name:    "partial stop",
+			initial: zero | T(1)<<1 | T(1)<<3 | T(1)<<(size-1),
+			stop:    2,
+			want:    []BitIndex{1, 3},
+		},
+	}
+	t.Run(fmt.Sprintf("%T", zero), func(t *testing.T) {
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				var f TP = &tt.initial
+
+				var got []BitIndex
+				f.ForEachSet(func(idx BitIndex) bool {
+					got = append(got, idx)
+					return len(got) != tt.stop
+				})
+				if !slices.Equal(got, tt.want) {
+					t.Errorf("ForEachSet() called fn with %v, want = %v", got, tt.want)
+				}
+			})
+		}
+	})
+}
+
+func TestBitFlags_ForEachSet(t *testing.T) {
+	helperRunTestForEachSet[BitFlags8](t)
+	helperRunTestForEachSet[BitFlags16](t)
+	helperRunTestForEachSet[BitFlags32](t)
+	helperRunTestForEachSet[BitFlags64](t)
+}
+
+func TestBitFlags_ForEachSet_allocs(t *testing.T) {
+	f := BitFlags64(0b1010_0110)
+	n := 0
+	allocs := testing.AllocsPerRun(10, func() {
+		f.ForEachSet(func(idx BitIndex) bool {
+			n += idx
+			return true
+		})
+	})
+	if allocs != 0 {
+		t.Errorf("ForEachSet() allocs = %v, want = 0", allocs)
+	}
+}
@@ -0,0 +1,325 @@
+package flagged
+
+import (
+	"errors"
+	"math/bits"
+)
+
+// ErrUnknownName is returned when a string has a name that isn't in the
+// name table of a [NamedBitFlags].
+var ErrUnknownName = errors.New("unknown flag name")
+
+// NamedBitFlags pairs a value of one of the BitFlags types with a table of
+// the names of its bits, so the bits can be accessed, and the value can be
+// formatted and parsed, by name, like:
+//
+//	f := flagged.NewNamed[flagged.BitFlags8]("Read", "Write", "Exec")
+//	f.SetByName("Read")
+//	f.SetByName("Exec")
+//	fmt.Println(f) // Read|Exec
+//
+// Copies of a [NamedBitFlags] share the same name table, which can't be
+// changed after it's created.
+// The zero value has no names, so it can only be used by index, through
+// its Flags field.
+type NamedBitFlags[T BitFlags8 | BitFlags16 | BitFlags32 | BitFlags64] struct {
+	// Flags is the value, which can be used directly, by index.
+	Flags T
+
+	names []string
+}
+
+// NewNamed returns a [NamedBitFlags] with no bits set, where the bit at
+// each index i is named names[i].
+// An empty name leaves its bit unnamed.
+// It panics if there are more names than the size of T, or if a name is
+// repeated, or has a '|', which separates the names in its String, or is
+// like "bit5", which names the unnamed bits.
+func NewNamed[T BitFlags8 | BitFlags16 | BitFlags32 | BitFlags64](names ...string) NamedBitFlags[T] {
+	validateNames(sizeOf[T](), names)
+	return NamedBitFlags[T]{names: append([]string(nil), names...)}
+}
+
+// validateNames panics if there are more names than size, or if a name is
+// repeated, or has a '|', or is like "bit5".
+func validateNames(size int, names []string) {
+	if len(names) > size {
+		panic("flagged: " + itoa(len(names)) + " names for " + itoa(size) + " bits")
+	}
+	for i, name := range names {
+		if name == "" {
+			continue
+		}
+		if indexByte(name, '|') >= 0 || isBitName(name) {
+			panic("flagged: invalid name " + quote(name))
+		}
+		for _, prev := range names[:i] {
+			if name == prev {
+				panic("flagged: repeated name " + quote(name))
+			}
+		}
+	}
+}
+
+// isBitName returns whether name is "bit" followed by decimal digits, like
+// the names of the unnamed bits, so it can't be told apart from them.
+func isBitName(name string) bool {
+	if len(name) < len("bit0") || name[:3] != "bit" {
+		return false
+	}
+	_, ok := atoi(name[3:])
+	return ok
+}
+
+// Name returns the name of the bit at index idx, or "" if it's unnamed.
+// It panics if idx is out of the allowed range [0, Size-1].
+func (f NamedBitFlags[T]) Name(idx BitIndex) string {
+	validateBitIndex(sizeOf[T](), idx)
+	if idx < len(f.names) {
+		return f.names[idx]
+	}
+	return ""
+}
+
+// IndexOf returns the index of the bit named name, and whether it exists.
+func (f NamedBitFlags[T]) IndexOf(name string) (idx BitIndex, ok bool) {
+	if name == "" {
+		return 0, false
+	}
+	for i, n := range f.names {
+		if n == name {
+			return i, true
+		}
+	}
+	return 0, false
+}
+
+// mustIndexOf returns the index of the bit named name, or panics.
+func (f NamedBitFlags[T]) mustIndexOf(name string) BitIndex {
+	idx, ok := f.IndexOf(name)
+	if !ok {
+		panic("flagged: unknown flag name " + quote(name))
+	}
+	return idx
+}
+
+// IsByName returns whether the bit named name is set.
+// It panics if there's no bit named name.
+func (f NamedBitFlags[T]) IsByName(name string) bool {
+	return isUint(f.Flags, f.mustIndexOf(name))
+}
+
+// SetByName sets the bit named name to true.
+// It panics if there's no bit named name.
+func (f *NamedBitFlags[T]) SetByName(name string) {
+	set(&f.Flags, sizeOf[T](), f.mustIndexOf(name), true)
+}
+
+// ResetByName sets the bit named name to false.
+// It panics if there's no bit named name.
+func (f *NamedBitFlags[T]) ResetByName(name string) {
+	set(&f.Flags, sizeOf[T](), f.mustIndexOf(name), false)
+}
+
+// SetByNameTo sets the bit named name to new.
+// It panics if there's no bit named name.
+func (f *NamedBitFlags[T]) SetByNameTo(name string, new bool) {
+	set(&f.Flags, sizeOf[T](), f.mustIndexOf(name), new)
+}
+
+// ToggleByName toggles the bit named name.
+// It panics if there's no bit named name.
+func (f *NamedBitFlags[T]) ToggleByName(name string) {
+	toggle(&f.Flags, sizeOf[T](), f.mustIndexOf(name))
+}
+
+// NamesString returns the names of the set bits, in increasing order of
+// index, separated by '|', like "Read|Exec", where each unnamed set bit is
+// named by its index, like "bit5".
+// It returns "" if no bit is set.
+func (f NamedBitFlags[T]) NamesString() string {
+	return string(appendNames(nil, uint64(f.Flags), f.names))
+}
+
+// String returns the same representation as NamesString, like "Read|Exec".
+func (f NamedBitFlags[T]) String() string {
+	return f.NamesString()
+}
+
+// appendNames appends the names of the set bits of v to dst, separated by
+// '|', where each unnamed set bit is named by its index, like "bit5".
+func appendNames(dst []byte, v uint64, names []string) []byte {
+	for first := true; v != 0; v &= v - 1 {
+		if !first {
+			dst = append(dst, '|')
+		}
+		first = false
+		idx := bits.TrailingZeros64(v)
+		if idx < len(names) && names[idx] != "" {
+			dst = append(dst, names[idx]...)
+		} else {
+			dst = appendItoa(append(dst, "bit"...), idx)
+		}
+	}
+	return dst
+}
+
+// Parse sets Flags to the value represented by s, in the format returned
+// by NamesString, like "Read|Exec".
+// The names may be in any order, and the empty string resets all bits.
+// The returned error is a *[ParseError], wrapping [ErrUnknownName] if s
+// has a name that's not in the name table, and Flags isn't changed.
+func (f *NamedBitFlags[T]) Parse(s string) error {
+	var flags T
+	for rest := s; rest != ""; {
+		name := rest
+		if i := indexByte(rest, '|'); i >= 0 {
+			name, rest = rest[:i], rest[i+1:]
+			if rest == "" {
+				return &ParseError{"NamedBitFlags.Parse", s, ErrUnknownName}
+			}
+		} else {
+			rest = ""
+		}
+		idx, ok := f.indexOfName(name)
+		if !ok {
+			return &ParseError{"NamedBitFlags.Parse", s, ErrUnknownName}
+		}
+		flags |= 1 << idx
+	}
+	f.Flags = flags
+	return nil
+}
+
+// indexOfName is like IndexOf, but it also accepts the names of the
+// unnamed bits used by NamesString, like "bit5".
+func (f NamedBitFlags[T]) indexOfName(name string) (idx BitIndex, ok bool) {
+	if idx, ok := f.IndexOf(name); ok {
+		return idx, true
+	}
+	if !isBitName(name) {
+		return 0, false
+	}
+	n, _ := atoi(name[3:])
+	if n >= sizeOf[T]() || f.Name(n) != "" || name[3:] != itoa(n) {
+		return 0, false
+	}
+	return n, true
+}
+
+// Dump returns a multi-line representation of Flags, like the Dump method
+// of the BitFlags types, with the name of each named bit, like:
+//
+//	bit  0: 1 Read
+//	bit  1: 0 Write
+//	bit  2: 1 Exec
+//	...
+func (f NamedBitFlags[T]) Dump() string {
+	return dump(f.Flags, sizeOf[T](), f.names)
+}
+
+// DiffString returns the changes from old to Flags, like [DiffString], but
+// with the names of the named bits, like "+Write -Exec".
+func (f NamedBitFlags[T]) DiffString(old T) string {
+	return diffString(old, f.Flags, f.names)
+}
+
+// MarshalJSON implements [encoding/json.Marshaler], encoding f as a JSON object,
+// with a bool member for each named bit, in increasing order of index,
+// like {"Read":true,"Write":false,"Exec":true}.
+// Each unnamed set bit is included too, named by its index, like "bit5".
+func (f NamedBitFlags[T]) MarshalJSON() ([]byte, error) {
+	buf := []byte{'{'}
+	for i := range sizeOf[T]() {
+		set := isUint(f.Flags, i)
+		name := f.Name(i)
+		if name == "" {
+			if !set {
+				continue
+			}
+			name = "bit" + itoa(i)
+		}
+		if len(buf) > 1 {
+			buf = append(buf, ',')
+		}
+		buf = append(appendJSONString(buf, name), ':')
+		if set {
+			buf = append(buf, "true"...)
+		} else {
+			buf = append(buf, "false"...)
+		}
+	}
+	return append(buf, '}'), nil
+}
+
+// UnmarshalJSON implements [encoding/json.Unmarshaler], decoding a JSON object in
+// the format returned by MarshalJSON into Flags, by the names of its bits,
+// so the order of the members doesn't matter, and the missing ones are
+// reset.
+// f must have been created by [NewNamed] with the names of the bits.
+// If data isn't such an object, the returned error is a *[ParseError]
+// wrapping [ErrSyntax], or [ErrUnknownName] if the object has a name
+// that's not in the name table, and Flags isn't changed.
+// A member repeated in the object takes its last value.
+func (f *NamedBitFlags[T]) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		return nil
+	}
+	flags, name, err := f.parseJSON(data)
+	if err != nil {
+		return &ParseError{"NamedBitFlags.UnmarshalJSON", name, err}
+	}
+	f.Flags = flags
+	return nil
+}
+
+// parseJSON returns the value of the JSON object in data, in the format
+// returned by MarshalJSON, or the error, with the input that caused it,
+// which is the unknown name for [ErrUnknownName].
+func (f NamedBitFlags[T]) parseJSON(data []byte) (flags T, input string, err error) {
+	rest := trimJSONSpace(data)
+	if len(rest) == 0 || rest[0] != '{' {
+		return 0, string(data), ErrSyntax
+	}
+	rest = trimJSONSpace(rest[1:])
+	for first := true; len(rest) == 0 || rest[0] != '}'; first = false {
+		if !first {
+			if len(rest) == 0 || rest[0] != ',' {
+				return 0, string(data), ErrSyntax
+			}
+			rest = trimJSONSpace(rest[1:])
+		}
+		name, after, ok := cutJSONString(rest)
+		if !ok {
+			return 0, string(data), ErrSyntax
+		}
+		rest = trimJSONSpace(after)
+		if len(rest) == 0 || rest[0] != ':' {
+			return 0, string(data), ErrSyntax
+		}
+		rest = trimJSONSpace(rest[1:])
+		var set bool
+		switch {
+		case len(rest) >= 4 && string(rest[:4]) == "true":
+			set, rest = true, rest[4:]
+		case len(rest) >= 5 && string(rest[:5]) == "false":
+			rest = rest[5:]
+		default:
+			return 0, string(data), ErrSyntax
+		}
+		idx, ok := f.indexOfName(name)
+		if !ok {
+			return 0, name, ErrUnknownName
+		}
+		if set {
+			flags |= 1 << idx
+		} else {
+			flags &^= 1 << idx
+		}
+		rest = trimJSONSpace(rest)
+	}
+	if len(trimJSONSpace(rest[1:])) != 0 {
+		return 0, string(data), ErrSyntax
+	}
+	return flags, "", nil
+}
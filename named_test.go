@@ -0,0 +1,308 @@
+package flagged
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+func TestNamedBitFlags(t *testing.T) {
+	f := NewNamed[BitFlags8]("Read", "Write", "", "Exec")
+
+	f.SetByName("Read")
+	f.SetByName("Exec")
+	f.Flags.Set(5)
+	if got, want := f.Flags, BitFlags8(0b10_1001); got != want {
+		t.Fatalf("Flags = %v, want = %v", got, want)
+	}
+	if !f.IsByName("Read") || f.IsByName("Write") || !f.IsByName("Exec") {
+		t.Errorf("IsByName() = %v, %v, %v, want = true, false, true",
+			f.IsByName("Read"), f.IsByName("Write"), f.IsByName("Exec"))
+	}
+	if got, want := f.NamesString(), "Read|Exec|bit5"; got != want {
+		t.Errorf("NamesString() = %q, want = %q", got, want)
+	}
+	if got, want := f.String(), f.NamesString(); got != want {
+		t.Errorf("String() = %q, want = %q", got, want)
+	}
+
+	f.ResetByName("Read")
+	f.SetByNameTo("Write", true)
+	f.ToggleByName("Exec")
+	if got, want := f.String(), "Write|bit5"; got != want {
+		t.Errorf("String() = %q, want = %q", got, want)
+	}
+
+	if idx, ok := f.IndexOf("Exec"); !ok || idx != 3 {
+		t.Errorf("IndexOf(Exec) = %v, %v, want = 3, true", idx, ok)
+	}
+	if _, ok := f.IndexOf(""); ok {
+		t.Errorf("IndexOf(\"\") = _, true, want = _, false")
+	}
+	if got := f.Name(3); got != "Exec" {
+		t.Errorf("Name(3) = %q, want = Exec", got)
+	}
+	if got := f.Name(7); got != "" {
+		t.Errorf("Name(7) = %q, want = \"\"", got)
+	}
+
+	// Copies share the name table.
+	g := f
+	g.Flags = 0
+	g.SetByName("Exec")
+	if got, want := g.String(), "Exec"; got != want {
+		t.Errorf("copy String() = %q, want = %q", got, want)
+	}
+	if got, want := g.DiffString(f.Flags), "-Write +Exec -bit5"; got != want {
+		t.Errorf("DiffString() = %q, want = %q", got, want)
+	}
+}
+
+func TestNamedBitFlags_Parse(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    BitFlags16
+		wantErr error
+	}{
+		{"", 0, nil},
+		{"Read", 0b1, nil},
+		{"Exec|Read", 0b1001, nil},
+		{"Read|bit2|bit15", 1<<15 | 0b101, nil},
+		{"Read|Read", 0b1, nil},
+		{"read", 0, ErrUnknownName},
+		{"Read|", 0, ErrUnknownName},
+		{"|Read", 0, ErrUnknownName},
+		{"Read||Write", 0, ErrUnknownName},
+		{"bit0", 0, ErrUnknownName},
+		{"bit16", 0, ErrUnknownName},
+		{"bit02", 0, ErrUnknownName},
+		{"bit", 0, ErrUnknownName},
+	}
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			f := NewNamed[BitFlags16]("Read", "Write", "", "Exec")
+			f.Flags = 0b0110_0000
+			err := f.Parse(tt.input)
+			if tt.wantErr == nil {
+				if err != nil || f.Flags != tt.want {
+					t.Errorf("Parse(%q) = %v, %v, want = %v, nil", tt.input, f.Flags, err, tt.want)
+				}
+				g := f
+				if err := g.Parse(f.String()); err != nil || g.Flags != f.Flags {
+					t.Errorf("Parse(String()) = %v, %v, want = %v, nil", g.Flags, err, f.Flags)
+				}
+				return
+			}
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("Parse(%q) error = %v, want = %v", tt.input, err, tt.wantErr)
+			}
+			if f.Flags != 0b0110_0000 {
+				t.Errorf("Parse(%q) changed Flags to %v", tt.input, f.Flags)
+			}
+		})
+	}
+}
+
+func TestNamedBitFlags_NamesString_Parse(t *testing.T) {
+	// The unnamed bits are named like "bit1", next to names starting with
+	// "bit" too, so every value must parse back from its NamesString.
+	f := NewNamed[BitFlags8]("bit", "", "bits", "", "bitten", "bit_1", "")
+	for v := range 1 << 8 {
+		f.Flags = BitFlags8(v)
+		s := f.NamesString()
+		g := f
+		g.Flags = 0
+		if err := g.Parse(s); err != nil || g.Flags != f.Flags {
+			t.Errorf("Parse(%q) = %v, %v, want = %v, nil", s, g.Flags, err, f.Flags)
+		}
+	}
+}
+
+func TestNamedBitFlags_Dump(t *testing.T) {
+	f := NewNamed[BitFlags8]("Read", "Write", "Exec")
+	f.SetByName("Exec")
+	want := "" +
+		"bit  0: 0 Read\n" +
+		"bit  1: 0 Write\n" +
+		"bit  2: 1 Exec\n" +
+		"bit  3: 0\n" +
+		"bit  4: 0\n" +
+		"bit  5: 0\n" +
+		"bit  6: 0\n" +
+		"bit  7: 0\n"
+	if got := f.Dump(); got != want {
+		t.Errorf("Dump() = %q, want = %q", got, want)
+	}
+}
+
+func TestNamedBitFlags_panic(t *testing.T) {
+	tests := []struct {
+		name string
+		fn   func()
+		want string
+	}{
+		{
+			name: "too many names",
+			fn:   func() { NewNamed[BitFlags8]("0", "1", "2", "3", "4", "5", "6", "7", "8") },
+			want: "flagged: 9 names for 8 bits",
+		},
+		{
+			name: "repeated name",
+			fn:   func() { NewNamed[BitFlags8]("Read", "", "Read") },
+			want: `flagged: repeated name "Read"`,
+		},
+		{
+			name: "invalid name",
+			fn:   func() { NewNamed[BitFlags8]("Read|Write") },
+			want: `flagged: invalid name "Read|Write"`,
+		},
+		{
+			name: "bit name",
+			fn:   func() { NewNamed[BitFlags8]("Read", "bit3") },
+			want: `flagged: invalid name "bit3"`,
+		},
+		{
+			name: "padded bit name",
+			fn:   func() { NewNamed[BitFlags8]("bit08") },
+			want: `flagged: invalid name "bit08"`,
+		},
+		{
+			name: "unknown name",
+			fn: func() {
+				f := NewNamed[BitFlags8]("Read")
+				f.SetByName("Write")
+			},
+			want: `flagged: unknown flag name "Write"`,
+		},
+		{
+			name: "zero value",
+			fn:   func() { NamedBitFlags[BitFlags8]{}.IsByName("Read") },
+			want: `flagged: unknown flag name "Read"`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer func() {
+				if got := recover(); got != tt.want {
+					t.Errorf("panic = %v, want = %v", got, tt.want)
+				}
+			}()
+			tt.fn()
+		})
+	}
+}
+
+func TestNamedBitFlags_JSON(t *testing.T) {
+	f := NewNamed[BitFlags8]("Read", "Write", "", "Exec")
+	f.Flags = 0b10_1001
+
+	b, err := json.Marshal(f)
+	if err != nil {
+		t.Fatalf("Marshal() error = %v", err)
+	}
+	if got, want := string(b), `{"Read":true,"Write":false,"Exec":true,"bit5":true}`; got != want {
+		t.Errorf("Marshal() = %s, want = %s", got, want)
+	}
+
+	g := NewNamed[BitFlags8]("Read", "Write", "", "Exec")
+	if err := json.Unmarshal(b, &g); err != nil || g.Flags != f.Flags {
+		t.Errorf("Unmarshal(Marshal()) = %v, %v, want = %v, nil", g.Flags, err, f.Flags)
+	}
+
+	// The members may be reordered, or missing.
+	if err := json.Unmarshal([]byte(`{"Exec":true,"Read":false}`), &g); err != nil || g.Flags != 0b1000 {
+		t.Errorf("Unmarshal(reordered) = %v, %v, want = %v, nil", g.Flags, err, BitFlags8(0b1000))
+	}
+
+	// Whitespace and escapes are decoded, and a repeated member takes its
+	// last value.
+	input := " {\n\t\"\\u0045xec\" : true , \"Read\":true, \"Read\":false } "
+	if err := json.Unmarshal([]byte(input), &g); err != nil || g.Flags != 0b1000 {
+		t.Errorf("Unmarshal(%s) = %v, %v, want = %v, nil", input, g.Flags, err, BitFlags8(0b1000))
+	}
+
+	// null doesn't change the value.
+	if err := json.Unmarshal([]byte(`null`), &g); err != nil || g.Flags != 0b1000 {
+		t.Errorf("Unmarshal(null) = %v, %v, want = %v, nil", g.Flags, err, BitFlags8(0b1000))
+	}
+
+	// Inside other values.
+	type doc struct {
+		Perms NamedBitFlags[BitFlags8] `json:"perms"`
+	}
+	d := doc{Perms: NewNamed[BitFlags8]("Read", "Write")}
+	if err := json.Unmarshal([]byte(`{"perms":{"Write":true}}`), &d); err != nil || d.Perms.Flags != 0b10 {
+		t.Errorf("Unmarshal(doc) = %v, %v, want = %v, nil", d.Perms.Flags, err, BitFlags8(0b10))
+	}
+	if b, err := json.Marshal(d); err != nil || string(b) != `{"perms":{"Read":false,"Write":true}}` {
+		t.Errorf("Marshal(doc) = %s, %v", b, err)
+	}
+}
+
+func TestNamedBitFlags_JSON_escapes(t *testing.T) {
+	names := []string{`say "hi"`, `C:\dir`, "tab\t", "héllo", "\x01", "<&>"}
+	f := NewNamed[BitFlags8](names...)
+	f.Flags = 0b10_1010
+
+	b, err := f.MarshalJSON()
+	if err != nil {
+		t.Fatalf("MarshalJSON() error = %v", err)
+	}
+	var m map[string]bool
+	if err := json.Unmarshal(b, &m); err != nil {
+		t.Fatalf("json.Unmarshal(%s) error = %v", b, err)
+	}
+	for i, name := range names {
+		if set := m[name]; set != f.Flags.Is(i) {
+			t.Errorf("json.Unmarshal(%s)[%q] = %v, want = %v", b, name, set, f.Flags.Is(i))
+		}
+	}
+
+	// Decoding what encoding/json encodes, with its own escapes.
+	b, err = json.Marshal(m)
+	if err != nil {
+		t.Fatalf("json.Marshal() error = %v", err)
+	}
+	g := NewNamed[BitFlags8](names...)
+	if err := g.UnmarshalJSON(b); err != nil || g.Flags != f.Flags {
+		t.Errorf("UnmarshalJSON(%s) = %v, %v, want = %v, nil", b, g.Flags, err, f.Flags)
+	}
+}
+
+func TestNamedBitFlags_UnmarshalJSON_errors(t *testing.T) {
+	tests := []struct {
+		input   string
+		wantErr error
+	}{
+		{`{"Exec":true}`, ErrUnknownName},
+		{`{"bit0":true}`, ErrUnknownName},
+		{`{"Read":1}`, ErrSyntax},
+		{`{"Read":null}`, ErrSyntax},
+		{`{"Read":tru}`, ErrSyntax},
+		{`{"Read":true,}`, ErrSyntax},
+		{`{"Read":true "Write":true}`, ErrSyntax},
+		{`{"Read" true}`, ErrSyntax},
+		{`{Read:true}`, ErrSyntax},
+		{`{"Read:true}`, ErrSyntax},
+		{`{"Read":true}}`, ErrSyntax},
+		{`["Read"]`, ErrSyntax},
+		{`{`, ErrSyntax},
+		{``, ErrSyntax},
+	}
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			f := NewNamed[BitFlags8]("Read", "Write")
+			f.Flags = 0b10
+			err := f.UnmarshalJSON([]byte(tt.input))
+			if err == nil {
+				t.Fatalf("UnmarshalJSON(%s) error = nil", tt.input)
+			}
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("UnmarshalJSON(%s) error = %v, want = %v", tt.input, err, tt.wantErr)
+			}
+			if f.Flags != 0b10 {
+				t.Errorf("UnmarshalJSON(%s) changed Flags to %v", tt.input, f.Flags)
+			}
+		})
+	}
+}
@@ -0,0 +1,198 @@
+package flagged
+
+import "errors"
+
+var (
+	// ErrSyntax is returned when a string doesn't have the format expected
+	// by the parse function.
+	ErrSyntax = errors.New("invalid syntax")
+
+	// ErrLength is returned when a string has more bits than the size of
+	// the BitFlags type it's parsed to, or no bits at all.
+	ErrLength = errors.New("invalid length")
+
+	// ErrRange is returned when a string has a bit index out of the range
+	// of the BitFlags type it's parsed to.
+	ErrRange = errors.New("index out of range")
+)
+
+// ParseError records a failed parse of a string to one of the BitFlags
+// types.
+type ParseError struct {
+	Func  string // the failing function, like "Parse".
+	Input string // the input.
+	Err   error  // the reason, like ErrSyntax or ErrLength.
+}
+
+func (e *ParseError) Error() string {
+	return "flagged." + e.Func + ": parsing " + quote(e.Input) + ": " + e.Err.Error()
+}
+
+func (e *ParseError) Unwrap() error { return e.Err }
+
+// Parse returns the value of one of the BitFlags types represented by s,
+// in the binary format returned by the String method, like "00000101",
+// with the bit at index 0 last.
+// s may have fewer bits than the size of T, in which case the missing
+// leading bits are reset.
+// The returned error is a *[ParseError], wrapping [ErrSyntax] if s has
+// characters other than '0' and '1', or [ErrLength] if s is empty or has
+// more bits than the size of T.
+func Parse[T BitFlags8 | BitFlags16 | BitFlags32 | BitFlags64](s string) (f T, err error) {
+	return parseBinary[T]("Parse", s)
+}
+
+// parseBinary implements Parse, reporting errors as coming from fn.
+func parseBinary[T BitFlags8 | BitFlags16 | BitFlags32 | BitFlags64](fn, s string) (f T, err error) {
+	if len(s) == 0 || len(s) > sizeOf[T]() {
+		return 0, &ParseError{fn, s, ErrLength}
+	}
+	for i := range len(s) {
+		f <<= 1
+		switch s[i] {
+		case '0':
+		case '1':
+			f |= 1
+		default:
+			return 0, &ParseError{fn, s, ErrSyntax}
+		}
+	}
+	return f, nil
+}
+
+// Parse8 is [Parse] for [BitFlags8].
+func Parse8(s string) (BitFlags8, error) { return Parse[BitFlags8](s) }
+
+// Parse16 is [Parse] for [BitFlags16].
+func Parse16(s string) (BitFlags16, error) { return Parse[BitFlags16](s) }
+
+// Parse32 is [Parse] for [BitFlags32].
+func Parse32(s string) (BitFlags32, error) { return Parse[BitFlags32](s) }
+
+// Parse64 is [Parse] for [BitFlags64].
+func Parse64(s string) (BitFlags64, error) { return Parse[BitFlags64](s) }
+
+// ParsePrettyString returns the value of one of the BitFlags types
+// represented by s, in the format returned by the PrettyString method,
+// like "O|O|O|O|O|I|O|I", with the bit at index 0 last:
+//
+//	f, err := flagged.ParsePrettyString[flagged.BitFlags16]("O|O|O|O|O|O|O|I_O|O|O|O|O|I|O|I")
+//
+// The bits must be separated by either '|' or '_', regardless of the
+// groups of 8 bits, and, like [Parse], s may have fewer bits than the
+// size of T, in which case the missing leading bits are reset.
+// The returned error is a *[ParseError], wrapping [ErrSyntax] or
+// [ErrLength], like [Parse].
+func ParsePrettyString[T BitFlags8 | BitFlags16 | BitFlags32 | BitFlags64](s string) (f T, err error) {
+	// Each bit takes 2 characters, with its separator, except the last.
+	if len(s) == 0 || (len(s)+1)/2 > sizeOf[T]() {
+		return 0, &ParseError{"ParsePrettyString", s, ErrLength}
+	}
+	for i := range len(s) {
+		if i%2 == 1 {
+			if s[i] != '|' && s[i] != '_' {
+				return 0, &ParseError{"ParsePrettyString", s, ErrSyntax}
+			}
+			continue
+		}
+		f <<= 1
+		switch s[i] {
+		case 'O':
+		case 'I':
+			f |= 1
+		default:
+			return 0, &ParseError{"ParsePrettyString", s, ErrSyntax}
+		}
+	}
+	// A trailing separator is missing its bit.
+	if len(s)%2 == 0 {
+		return 0, &ParseError{"ParsePrettyString", s, ErrSyntax}
+	}
+	return f, nil
+}
+
+// ParseHex returns the value of one of the BitFlags types represented by
+// s, in hex, like "0x05", as returned by the Hex method.
+// The "0x" or "0X" prefix is optional, the digits may be in either case,
+// and, like [Parse], s may have fewer digits than the size of T, in which
+// case the missing leading bits are reset.
+// The returned error is a *[ParseError], wrapping [ErrSyntax] or
+// [ErrLength], like [Parse].
+func ParseHex[T BitFlags8 | BitFlags16 | BitFlags32 | BitFlags64](s string) (f T, err error) {
+	digits := s
+	if len(digits) >= 2 && digits[0] == '0' && (digits[1] == 'x' || digits[1] == 'X') {
+		digits = digits[2:]
+	}
+	if len(digits) == 0 || len(digits) > sizeOf[T]()/4 {
+		return 0, &ParseError{"ParseHex", s, ErrLength}
+	}
+	for i := range len(digits) {
+		d, ok := unhex(digits[i])
+		if !ok {
+			return 0, &ParseError{"ParseHex", s, ErrSyntax}
+		}
+		f = f<<4 | T(d)
+	}
+	return f, nil
+}
+
+// ParseRanges returns the value of one of the BitFlags types with the bits
+// at the indexes in s set, where s has comma separated indexes or ranges
+// of indexes, like "0-3,7,12-15", as returned by the FormatRanges method.
+// The ranges include both ends, may be in any order, and may overlap, and
+// the empty string returns a value with no bits set.
+// The returned error is a *[ParseError], wrapping [ErrSyntax] if s isn't
+// in that format, or [ErrRange] if it has an index out of the range of T.
+func ParseRanges[T BitFlags8 | BitFlags16 | BitFlags32 | BitFlags64](s string) (f T, err error) {
+	size := sizeOf[T]()
+	for rest := s; rest != ""; {
+		part := rest
+		if i := indexByte(rest, ','); i >= 0 {
+			part, rest = rest[:i], rest[i+1:]
+			if rest == "" {
+				return 0, &ParseError{"ParseRanges", s, ErrSyntax}
+			}
+		} else {
+			rest = ""
+		}
+
+		lo, hi := part, part
+		if i := indexByte(part, '-'); i >= 0 {
+			lo, hi = part[:i], part[i+1:]
+		}
+		from, ok1 := atoi(lo)
+		to, ok2 := atoi(hi)
+		if !ok1 || !ok2 || from > to {
+			return 0, &ParseError{"ParseRanges", s, ErrSyntax}
+		}
+		if to >= size {
+			return 0, &ParseError{"ParseRanges", s, ErrRange}
+		}
+		f |= rangeMask[T](size, from, to+1)
+	}
+	return f, nil
+}
+
+// unhex returns the value of the hex digit c, in either case, and whether
+// it's a hex digit.
+func unhex(c byte) (d byte, ok bool) {
+	switch {
+	case '0' <= c && c <= '9':
+		return c - '0', true
+	case 'a' <= c && c <= 'f':
+		return c - 'a' + 10, true
+	case 'A' <= c && c <= 'F':
+		return c - 'A' + 10, true
+	}
+	return 0, false
+}
+
+// indexByte returns the index of the first c in s, or -1 if it's missing.
+func indexByte(s string, c byte) int {
+	for i := range len(s) {
+		if s[i] == c {
+			return i
+		}
+	}
+	return -1
+}
@@ -0,0 +1,240 @@
+package flagged
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func helperRunTestParse[T BitFlags8 | BitFlags16 | BitFlags32 | BitFlags64, TP interface {
+	ptrBitFlags[T]
+	Hex() string
+	FormatRanges() string
+}](t *testing.T) {
+	var (
+		zero   T
+		allset = ^zero
+		size   = TP(&zero).Size()
+	)
+	tests := []struct {
+		name    string
+		initial T
+	}{
+		{
+			name:    "zero",
+			initial: zero,
+		},
+		{
+			name:    "allset",
+			initial: allset,
+		},
+		{
+			name:    "partial",
+			initial: zero | 0b0101 | T(1)<<(size-1),
+		},
+	}
+	t.Run(fmt.Sprintf("%T", zero), func(t *testing.T) {
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				s := TP(&tt.initial).String()
+				got, err := Parse[T](s)
+				if err != nil {
+					t.Fatalf("Parse(%q) error = %v", s, err)
+				}
+				if got != tt.initial {
+					t.Errorf("Parse(%q) = %v, want = %v", s, got, tt.initial)
+				}
+
+				s = TP(&tt.initial).PrettyString()
+				got, err = ParsePrettyString[T](s)
+				if err != nil {
+					t.Fatalf("ParsePrettyString(%q) error = %v", s, err)
+				}
+				if got != tt.initial {
+					t.Errorf("ParsePrettyString(%q) = %v, want = %v", s, got, tt.initial)
+				}
+
+				s = TP(&tt.initial).Hex()
+				got, err = ParseHex[T](s)
+				if err != nil {
+					t.Fatalf("ParseHex(%q) error = %v", s, err)
+				}
+				if got != tt.initial {
+					t.Errorf("ParseHex(%q) = %v, want = %v", s, got, tt.initial)
+				}
+
+				s = TP(&tt.initial).FormatRanges()
+				got, err = ParseRanges[T](s)
+				if err != nil {
+					t.Fatalf("ParseRanges(%q) error = %v", s, err)
+				}
+				if got != tt.initial {
+					t.Errorf("ParseRanges(%q) = %v, want = %v", s, got, tt.initial)
+				}
+			})
+		}
+
+		// Shorter strings have the missing leading bits reset.
+		if got, err := Parse[T]("101"); err != nil || got != 0b101 {
+			t.Errorf("Parse(\"101\") = %v, %v, want = %v, nil", got, err, T(0b101))
+		}
+		// Longer strings are rejected, even if the extra bits are reset.
+		if _, err := Parse[T]("0" + TP(&zero).String()); !errors.Is(err, ErrLength) {
+			t.Errorf("Parse(too long) error = %v, want = %v", err, ErrLength)
+		}
+	})
+}
+
+func TestParse(t *testing.T) {
+	helperRunTestParse[BitFlags8](t)
+	helperRunTestParse[BitFlags16](t)
+	helperRunTestParse[BitFlags32](t)
+	helperRunTestParse[BitFlags64](t)
+}
+
+func TestParse_errors(t *testing.T) {
+	tests := []struct {
+		input   string
+		wantErr error
+		wantMsg string
+	}{
+		{"", ErrLength, `flagged.Parse: parsing "": invalid length`},
+		{"000000001", ErrLength, `flagged.Parse: parsing "000000001": invalid length`},
+		{"0000010x", ErrSyntax, `flagged.Parse: parsing "0000010x": invalid syntax`},
+		{" 101", ErrSyntax, `flagged.Parse: parsing " 101": invalid syntax`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := Parse8(tt.input)
+			if got != 0 {
+				t.Errorf("Parse8(%q) = %v, want = 0", tt.input, got)
+			}
+			var perr *ParseError
+			if !errors.As(err, &perr) || !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Parse8(%q) error = %#v, want = %v", tt.input, err, tt.wantErr)
+			}
+			if err.Error() != tt.wantMsg {
+				t.Errorf("Parse8(%q) error = %q, want = %q", tt.input, err.Error(), tt.wantMsg)
+			}
+		})
+	}
+}
+
+func TestParsePrettyString_errors(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    BitFlags8
+		wantErr error
+	}{
+		{"I|O|I", 0b101, nil},
+		{"I_O|I", 0b101, nil},
+		{"I", 0b1, nil},
+		{"", 0, ErrLength},
+		{"O|O|O|O|O|O|O|O|I", 0, ErrLength},
+		{"I|O|", 0, ErrSyntax},
+		{"I||O", 0, ErrSyntax},
+		{"IO", 0, ErrSyntax},
+		{"I|0", 0, ErrSyntax},
+		{"I,O", 0, ErrSyntax},
+	}
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := ParsePrettyString[BitFlags8](tt.input)
+			if got != tt.want {
+				t.Errorf("ParsePrettyString(%q) = %v, want = %v", tt.input, got, tt.want)
+			}
+			if tt.wantErr == nil {
+				if err != nil {
+					t.Errorf("ParsePrettyString(%q) error = %v, want = nil", tt.input, err)
+				}
+				return
+			}
+			var perr *ParseError
+			if !errors.As(err, &perr) || !errors.Is(err, tt.wantErr) || perr.Func != "ParsePrettyString" {
+				t.Errorf("ParsePrettyString(%q) error = %#v, want = %v", tt.input, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestParseHex_errors(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    BitFlags16
+		wantErr error
+	}{
+		{"0x0005", 0x5, nil},
+		{"0XaBcD", 0xabcd, nil},
+		{"abcd", 0xabcd, nil},
+		{"0x5", 0x5, nil},
+		{"0", 0, nil},
+		{"", 0, ErrLength},
+		{"0x", 0, ErrLength},
+		{"0x00005", 0, ErrLength},
+		{"0xg", 0, ErrSyntax},
+		{"x5", 0, ErrSyntax},
+		{"-0x5", 0, ErrSyntax},
+	}
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := ParseHex[BitFlags16](tt.input)
+			if got != tt.want {
+				t.Errorf("ParseHex(%q) = %v, want = %v", tt.input, got, tt.want)
+			}
+			if tt.wantErr == nil {
+				if err != nil {
+					t.Errorf("ParseHex(%q) error = %v, want = nil", tt.input, err)
+				}
+				return
+			}
+			var perr *ParseError
+			if !errors.As(err, &perr) || !errors.Is(err, tt.wantErr) || perr.Func != "ParseHex" {
+				t.Errorf("ParseHex(%q) error = %#v, want = %v", tt.input, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestParseRanges_errors(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    BitFlags16
+		wantErr error
+	}{
+		{"", 0, nil},
+		{"0-3,7,12-15", 0b1111_0000_1000_1111, nil},
+		{"7,0-3", 0b1000_1111, nil},
+		{"0-3,2-5", 0b11_1111, nil},
+		{"4-4", 0b1_0000, nil},
+		{"15", 1 << 15, nil},
+		{"16", 0, ErrRange},
+		{"0-16", 0, ErrRange},
+		{"99999999999999999999", 0, ErrRange},
+		{"3-1", 0, ErrSyntax},
+		{"1,", 0, ErrSyntax},
+		{",1", 0, ErrSyntax},
+		{"1,,2", 0, ErrSyntax},
+		{"-1", 0, ErrSyntax},
+		{"1-2-3", 0, ErrSyntax},
+		{"1, 2", 0, ErrSyntax},
+		{"+1", 0, ErrSyntax},
+	}
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := ParseRanges[BitFlags16](tt.input)
+			if got != tt.want {
+				t.Errorf("ParseRanges(%q) = %v, want = %v", tt.input, got, tt.want)
+			}
+			if tt.wantErr == nil {
+				if err != nil {
+					t.Errorf("ParseRanges(%q) error = %v, want = nil", tt.input, err)
+				}
+				return
+			}
+			var perr *ParseError
+			if !errors.As(err, &perr) || !errors.Is(err, tt.wantErr) || perr.Func != "ParseRanges" {
+				t.Errorf("ParseRanges(%q) error = %#v, want = %v", tt.input, err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,98 @@
+package flagged
+
+// The indexes of the Unix permission bits in a [BitFlags16], which match
+// their positions in a Unix file mode, so 0o755 sets PermUserRead,
+// PermUserWrite, PermUserExec, PermGroupRead, PermGroupExec, PermOtherRead
+// and PermOtherExec.
+const (
+	PermOtherExec BitIndex = iota
+	PermOtherWrite
+	PermOtherRead
+	PermGroupExec
+	PermGroupWrite
+	PermGroupRead
+	PermUserExec
+	PermUserWrite
+	PermUserRead
+)
+
+// permMask is the mask of the 9 Unix permission bits.
+const permMask = 0o777
+
+// PermOctal returns the 9 Unix permission bits of f in octal, like
+// "0o755", ignoring the bits from index 9 up.
+// It's the inverse of [ParsePermOctal].
+func (f BitFlags16) PermOctal() string {
+	return string([]byte{
+		'0', 'o',
+		'0' + byte(f>>6&7),
+		'0' + byte(f>>3&7),
+		'0' + byte(f&7),
+	})
+}
+
+// PermString returns the 9 Unix permission bits of f like "rwxr-xr-x",
+// as printed by ls, ignoring the bits from index 9 up.
+// It's the inverse of [ParsePermString].
+func (f BitFlags16) PermString() string {
+	const syms = "rwxrwxrwx"
+	var b [9]byte
+	for i := range b {
+		if isUint(f, PermUserRead-i) {
+			b[i] = syms[i]
+		} else {
+			b[i] = '-'
+		}
+	}
+	return string(b[:])
+}
+
+// ParsePermOctal returns a [BitFlags16] with the Unix permission bits in s
+// set, where s is in octal, like "0o755", as returned by the PermOctal
+// method.
+// The "0o" or "0O" prefix is optional, so "755" and "0755" are accepted.
+// The returned error is a *[ParseError], wrapping [ErrSyntax] if s isn't
+// in that format, or [ErrRange] if it's more than 0o777.
+func ParsePermOctal(s string) (f BitFlags16, err error) {
+	digits := s
+	if len(digits) >= 2 && digits[0] == '0' && (digits[1] == 'o' || digits[1] == 'O') {
+		digits = digits[2:]
+	}
+	if len(digits) == 0 || len(digits) > 4 {
+		return 0, &ParseError{"ParsePermOctal", s, ErrSyntax}
+	}
+	for i := range len(digits) {
+		c := digits[i]
+		if c < '0' || c > '7' {
+			return 0, &ParseError{"ParsePermOctal", s, ErrSyntax}
+		}
+		f = f<<3 | BitFlags16(c-'0')
+	}
+	if f&^permMask != 0 {
+		return 0, &ParseError{"ParsePermOctal", s, ErrRange}
+	}
+	return f, nil
+}
+
+// ParsePermString returns a [BitFlags16] with the Unix permission bits in
+// s set, where s is like "rwxr-xr-x", as returned by the PermString
+// method.
+// The returned error is a *[ParseError], wrapping [ErrLength] if s doesn't
+// have 9 characters, or [ErrSyntax] if any of them isn't the expected
+// letter or '-'.
+func ParsePermString(s string) (f BitFlags16, err error) {
+	const syms = "rwxrwxrwx"
+	if len(s) != len(syms) {
+		return 0, &ParseError{"ParsePermString", s, ErrLength}
+	}
+	for i := range len(s) {
+		switch s[i] {
+		case syms[i]:
+			f |= 1 << (PermUserRead - i)
+		case '-':
+		default:
+			return 0, &ParseError{"ParsePermString", s, ErrSyntax}
+		}
+	}
+	return f, nil
+}
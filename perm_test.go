@@ -0,0 +1,109 @@
+package flagged
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestBitFlags16_Perm(t *testing.T) {
+	tests := []struct {
+		f     BitFlags16
+		octal string
+		str   string
+	}{
+		{0, "0o000", "---------"},
+		{0o755, "0o755", "rwxr-xr-x"},
+		{0o644, "0o644", "rw-r--r--"},
+		{0o777, "0o777", "rwxrwxrwx"},
+		{0o421, "0o421", "r---w---x"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.octal, func(t *testing.T) {
+			if got := tt.f.PermOctal(); got != tt.octal {
+				t.Errorf("PermOctal() = %v, want = %v", got, tt.octal)
+			}
+			if got := tt.f.PermString(); got != tt.str {
+				t.Errorf("PermString() = %v, want = %v", got, tt.str)
+			}
+			// The bits beyond the permission bits are ignored.
+			high := tt.f | 0xfe00
+			if got := high.PermOctal(); got != tt.octal {
+				t.Errorf("PermOctal() with high bits = %v, want = %v", got, tt.octal)
+			}
+			if got := high.PermString(); got != tt.str {
+				t.Errorf("PermString() with high bits = %v, want = %v", got, tt.str)
+			}
+
+			if got, err := ParsePermOctal(tt.octal); err != nil || got != tt.f {
+				t.Errorf("ParsePermOctal(%q) = %v, %v, want = %v, nil", tt.octal, got, err, tt.f)
+			}
+			if got, err := ParsePermString(tt.str); err != nil || got != tt.f {
+				t.Errorf("ParsePermString(%q) = %v, %v, want = %v, nil", tt.str, got, err, tt.f)
+			}
+		})
+	}
+
+	f := Build16().Set(PermUserRead).Set(PermUserWrite).Set(PermGroupRead).Set(PermOtherRead).Value()
+	if f != 0o644 {
+		t.Errorf("Perm indexes = %o, want = 644", f)
+	}
+}
+
+func TestParsePermOctal_errors(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    BitFlags16
+		wantErr error
+	}{
+		{"755", 0o755, nil},
+		{"0755", 0o755, nil},
+		{"0O755", 0o755, nil},
+		{"7", 0o7, nil},
+		{"", 0, ErrSyntax},
+		{"0o", 0, ErrSyntax},
+		{"0x755", 0, ErrSyntax},
+		{"0o758", 0, ErrSyntax},
+		{"00755", 0, ErrSyntax},
+		{"1755", 0, ErrRange},
+		{"0o1000", 0, ErrRange},
+	}
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := ParsePermOctal(tt.input)
+			if got != tt.want {
+				t.Errorf("ParsePermOctal(%q) = %o, want = %o", tt.input, got, tt.want)
+			}
+			if tt.wantErr == nil {
+				if err != nil {
+					t.Errorf("ParsePermOctal(%q) error = %v, want = nil", tt.input, err)
+				}
+				return
+			}
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("ParsePermOctal(%q) error = %v, want = %v", tt.input, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestParsePermString_errors(t *testing.T) {
+	tests := []struct {
+		input   string
+		wantErr error
+	}{
+		{"", ErrLength},
+		{"rwxr-xr-", ErrLength},
+		{"-rwxr-xr-x", ErrLength},
+		{"rwxr-xr-s", ErrSyntax},
+		{"wrxr-xr-x", ErrSyntax},
+		{"RWXR-XR-X", ErrSyntax},
+	}
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := ParsePermString(tt.input)
+			if got != 0 || !errors.Is(err, tt.wantErr) {
+				t.Errorf("ParsePermString(%q) = %v, %v, want = 0, %v", tt.input, got, err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,79 @@
+package flagged
+
+// PrettyStringOpts configures the representation returned by the
+// PrettyStringWith method of the BitFlags types, like
+// [BitFlags8.PrettyStringWith].
+// Its fields are used as is, so start from [DefaultPrettyStringOpts] to
+// change only some of them.
+type PrettyStringOpts struct {
+	Set   string // the symbol of a bit set to true.
+	Unset string // the symbol of a bit set to false.
+
+	BitSeparator   string // the separator between each 2 bits in a group.
+	GroupSeparator string // the separator between each 2 groups of bits.
+	GroupSize      int    // the number of bits in a group, or 0 for one group.
+
+	// LSBFirst starts with the bit at index 0, instead of the one at index
+	// Size-1 like String.
+	LSBFirst bool
+}
+
+// DefaultPrettyStringOpts are the options matching the PrettyString method
+// of the BitFlags types, like "O|O|O|O|O|I|O|O_O|I|O|O|O|I|O|O".
+var DefaultPrettyStringOpts = PrettyStringOpts{
+	Set:            "I",
+	Unset:          "O",
+	BitSeparator:   "|",
+	GroupSeparator: "_",
+	GroupSize:      8,
+}
+
+func getPrettyStringWith[T bitFlagsTypes](f T, size int, opts PrettyStringOpts) string {
+	symLen := max(len(opts.Set), len(opts.Unset))
+	sepLen := max(len(opts.BitSeparator), len(opts.GroupSeparator))
+	str := make(stringBuilder, 0, size*symLen+(size-1)*sepLen)
+	for i := range size {
+		if i != 0 {
+			if opts.GroupSize > 0 && i%opts.GroupSize == 0 {
+				str.WriteString(opts.GroupSeparator)
+			} else {
+				str.WriteString(opts.BitSeparator)
+			}
+		}
+
+		idx := size - i - 1
+		if opts.LSBFirst {
+			idx = i
+		}
+		if isUint(f, idx) {
+			str.WriteString(opts.Set)
+		} else {
+			str.WriteString(opts.Unset)
+		}
+	}
+	return str.String()
+}
+
+// PrettyStringWith returns a human-readable representation of f, like
+// [BitFlags8.PrettyString], but configured by opts.
+func (f BitFlags8) PrettyStringWith(opts PrettyStringOpts) string {
+	return getPrettyStringWith(f, 8, opts)
+}
+
+// PrettyStringWith returns a human-readable representation of f, like
+// [BitFlags16.PrettyString], but configured by opts.
+func (f BitFlags16) PrettyStringWith(opts PrettyStringOpts) string {
+	return getPrettyStringWith(f, 16, opts)
+}
+
+// PrettyStringWith returns a human-readable representation of f, like
+// [BitFlags32.PrettyString], but configured by opts.
+func (f BitFlags32) PrettyStringWith(opts PrettyStringOpts) string {
+	return getPrettyStringWith(f, 32, opts)
+}
+
+// PrettyStringWith returns a human-readable representation of f, like
+// [BitFlags64.PrettyString], but configured by opts.
+func (f BitFlags64) PrettyStringWith(opts PrettyStringOpts) string {
+	return getPrettyStringWith(f, 64, opts)
+}
@@ -0,0 +1,86 @@
+package flagged
+
+import (
+	"fmt"
+	"testing"
+)
+
+func helperRunTestPrettyStringWith[T bitFlags, TP interface {
+	ptrBitFlags[T]
+	PrettyStringWith(opts PrettyStringOpts) string
+}](t *testing.T) {
+	var (
+		zero   T
+		allset = ^zero
+		size   = TP(&zero).Size()
+	)
+	tests := []struct {
+		name    string
+		initial T
+	}{
+		{
+			name:    "zero",
+			initial: zero,
+		},
+		{
+			name:    "allset",
+			initial: allset,
+		},
+		{
+			name:    "partial",
+			initial: zero | 0b0101 | T(1)<<(size-1),
+		},
+	}
+	t.Run(fmt.Sprintf("%T", zero), func(t *testing.T) {
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				var f TP = &tt.initial
+
+				// The default options match PrettyString.
+				if got, want := f.PrettyStringWith(DefaultPrettyStringOpts), f.PrettyString(); got != want {
+					t.Errorf("PrettyStringWith(DefaultPrettyStringOpts) = %v, want = %v", got, want)
+				}
+
+				// Plain 1s and 0s, without separators, match String.
+				plain := PrettyStringOpts{Set: "1", Unset: "0"}
+				if got, want := f.PrettyStringWith(plain), f.String(); got != want {
+					t.Errorf("PrettyStringWith(plain) = %v, want = %v", got, want)
+				}
+			})
+		}
+	})
+}
+
+func TestBitFlags_PrettyStringWith(t *testing.T) {
+	helperRunTestPrettyStringWith[BitFlags8](t)
+	helperRunTestPrettyStringWith[BitFlags16](t)
+	helperRunTestPrettyStringWith[BitFlags32](t)
+	helperRunTestPrettyStringWith[BitFlags64](t)
+}
+
+func TestBitFlags_PrettyStringWith_opts(t *testing.T) {
+	f := BitFlags16(0b0000_0001_0000_0101)
+	tests := []struct {
+		name string
+		opts PrettyStringOpts
+		want string
+	}{
+		{
+			name: "LSB first, nibbles",
+			opts: PrettyStringOpts{Set: "1", Unset: "0", GroupSeparator: " ", GroupSize: 4, LSBFirst: true},
+			want: "1010 0000 1000 0000",
+		},
+		{
+			name: "symbols and separators",
+			opts: PrettyStringOpts{Set: "x", Unset: ".", BitSeparator: ",", GroupSeparator: " | ", GroupSize: 8},
+			want: ".,.,.,.,.,.,.,x | .,.,.,.,.,x,.,x",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := f.PrettyStringWith(tt.opts); got != tt.want {
+				t.Errorf("PrettyStringWith() = %q, want = %q", got, tt.want)
+			}
+		})
+	}
+}
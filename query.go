@@ -0,0 +1,380 @@
+package flagged
+
+import "math/bits"
+
+func firstSet[T bitFlagsTypes](f T) (idx BitIndex, ok bool) {
+	if f == 0 {
+		return 0, false
+	}
+	return bits.TrailingZeros64(uint64(f)), true
+}
+
+func lastSet[T bitFlagsTypes](f T) (idx BitIndex, ok bool) {
+	if f == 0 {
+		return 0, false
+	}
+	return bits.Len64(uint64(f)) - 1, true
+}
+
+func nextSet[T bitFlagsTypes](f T, size int, after BitIndex) (idx BitIndex, ok bool) {
+	validateBitIndex(size, after)
+	v := uint64(f) >> (after + 1)
+	if v == 0 {
+		return 0, false
+	}
+	return after + 1 + bits.TrailingZeros64(v), true
+}
+
+func prevSet[T bitFlagsTypes](f T, size int, before BitIndex) (idx BitIndex, ok bool) {
+	validateBitIndex(size, before)
+	v := uint64(f) & (1<<before - 1)
+	if v == 0 {
+		return 0, false
+	}
+	return bits.Len64(v) - 1, true
+}
+
+func isRangeSet[T bitFlags](f T, size int, from, to BitIndex) bool {
+	mask := rangeMask[T](size, from, to)
+	return f&mask == mask
+}
+
+func countRange[T bitFlags](f T, size int, from, to BitIndex) int {
+	return bits.OnesCount64(uint64(f & rangeMask[T](size, from, to)))
+}
+
+// ofMask returns the bits of f at the indices idx, or all of them if no
+// indices are passed, like AnyOf and AllOf.
+func ofMask[T bitFlags](f T, size int, idx ...BitIndex) T {
+	if len(idx) == 0 {
+		return f
+	}
+	return f & indicesMask[T](size, idx...)
+}
+
+func noneOf[T bitFlags](f T, size int, idx ...BitIndex) bool {
+	return ofMask(f, size, idx...) == 0
+}
+
+func oneOf[T bitFlags](f T, size int, idx ...BitIndex) bool {
+	v := ofMask(f, size, idx...)
+	return v != 0 && v&(v-1) == 0
+}
+
+func countOf[T bitFlags](f T, size int, idx ...BitIndex) int {
+	return bits.OnesCount64(uint64(ofMask(f, size, idx...)))
+}
+
+func validateNotEmpty(idx []BitIndex) {
+	if len(idx) == 0 {
+		panic(EmptyIndexesError{})
+	}
+}
+
+// FirstSet returns the lowest [BitIndex] of the bits set to true, and
+// false if none of the bits is set.
+func (f BitFlags8) FirstSet() (idx BitIndex, ok bool) { return firstSet(f) }
+
+// LastSet returns the highest [BitIndex] of the bits set to true, and
+// false if none of the bits is set.
+func (f BitFlags8) LastSet() (idx BitIndex, ok bool) { return lastSet(f) }
+
+// IsZero reports whether none of the bits is set, which is !f.AnySet().
+// It's also used by encoding/json to omit the zero value of fields with
+// the omitzero option.
+func (f BitFlags8) IsZero() bool { return f == 0 }
+
+// ExactlyOneSet reports whether exactly one of the bits is set, like when
+// the bits encode mutually exclusive modes.
+func (f BitFlags8) ExactlyOneSet() bool { return f != 0 && f&(f-1) == 0 }
+
+// ExactlyNSet reports whether exactly n of the bits are set.
+func (f BitFlags8) ExactlyNSet(n int) bool { return f.CountSet() == n }
+
+// NoneOf reports whether none of the bits at the indices idx is set,
+// or none of all the bits if no indices are passed.
+// It panics if any of idx is out of the allowed range [0, 7].
+func (f BitFlags8) NoneOf(idx ...BitIndex) bool { return noneOf(f, 8, idx...) }
+
+// OneOf reports whether exactly one of the bits at the indices idx is set,
+// or exactly one of all the bits if no indices are passed.
+// It panics if any of idx is out of the allowed range [0, 7].
+func (f BitFlags8) OneOf(idx ...BitIndex) bool { return oneOf(f, 8, idx...) }
+
+// CountOf returns the number of the bits at the distinct indices idx that
+// are set, or of all the bits if no indices are passed, like CountSet.
+// It panics if any of idx is out of the allowed range [0, 7].
+func (f BitFlags8) CountOf(idx ...BitIndex) int { return countOf(f, 8, idx...) }
+
+// NextSet returns the lowest [BitIndex] of the bits set to true after
+// the index after, and false if none of them is set.
+// It panics if after is out of the allowed range [0, 7].
+func (f BitFlags8) NextSet(after BitIndex) (idx BitIndex, ok bool) { return nextSet(f, 8, after) }
+
+// PrevSet returns the highest [BitIndex] of the bits set to true before
+// the index before, and false if none of them is set.
+// It panics if before is out of the allowed range [0, 7].
+func (f BitFlags8) PrevSet(before BitIndex) (idx BitIndex, ok bool) { return prevSet(f, 8, before) }
+
+// AnyOfMask reports whether any of the bits set in mask is set in f.
+// Unlike AnyOf, it's a single comparison, and it's false for a zero mask.
+func (f BitFlags8) AnyOfMask(mask BitFlags8) bool { return f&mask != 0 }
+
+// AllOfMask reports whether all the bits set in mask are set in f.
+// Unlike AllOf, it's a single comparison, and it's true for a zero mask.
+func (f BitFlags8) AllOfMask(mask BitFlags8) bool { return f&mask == mask }
+
+// AnyOfStrict is like AnyOf, but panics with an [EmptyIndexesError] if no
+// indexes are passed, instead of acting as AnySet, to catch accidentally
+// empty index lists.
+func (f BitFlags8) AnyOfStrict(idx ...BitIndex) bool {
+	validateNotEmpty(idx)
+	return anySet(f, 8, idx...)
+}
+
+// AllOfStrict is like AllOf, but panics if no indexes are passed, instead
+// of acting as AllSet, to catch accidentally empty index lists.
+func (f BitFlags8) AllOfStrict(idx ...BitIndex) bool {
+	validateNotEmpty(idx)
+	return allSet(f, 8, idx...)
+}
+
+// IsRangeSet reports whether all the bits in the range [from, to) are set
+// to true, which is true for an empty range.
+// It panics if the range isn't within [0, 8], or if from > to.
+func (f BitFlags8) IsRangeSet(from, to BitIndex) bool { return isRangeSet(f, 8, from, to) }
+
+// CountRange returns the number of bits set to true in the range [from, to).
+// It panics if the range isn't within [0, 8], or if from > to.
+func (f BitFlags8) CountRange(from, to BitIndex) int { return countRange(f, 8, from, to) }
+
+// FirstSet returns the lowest [BitIndex] of the bits set to true, and
+// false if none of the bits is set.
+func (f BitFlags16) FirstSet() (idx BitIndex, ok bool) { return firstSet(f) }
+
+// LastSet returns the highest [BitIndex] of the bits set to true, and
+// false if none of the bits is set.
+func (f BitFlags16) LastSet() (idx BitIndex, ok bool) { return lastSet(f) }
+
+// IsZero reports whether none of the bits is set, which is !f.AnySet().
+// It's also used by encoding/json to omit the zero value of fields with
+// the omitzero option.
+func (f BitFlags16) IsZero() bool { return f == 0 }
+
+// ExactlyOneSet reports whether exactly one of the bits is set, like when
+// the bits encode mutually exclusive modes.
+func (f BitFlags16) ExactlyOneSet() bool { return f != 0 && f&(f-1) == 0 }
+
+// ExactlyNSet reports whether exactly n of the bits are set.
+func (f BitFlags16) ExactlyNSet(n int) bool { return f.CountSet() == n }
+
+// NoneOf reports whether none of the bits at the indices idx is set,
+// or none of all the bits if no indices are passed.
+// It panics if any of idx is out of the allowed range [0, 15].
+func (f BitFlags16) NoneOf(idx ...BitIndex) bool { return noneOf(f, 16, idx...) }
+
+// OneOf reports whether exactly one of the bits at the indices idx is set,
+// or exactly one of all the bits if no indices are passed.
+// It panics if any of idx is out of the allowed range [0, 15].
+func (f BitFlags16) OneOf(idx ...BitIndex) bool { return oneOf(f, 16, idx...) }
+
+// CountOf returns the number of the bits at the distinct indices idx that
+// are set, or of all the bits if no indices are passed, like CountSet.
+// It panics if any of idx is out of the allowed range [0, 15].
+func (f BitFlags16) CountOf(idx ...BitIndex) int { return countOf(f, 16, idx...) }
+
+// NextSet returns the lowest [BitIndex] of the bits set to true after
+// the index after, and false if none of them is set.
+// It panics if after is out of the allowed range [0, 15].
+func (f BitFlags16) NextSet(after BitIndex) (idx BitIndex, ok bool) { return nextSet(f, 16, after) }
+
+// PrevSet returns the highest [BitIndex] of the bits set to true before
+// the index before, and false if none of them is set.
+// It panics if before is out of the allowed range [0, 15].
+func (f BitFlags16) PrevSet(before BitIndex) (idx BitIndex, ok bool) { return prevSet(f, 16, before) }
+
+// AnyOfMask reports whether any of the bits set in mask is set in f.
+// Unlike AnyOf, it's a single comparison, and it's false for a zero mask.
+func (f BitFlags16) AnyOfMask(mask BitFlags16) bool { return f&mask != 0 }
+
+// AllOfMask reports whether all the bits set in mask are set in f.
+// Unlike AllOf, it's a single comparison, and it's true for a zero mask.
+func (f BitFlags16) AllOfMask(mask BitFlags16) bool { return f&mask == mask }
+
+// AnyOfStrict is like AnyOf, but panics with an [EmptyIndexesError] if no
+// indexes are passed, instead of acting as AnySet, to catch accidentally
+// empty index lists.
+func (f BitFlags16) AnyOfStrict(idx ...BitIndex) bool {
+	validateNotEmpty(idx)
+	return anySet(f, 16, idx...)
+}
+
+// AllOfStrict is like AllOf, but panics if no indexes are passed, instead
+// of acting as AllSet, to catch accidentally empty index lists.
+func (f BitFlags16) AllOfStrict(idx ...BitIndex) bool {
+	validateNotEmpty(idx)
+	return allSet(f, 16, idx...)
+}
+
+// IsRangeSet reports whether all the bits in the range [from, to) are set
+// to true, which is true for an empty range.
+// It panics if the range isn't within [0, 16], or if from > to.
+func (f BitFlags16) IsRangeSet(from, to BitIndex) bool { return isRangeSet(f, 16, from, to) }
+
+// CountRange returns the number of bits set to true in the range [from, to).
+// It panics if the range isn't within [0, 16], or if from > to.
+func (f BitFlags16) CountRange(from, to BitIndex) int { return countRange(f, 16, from, to) }
+
+// FirstSet returns the lowest [BitIndex] of the bits set to true, and
+// false if none of the bits is set.
+func (f BitFlags32) FirstSet() (idx BitIndex, ok bool) { return firstSet(f) }
+
+// LastSet returns the highest [BitIndex] of the bits set to true, and
+// false if none of the bits is set.
+func (f BitFlags32) LastSet() (idx BitIndex, ok bool) { return lastSet(f) }
+
+// IsZero reports whether none of the bits is set, which is !f.AnySet().
+// It's also used by encoding/json to omit the zero value of fields with
+// the omitzero option.
+func (f BitFlags32) IsZero() bool { return f == 0 }
+
+// ExactlyOneSet reports whether exactly one of the bits is set, like when
+// the bits encode mutually exclusive modes.
+func (f BitFlags32) ExactlyOneSet() bool { return f != 0 && f&(f-1) == 0 }
+
+// ExactlyNSet reports whether exactly n of the bits are set.
+func (f BitFlags32) ExactlyNSet(n int) bool { return f.CountSet() == n }
+
+// NoneOf reports whether none of the bits at the indices idx is set,
+// or none of all the bits if no indices are passed.
+// It panics if any of idx is out of the allowed range [0, 31].
+func (f BitFlags32) NoneOf(idx ...BitIndex) bool { return noneOf(f, 32, idx...) }
+
+// OneOf reports whether exactly one of the bits at the indices idx is set,
+// or exactly one of all the bits if no indices are passed.
+// It panics if any of idx is out of the allowed range [0, 31].
+func (f BitFlags32) OneOf(idx ...BitIndex) bool { return oneOf(f, 32, idx...) }
+
+// CountOf returns the number of the bits at the distinct indices idx that
+// are set, or of all the bits if no indices are passed, like CountSet.
+// It panics if any of idx is out of the allowed range [0, 31].
+func (f BitFlags32) CountOf(idx ...BitIndex) int { return countOf(f, 32, idx...) }
+
+// NextSet returns the lowest [BitIndex] of the bits set to true after
+// the index after, and false if none of them is set.
+// It panics if after is out of the allowed range [0, 31].
+func (f BitFlags32) NextSet(after BitIndex) (idx BitIndex, ok bool) { return nextSet(f, 32, after) }
+
+// PrevSet returns the highest [BitIndex] of the bits set to true before
+// the index before, and false if none of them is set.
+// It panics if before is out of the allowed range [0, 31].
+func (f BitFlags32) PrevSet(before BitIndex) (idx BitIndex, ok bool) { return prevSet(f, 32, before) }
+
+// AnyOfMask reports whether any of the bits set in mask is set in f.
+// Unlike AnyOf, it's a single comparison, and it's false for a zero mask.
+func (f BitFlags32) AnyOfMask(mask BitFlags32) bool { return f&mask != 0 }
+
+// AllOfMask reports whether all the bits set in mask are set in f.
+// Unlike AllOf, it's a single comparison, and it's true for a zero mask.
+func (f BitFlags32) AllOfMask(mask BitFlags32) bool { return f&mask == mask }
+
+// AnyOfStrict is like AnyOf, but panics with an [EmptyIndexesError] if no
+// indexes are passed, instead of acting as AnySet, to catch accidentally
+// empty index lists.
+func (f BitFlags32) AnyOfStrict(idx ...BitIndex) bool {
+	validateNotEmpty(idx)
+	return anySet(f, 32, idx...)
+}
+
+// AllOfStrict is like AllOf, but panics if no indexes are passed, instead
+// of acting as AllSet, to catch accidentally empty index lists.
+func (f BitFlags32) AllOfStrict(idx ...BitIndex) bool {
+	validateNotEmpty(idx)
+	return allSet(f, 32, idx...)
+}
+
+// IsRangeSet reports whether all the bits in the range [from, to) are set
+// to true, which is true for an empty range.
+// It panics if the range isn't within [0, 32], or if from > to.
+func (f BitFlags32) IsRangeSet(from, to BitIndex) bool { return isRangeSet(f, 32, from, to) }
+
+// CountRange returns the number of bits set to true in the range [from, to).
+// It panics if the range isn't within [0, 32], or if from > to.
+func (f BitFlags32) CountRange(from, to BitIndex) int { return countRange(f, 32, from, to) }
+
+// FirstSet returns the lowest [BitIndex] of the bits set to true, and
+// false if none of the bits is set.
+func (f BitFlags64) FirstSet() (idx BitIndex, ok bool) { return firstSet(f) }
+
+// LastSet returns the highest [BitIndex] of the bits set to true, and
+// false if none of the bits is set.
+func (f BitFlags64) LastSet() (idx BitIndex, ok bool) { return lastSet(f) }
+
+// IsZero reports whether none of the bits is set, which is !f.AnySet().
+// It's also used by encoding/json to omit the zero value of fields with
+// the omitzero option.
+func (f BitFlags64) IsZero() bool { return f == 0 }
+
+// ExactlyOneSet reports whether exactly one of the bits is set, like when
+// the bits encode mutually exclusive modes.
+func (f BitFlags64) ExactlyOneSet() bool { return f != 0 && f&(f-1) == 0 }
+
+// ExactlyNSet reports whether exactly n of the bits are set.
+func (f BitFlags64) ExactlyNSet(n int) bool { return f.CountSet() == n }
+
+// NoneOf reports whether none of the bits at the indices idx is set,
+// or none of all the bits if no indices are passed.
+// It panics if any of idx is out of the allowed range [0, 63].
+func (f BitFlags64) NoneOf(idx ...BitIndex) bool { return noneOf(f, 64, idx...) }
+
+// OneOf reports whether exactly one of the bits at the indices idx is set,
+// or exactly one of all the bits if no indices are passed.
+// It panics if any of idx is out of the allowed range [0, 63].
+func (f BitFlags64) OneOf(idx ...BitIndex) bool { return oneOf(f, 64, idx...) }
+
+// CountOf returns the number of the bits at the distinct indices idx that
+// are set, or of all the bits if no indices are passed, like CountSet.
+// It panics if any of idx is out of the allowed range [0, 63].
+func (f BitFlags64) CountOf(idx ...BitIndex) int { return countOf(f, 64, idx...) }
+
+// NextSet returns the lowest [BitIndex] of the bits set to true after
+// the index after, and false if none of them is set.
+// It panics if after is out of the allowed range [0, 63].
+func (f BitFlags64) NextSet(after BitIndex) (idx BitIndex, ok bool) { return nextSet(f, 64, after) }
+
+// PrevSet returns the highest [BitIndex] of the bits set to true before
+// the index before, and false if none of them is set.
+// It panics if before is out of the allowed range [0, 63].
+func (f BitFlags64) PrevSet(before BitIndex) (idx BitIndex, ok bool) { return prevSet(f, 64, before) }
+
+// AnyOfMask reports whether any of the bits set in mask is set in f.
+// Unlike AnyOf, it's a single comparison, and it's false for a zero mask.
+func (f BitFlags64) AnyOfMask(mask BitFlags64) bool { return f&mask != 0 }
+
+// AllOfMask reports whether all the bits set in mask are set in f.
+// Unlike AllOf, it's a single comparison, and it's true for a zero mask.
+func (f BitFlags64) AllOfMask(mask BitFlags64) bool { return f&mask == mask }
+
+// AnyOfStrict is like AnyOf, but panics with an [EmptyIndexesError] if no
+// indexes are passed, instead of acting as AnySet, to catch accidentally
+// empty index lists.
+func (f BitFlags64) AnyOfStrict(idx ...BitIndex) bool {
+	validateNotEmpty(idx)
+	return anySet(f, 64, idx...)
+}
+
+// AllOfStrict is like AllOf, but panics if no indexes are passed, instead
+// of acting as AllSet, to catch accidentally empty index lists.
+func (f BitFlags64) AllOfStrict(idx ...BitIndex) bool {
+	validateNotEmpty(idx)
+	return allSet(f, 64, idx...)
+}
+
+// IsRangeSet reports whether all the bits in the range [from, to) are set
+// to true, which is true for an empty range.
+// It panics if the range isn't within [0, 64], or if from > to.
+func (f BitFlags64) IsRangeSet(from, to BitIndex) bool { return isRangeSet(f, 64, from, to) }
+
+// CountRange returns the number of bits set to true in the range [from, to).
+// It panics if the range isn't within [0, 64], or if from > to.
+func (f BitFlags64) CountRange(from, to BitIndex) int { return countRange(f, 64, from, to) }
@@ -0,0 +1,664 @@
+package flagged
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func helperRunTestFirstLastSet[T bitFlags, TP interface {
+	ptrBitFlags[T]
+	FirstSet() (BitIndex, bool)
+	LastSet() (BitIndex, bool)
+}](t *testing.T) {
+	var (
+		zero   T
+		allset = ^zero
+		size   = TP(&zero).Size()
+	)
+	type testCase struct {
+		name      string
+		initial   T
+		wantFirst BitIndex
+		wantLast  BitIndex
+		wantOk    bool
+	}
+	tests := []testCase{
+		{
+			name:    "zero",
+			initial: zero,
+			wantOk:  false,
+		},
+		{
+			name:      "allset",
+			initial:   allset,
+			wantFirst: 0,
+			wantLast:  size - 1,
+			wantOk:    true,
+		},
+		{
+			name:      "one bit",
+			initial:   zero | 1<<3,
+			wantFirst: 3,
+			wantLast:  3,
+			wantOk:    true,
+		},
+		{
+			name:      "partial",
+			initial:   zero | 1<<1 | T(1)<<(size-2),
+			wantFirst: 1,
+			wantLast:  size - 2,
+			wantOk:    true,
+		},
+	}
+	t.Run(fmt.Sprintf("%T", zero), func(t *testing.T) {
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				var f TP = &tt.initial
+				if got, ok := f.FirstSet(); got != tt.wantFirst || ok != tt.wantOk {
+					t.Errorf("FirstSet() = %v, %v, want = %v, %v", got, ok, tt.wantFirst, tt.wantOk)
+				}
+				if got, ok := f.LastSet(); got != tt.wantLast || ok != tt.wantOk {
+					t.Errorf("LastSet() = %v, %v, want = %v, %v", got, ok, tt.wantLast, tt.wantOk)
+				}
+			})
+		}
+	})
+}
+
+func TestBitFlags_FirstLastSet(t *testing.T) {
+	helperRunTestFirstLastSet[BitFlags8](t)
+	helperRunTestFirstLastSet[BitFlags16](t)
+	helperRunTestFirstLastSet[BitFlags32](t)
+	helperRunTestFirstLastSet[BitFlags64](t)
+}
+
+func helperRunTestNextPrevSet[T bitFlags, TP interface {
+	ptrBitFlags[T]
+	NextSet(after BitIndex) (BitIndex, bool)
+	PrevSet(before BitIndex) (BitIndex, bool)
+}](t *testing.T) {
+	var (
+		zero   T
+		allset = ^zero
+		size   = TP(&zero).Size()
+	)
+	type testCase struct {
+		name     string
+		initial  T
+		from     BitIndex
+		wantNext BitIndex
+		nextOk   bool
+		wantPrev BitIndex
+		prevOk   bool
+	}
+	tests := []testCase{
+		{
+			name:    "zero",
+			initial: zero,
+			from:    3,
+		},
+		{
+			name:     "allset middle",
+			initial:  allset,
+			from:     3,
+			wantNext: 4,
+			nextOk:   true,
+			wantPrev: 2,
+			prevOk:   true,
+		},
+		{
+			name:     "allset first",
+			initial:  allset,
+			from:     0,
+			wantNext: 1,
+			nextOk:   true,
+		},
+		{
+			name:     "allset last",
+			initial:  allset,
+			from:     size - 1,
+			wantPrev: size - 2,
+			prevOk:   true,
+		},
+		{
+			name:     "partial",
+			initial:  zero | 1<<1 | 1<<3 | T(1)<<(size-1),
+			from:     3,
+			wantNext: size - 1,
+			nextOk:   true,
+			wantPrev: 1,
+			prevOk:   true,
+		},
+	}
+	t.Run(fmt.Sprintf("%T", zero), func(t *testing.T) {
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				var f TP = &tt.initial
+				if got, ok := f.NextSet(tt.from); got != tt.wantNext || ok != tt.nextOk {
+					t.Errorf("NextSet(%v) = %v, %v, want = %v, %v", tt.from, got, ok, tt.wantNext, tt.nextOk)
+				}
+				if got, ok := f.PrevSet(tt.from); got != tt.wantPrev || ok != tt.prevOk {
+					t.Errorf("PrevSet(%v) = %v, %v, want = %v, %v", tt.from, got, ok, tt.wantPrev, tt.prevOk)
+				}
+			})
+		}
+	})
+}
+
+func TestBitFlags_NextPrevSet(t *testing.T) {
+	helperRunTestNextPrevSet[BitFlags8](t)
+	helperRunTestNextPrevSet[BitFlags16](t)
+	helperRunTestNextPrevSet[BitFlags32](t)
+	helperRunTestNextPrevSet[BitFlags64](t)
+}
+
+func helperRunTestOfMask[T bitFlags, TP interface {
+	ptrBitFlags[T]
+	AnyOfMask(mask T) bool
+	AllOfMask(mask T) bool
+}](t *testing.T) {
+	var (
+		zero   T
+		allset = ^zero
+		size   = TP(&zero).Size()
+	)
+	type testCase struct {
+		name    string
+		initial T
+		mask    T
+		wantAny bool
+		wantAll bool
+	}
+	tests := []testCase{
+		{
+			name:    "zero mask",
+			initial: zero | 0b0101,
+			mask:    zero,
+			wantAny: false,
+			wantAll: true,
+		},
+		{
+			name:    "zero",
+			initial: zero,
+			mask:    zero | 0b0101,
+			wantAny: false,
+			wantAll: false,
+		},
+		{
+			name:    "allset",
+			initial: allset,
+			mask:    zero | 0b0101 | T(1)<<(size-1),
+			wantAny: true,
+			wantAll: true,
+		},
+		{
+			name:    "partial",
+			initial: zero | 0b0101,
+			mask:    zero | 0b0011,
+			wantAny: true,
+			wantAll: false,
+		},
+	}
+	t.Run(fmt.Sprintf("%T", zero), func(t *testing.T) {
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				var f TP = &tt.initial
+				if got := f.AnyOfMask(tt.mask); got != tt.wantAny {
+					t.Errorf("AnyOfMask() = %v, want = %v", got, tt.wantAny)
+				}
+				if got := f.AllOfMask(tt.mask); got != tt.wantAll {
+					t.Errorf("AllOfMask() = %v, want = %v", got, tt.wantAll)
+				}
+			})
+		}
+	})
+}
+
+func TestBitFlags_OfMask(t *testing.T) {
+	helperRunTestOfMask[BitFlags8](t)
+	helperRunTestOfMask[BitFlags16](t)
+	helperRunTestOfMask[BitFlags32](t)
+	helperRunTestOfMask[BitFlags64](t)
+}
+
+func helperRunTestRangeQueries[T bitFlags, TP interface {
+	ptrBitFlags[T]
+	IsRangeSet(from, to BitIndex) bool
+	CountRange(from, to BitIndex) int
+}](t *testing.T) {
+	var (
+		zero   T
+		allset = ^zero
+		size   = TP(&zero).Size()
+	)
+	type testCase struct {
+		name      string
+		initial   T
+		from, to  BitIndex
+		wantSet   bool
+		wantCount int
+	}
+	tests := []testCase{
+		{
+			name:      "empty range",
+			initial:   zero,
+			from:      3,
+			to:        3,
+			wantSet:   true,
+			wantCount: 0,
+		},
+		{
+			name:      "zero",
+			initial:   zero,
+			from:      0,
+			to:        size,
+			wantSet:   false,
+			wantCount: 0,
+		},
+		{
+			name:      "allset",
+			initial:   allset,
+			from:      0,
+			to:        size,
+			wantSet:   true,
+			wantCount: size,
+		},
+		{
+			name:      "partial set range",
+			initial:   zero | 0b0110 | T(1)<<(size-1),
+			from:      1,
+			to:        3,
+			wantSet:   true,
+			wantCount: 2,
+		},
+		{
+			name:      "partial unset range",
+			initial:   zero | 0b0110 | T(1)<<(size-1),
+			from:      2,
+			to:        size,
+			wantSet:   false,
+			wantCount: 2,
+		},
+	}
+	t.Run(fmt.Sprintf("%T", zero), func(t *testing.T) {
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				var f TP = &tt.initial
+				if got := f.IsRangeSet(tt.from, tt.to); got != tt.wantSet {
+					t.Errorf("IsRangeSet(%v, %v) = %v, want = %v", tt.from, tt.to, got, tt.wantSet)
+				}
+				if got := f.CountRange(tt.from, tt.to); got != tt.wantCount {
+					t.Errorf("CountRange(%v, %v) = %v, want = %v", tt.from, tt.to, got, tt.wantCount)
+				}
+			})
+		}
+	})
+}
+
+func TestBitFlags_RangeQueries(t *testing.T) {
+	helperRunTestRangeQueries[BitFlags8](t)
+	helperRunTestRangeQueries[BitFlags16](t)
+	helperRunTestRangeQueries[BitFlags32](t)
+	helperRunTestRangeQueries[BitFlags64](t)
+}
+
+func helperRunTestIsZero[T bitFlags, TP interface {
+	ptrBitFlags[T]
+	IsZero() bool
+}](t *testing.T) {
+	var (
+		zero   T
+		allset = ^zero
+		size   = TP(&zero).Size()
+	)
+	tests := []struct {
+		name    string
+		initial T
+		want    bool
+	}{
+		{
+			name:    "zero",
+			initial: zero,
+			want:    true,
+		},
+		{
+			name:    "allset",
+			initial: allset,
+			want:    false,
+		},
+		{
+			name:    "last bit",
+			initial: zero | T(1)<<(size-1),
+			want:    false,
+		},
+	}
+	t.Run(fmt.Sprintf("%T", zero), func(t *testing.T) {
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				var f TP = &tt.initial
+				if got := f.IsZero(); got != tt.want {
+					t.Errorf("IsZero() = %v, want = %v", got, tt.want)
+				}
+				if got := f.IsZero(); got == f.AnySet() {
+					t.Errorf("IsZero() = %v, AnySet() = %v", got, f.AnySet())
+				}
+			})
+		}
+	})
+}
+
+func TestBitFlags_IsZero(t *testing.T) {
+	helperRunTestIsZero[BitFlags8](t)
+	helperRunTestIsZero[BitFlags16](t)
+	helperRunTestIsZero[BitFlags32](t)
+	helperRunTestIsZero[BitFlags64](t)
+}
+
+func helperRunTestExactlySet[T bitFlags, TP interface {
+	ptrBitFlags[T]
+	ExactlyOneSet() bool
+	ExactlyNSet(n int) bool
+}](t *testing.T) {
+	var (
+		zero   T
+		allset = ^zero
+		size   = TP(&zero).Size()
+	)
+	tests := []struct {
+		name    string
+		initial T
+		n       int
+		wantOne bool
+		wantN   bool
+	}{
+		{
+			name:    "zero",
+			initial: zero,
+			n:       0,
+			wantOne: false,
+			wantN:   true,
+		},
+		{
+			name:    "allset",
+			initial: allset,
+			n:       size,
+			wantOne: false,
+			wantN:   true,
+		},
+		{
+			name:    "one bit",
+			initial: zero | T(1)<<(size-1),
+			n:       1,
+			wantOne: true,
+			wantN:   true,
+		},
+		{
+			name:    "two bits",
+			initial: zero | 0b0001 | T(1)<<(size-1),
+			n:       1,
+			wantOne: false,
+			wantN:   false,
+		},
+	}
+	t.Run(fmt.Sprintf("%T", zero), func(t *testing.T) {
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				var f TP = &tt.initial
+				if got := f.ExactlyOneSet(); got != tt.wantOne {
+					t.Errorf("ExactlyOneSet() = %v, want = %v", got, tt.wantOne)
+				}
+				if got := f.ExactlyNSet(tt.n); got != tt.wantN {
+					t.Errorf("ExactlyNSet(%v) = %v, want = %v", tt.n, got, tt.wantN)
+				}
+			})
+		}
+	})
+}
+
+func TestBitFlags_ExactlySet(t *testing.T) {
+	helperRunTestExactlySet[BitFlags8](t)
+	helperRunTestExactlySet[BitFlags16](t)
+	helperRunTestExactlySet[BitFlags32](t)
+	helperRunTestExactlySet[BitFlags64](t)
+}
+
+func helperRunTestNoneOneOf[T bitFlags, TP interface {
+	ptrBitFlags[T]
+	NoneOf(idx ...BitIndex) bool
+	OneOf(idx ...BitIndex) bool
+}](t *testing.T) {
+	var (
+		zero   T
+		allset = ^zero
+		size   = TP(&zero).Size()
+	)
+	tests := []struct {
+		name     string
+		initial  T
+		idx      []BitIndex
+		wantNone bool
+		wantOne  bool
+	}{
+		{
+			name:     "zero no indices",
+			initial:  zero,
+			idx:      nil,
+			wantNone: true,
+			wantOne:  false,
+		},
+		{
+			name:     "allset no indices",
+			initial:  allset,
+			idx:      nil,
+			wantNone: false,
+			wantOne:  false,
+		},
+		{
+			name:     "one bit no indices",
+			initial:  zero | T(1)<<(size-1),
+			idx:      nil,
+			wantNone: false,
+			wantOne:  true,
+		},
+		{
+			name:     "none of indices",
+			initial:  zero | 0b0101,
+			idx:      []BitIndex{1, 3, size - 1},
+			wantNone: true,
+			wantOne:  false,
+		},
+		{
+			name:     "one of indices",
+			initial:  zero | 0b0101,
+			idx:      []BitIndex{1, 2, size - 1},
+			wantNone: false,
+			wantOne:  true,
+		},
+		{
+			name:     "two of indices",
+			initial:  zero | 0b0101,
+			idx:      []BitIndex{0, 1, 2},
+			wantNone: false,
+			wantOne:  false,
+		},
+	}
+	t.Run(fmt.Sprintf("%T", zero), func(t *testing.T) {
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				var f TP = &tt.initial
+				if got := f.NoneOf(tt.idx...); got != tt.wantNone {
+					t.Errorf("NoneOf(%v) = %v, want = %v", tt.idx, got, tt.wantNone)
+				}
+				if got := f.OneOf(tt.idx...); got != tt.wantOne {
+					t.Errorf("OneOf(%v) = %v, want = %v", tt.idx, got, tt.wantOne)
+				}
+			})
+		}
+	})
+}
+
+func TestBitFlags_NoneOneOf(t *testing.T) {
+	helperRunTestNoneOneOf[BitFlags8](t)
+	helperRunTestNoneOneOf[BitFlags16](t)
+	helperRunTestNoneOneOf[BitFlags32](t)
+	helperRunTestNoneOneOf[BitFlags64](t)
+}
+
+func helperRunTestCountOf[T bitFlags, TP interface {
+	ptrBitFlags[T]
+	CountOf(idx ...BitIndex) int
+}](t *testing.T) {
+	var (
+		zero   T
+		allset = ^zero
+		size   = TP(&zero).Size()
+	)
+	tests := []struct {
+		name    string
+		initial T
+		idx     []BitIndex
+		want    int
+		panics  bool
+	}{
+		{
+			name:    "allset no indices",
+			initial: allset,
+			idx:     nil,
+			want:    size,
+		},
+		{
+			name:    "zero",
+			initial: zero,
+			idx:     []BitIndex{0, 1, size - 1},
+			want:    0,
+		},
+		{
+			name:    "two of three",
+			initial: zero | 0b0101,
+			idx:     []BitIndex{0, 1, 2},
+			want:    2,
+		},
+		{
+			name:    "duplicate indices",
+			initial: zero | 0b0101,
+			idx:     []BitIndex{0, 0, 2},
+			want:    2,
+		},
+		{
+			name:    "out of range",
+			initial: zero | 0b0101,
+			idx:     []BitIndex{0, size},
+			panics:  true,
+		},
+	}
+	t.Run(fmt.Sprintf("%T", zero), func(t *testing.T) {
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				skipUnvalidated(t, tt.panics)
+				defer func() {
+					if r := recover(); (r != nil) != tt.panics {
+						t.Errorf("CountOf() panic = %v, want panic = %v", r, tt.panics)
+					}
+				}()
+				var f TP = &tt.initial
+				if got := f.CountOf(tt.idx...); got != tt.want {
+					t.Errorf("CountOf(%v) = %v, want = %v", tt.idx, got, tt.want)
+				}
+			})
+		}
+	})
+}
+
+func TestBitFlags_CountOf(t *testing.T) {
+	helperRunTestCountOf[BitFlags8](t)
+	helperRunTestCountOf[BitFlags16](t)
+	helperRunTestCountOf[BitFlags32](t)
+	helperRunTestCountOf[BitFlags64](t)
+}
+
+func helperRunTestOfStrict[T bitFlags, TP interface {
+	ptrBitFlags[T]
+	AnyOfStrict(idx ...BitIndex) bool
+	AllOfStrict(idx ...BitIndex) bool
+}](t *testing.T) {
+	var (
+		zero   T
+		allset = ^zero
+		size   = TP(&zero).Size()
+	)
+	tests := []struct {
+		name    string
+		initial T
+		idx     []BitIndex
+		wantAny bool
+		wantAll bool
+		panicV  any
+	}{
+		{
+			name:    "zero no indexes",
+			initial: zero,
+			idx:     nil,
+			panicV:  EmptyIndexesError{},
+		},
+		{
+			name:    "allset no indexes",
+			initial: allset,
+			idx:     []BitIndex{},
+			panicV:  EmptyIndexesError{},
+		},
+		{
+			name:    "partial",
+			initial: zero | 0b0101,
+			idx:     []BitIndex{0, 1},
+			wantAny: true,
+			wantAll: false,
+		},
+		{
+			name:    "out of range",
+			initial: zero | 0b0101,
+			idx:     []BitIndex{0, size},
+			panicV:  IndexError{Idx: size, Size: size},
+		},
+	}
+	t.Run(fmt.Sprintf("%T", zero), func(t *testing.T) {
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				_, isIndexError := tt.panicV.(IndexError)
+				skipUnvalidated(t, isIndexError)
+				var f TP = &tt.initial
+				for _, op := range []struct {
+					name string
+					fn   func(idx ...BitIndex) bool
+					want bool
+				}{
+					{"AnyOfStrict", f.AnyOfStrict, tt.wantAny},
+					{"AllOfStrict", f.AllOfStrict, tt.wantAll},
+				} {
+					func() {
+						defer func() {
+							r := recover()
+							if r != tt.panicV {
+								t.Errorf("%s() panic = %v, want = %v", op.name, r, tt.panicV)
+							}
+							var emptyErr EmptyIndexesError
+							if err, _ := r.(error); tt.panicV == (EmptyIndexesError{}) && !errors.As(err, &emptyErr) {
+								t.Errorf("%s() panic = %v, want an EmptyIndexesError", op.name, r)
+							}
+						}()
+						if got := op.fn(tt.idx...); got != op.want {
+							t.Errorf("%s(%v) = %v, want = %v", op.name, tt.idx, got, op.want)
+						}
+					}()
+				}
+			})
+		}
+	})
+}
+
+func TestBitFlags_OfStrict(t *testing.T) {
+	helperRunTestOfStrict[BitFlags8](t)
+	helperRunTestOfStrict[BitFlags16](t)
+	helperRunTestOfStrict[BitFlags32](t)
+	helperRunTestOfStrict[BitFlags64](t)
+}
@@ -0,0 +1,120 @@
+package flagged
+
+import "sync"
+
+// underlyingBitFlags is satisfied by the BitFlags types, and by the types
+// defined on them, like:
+//
+//	type Permissions flagged.BitFlags16
+type underlyingBitFlags interface {
+	~uint8 | ~uint16 | ~uint32 | ~uint64
+}
+
+// registry maps each type passed to RegisterNames to its names.
+// It's keyed on the zero value of the type, as an interface value, since
+// interface values of different dynamic types never compare equal.
+var registry sync.Map // map[any][]string
+
+// RegisterNames registers names as the names of the bits of the values of
+// type T, where the bit at each index i is named names[i], so they're
+// included in the representations of the values wrapped by [WithNames].
+// It's meant to be called at init time, usually on a type defined on one
+// of the BitFlags types, like:
+//
+//	type Permissions flagged.BitFlags16
+//
+//	func init() {
+//		flagged.RegisterNames[Permissions]("Read", "Write", "Exec")
+//	}
+//
+// An empty name leaves its bit unnamed.
+// It panics if names are already registered for T, if there are more
+// names than the size of T, or if a name is repeated, or has a '|', or is
+// like "bit5".
+func RegisterNames[T underlyingBitFlags](names ...string) {
+	validateNames(sizeOf[T](), names)
+	if _, loaded := registry.LoadOrStore(any(T(0)), append([]string(nil), names...)); loaded {
+		panic("flagged: names already registered for the type")
+	}
+}
+
+// RegisteredNames returns a copy of the names registered for type T, or
+// nil if there are none.
+func RegisteredNames[T underlyingBitFlags]() []string {
+	return append([]string(nil), registeredNames[T]()...)
+}
+
+func registeredNames[T underlyingBitFlags]() []string {
+	names, _ := registry.Load(any(T(0)))
+	n, _ := names.([]string)
+	return n
+}
+
+// Registered wraps a value of type T, so its representations include the
+// names registered for T by [RegisterNames].
+type Registered[T underlyingBitFlags] struct {
+	f T
+}
+
+// WithNames wraps f, so its representations include the names registered
+// for its type by [RegisterNames], like:
+//
+//	fmt.Println(flagged.WithNames(perms)) // 0000000000000101 (Read|Exec)
+func WithNames[T underlyingBitFlags](f T) Registered[T] {
+	return Registered[T]{f: f}
+}
+
+// Value returns the wrapped value.
+func (r Registered[T]) Value() T { return r.f }
+
+// String returns the binary representation of the wrapped value, like the
+// String method of the BitFlags types, followed by the names of its set
+// bits, like "00000101 (Read|Exec)", or just the binary representation if
+// no bit is set.
+func (r Registered[T]) String() string {
+	return r.withNames(getBinaryString(uint64(r.f), sizeOf[T]()))
+}
+
+// PrettyString returns the representation of the wrapped value returned
+// by the PrettyString method of the BitFlags types, followed by the names
+// of its set bits, like "O|O|O|O|O|I|O|I (Read|Exec)", or just the pretty
+// representation if no bit is set.
+func (r Registered[T]) PrettyString() string {
+	return r.withNames(getPrettyString(uint64(r.f), sizeOf[T]()))
+}
+
+// NamesString returns the names of the set bits of the wrapped value, in
+// increasing order of index, separated by '|', like "Read|Exec", where
+// each unnamed set bit is named by its index, like "bit5".
+// It returns "" if no bit is set.
+func (r Registered[T]) NamesString() string {
+	return string(appendNames(nil, uint64(r.f), registeredNames[T]()))
+}
+
+// Dump returns a multi-line representation of the wrapped value, like the
+// Dump method of the BitFlags types, with the name of each named bit, like:
+//
+//	bit  0: 1 Read
+//	bit  1: 0 Write
+//	bit  2: 1 Exec
+//	...
+func (r Registered[T]) Dump() string {
+	return dump(uint64(r.f), sizeOf[T](), registeredNames[T]())
+}
+
+// DiffString returns the changes from old to the wrapped value, in the
+// format returned by [DiffString], like "+Write -Exec".
+func (r Registered[T]) DiffString(old T) string {
+	return DiffString(old, r.f)
+}
+
+// withNames appends the names of the set bits of the wrapped value to s.
+func (r Registered[T]) withNames(s string) string {
+	if r.f == 0 {
+		return s
+	}
+	str := append(stringBuilder(s), " ("...)
+	str = appendNames(str, uint64(r.f), registeredNames[T]())
+	str.WriteByte(')')
+	return str.String()
+}
@@ -0,0 +1,164 @@
+package flagged
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+)
+
+type registryTestPerms BitFlags16
+
+type registryTestUnregistered BitFlags8
+
+func init() {
+	RegisterNames[registryTestPerms]("Read", "Write", "", "Exec")
+}
+
+func TestRegisterNames(t *testing.T) {
+	if got, want := RegisteredNames[registryTestPerms](), []string{"Read", "Write", "", "Exec"}; !slices.Equal(got, want) {
+		t.Errorf("RegisteredNames() = %q, want = %q", got, want)
+	}
+	if got := RegisteredNames[registryTestUnregistered](); got != nil {
+		t.Errorf("RegisteredNames() of unregistered type = %q, want = nil", got)
+	}
+
+	// The returned names are a copy.
+	RegisteredNames[registryTestPerms]()[0] = "Changed"
+	if got := RegisteredNames[registryTestPerms]()[0]; got != "Read" {
+		t.Errorf("RegisteredNames()[0] = %q, want = Read", got)
+	}
+}
+
+func TestRegisterNames_panic(t *testing.T) {
+	tests := []struct {
+		name string
+		fn   func()
+		want string
+	}{
+		{
+			name: "already registered",
+			fn:   func() { RegisterNames[registryTestPerms]("Read") },
+			want: "flagged: names already registered for the type",
+		},
+		{
+			name: "too many names",
+			fn:   func() { RegisterNames[registryTestUnregistered]("0", "1", "2", "3", "4", "5", "6", "7", "8") },
+			want: "flagged: 9 names for 8 bits",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer func() {
+				if got := recover(); got != tt.want {
+					t.Errorf("panic = %v, want = %v", got, tt.want)
+				}
+			}()
+			tt.fn()
+		})
+	}
+}
+
+func TestWithNames(t *testing.T) {
+	tests := []struct {
+		name string
+		r    fmt.Stringer
+		want string
+	}{
+		{
+			name: "registered",
+			r:    WithNames(registryTestPerms(0b1001)),
+			want: "0000000000001001 (Read|Exec)",
+		},
+		{
+			name: "unnamed bit",
+			r:    WithNames(registryTestPerms(0b110)),
+			want: "0000000000000110 (Write|bit2)",
+		},
+		{
+			name: "zero",
+			r:    WithNames(registryTestPerms(0)),
+			want: "0000000000000000",
+		},
+		{
+			name: "unregistered",
+			r:    WithNames(registryTestUnregistered(0b1)),
+			want: "00000001 (bit0)",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.r.String(); got != tt.want {
+				t.Errorf("String() = %q, want = %q", got, tt.want)
+			}
+			if got := fmt.Sprint(tt.r); got != tt.want {
+				t.Errorf("Sprint() = %q, want = %q", got, tt.want)
+			}
+		})
+	}
+
+	if got, want := WithNames(registryTestPerms(0b1001)).PrettyString(), "O|O|O|O|O|O|O|O_O|O|O|O|I|O|O|I (Read|Exec)"; got != want {
+		t.Errorf("PrettyString() = %q, want = %q", got, want)
+	}
+	if got, want := WithNames(BitFlags8(0b1)).Value(), BitFlags8(0b1); got != want {
+		t.Errorf("Value() = %v, want = %v", got, want)
+	}
+}
+
+func TestDiffString_registered(t *testing.T) {
+	tests := []struct {
+		got  string
+		want string
+	}{
+		{DiffString[registryTestPerms](0b1001, 0b1001), ""},
+		{DiffString[registryTestPerms](0b1001, 0b0010), "-Read +Write -Exec"},
+		{DiffString[registryTestPerms](0b0100, 1<<15), "-bit2 +bit15"},
+		{DiffString[registryTestUnregistered](0b01, 0b10), "-bit0 +bit1"},
+	}
+	for _, tt := range tests {
+		if tt.got != tt.want {
+			t.Errorf("DiffString() = %q, want = %q", tt.got, tt.want)
+		}
+	}
+}
+
+func TestRegistered_Dump(t *testing.T) {
+	want := "" +
+		"bit  0: 1 Read\n" +
+		"bit  1: 0 Write\n" +
+		"bit  2: 1\n" +
+		"bit  3: 1 Exec\n"
+	if got := WithNames(registryTestPerms(0b1101)).Dump(); !strings.HasPrefix(got, want) || strings.Count(got, "\n") != 16 {
+		t.Errorf("Dump() = %q, want prefix = %q and 16 lines", got, want)
+	}
+	if got, want := WithNames(registryTestUnregistered(0b1)).Dump(), BitFlags8(0b1).Dump(); got != want {
+		t.Errorf("Dump() of unregistered type = %q, want = %q", got, want)
+	}
+}
+
+func TestRegistered_DiffString(t *testing.T) {
+	if got, want := WithNames(registryTestPerms(0b0010)).DiffString(0b1001), "-Read +Write -Exec"; got != want {
+		t.Errorf("DiffString() = %q, want = %q", got, want)
+	}
+	if got, want := WithNames(registryTestUnregistered(0b10)).DiffString(0b01), "-bit0 +bit1"; got != want {
+		t.Errorf("DiffString() of unregistered type = %q, want = %q", got, want)
+	}
+}
+
+func TestRegistered_NamesString(t *testing.T) {
+	tests := []struct {
+		got  string
+		want string
+	}{
+		{WithNames(registryTestPerms(0)).NamesString(), ""},
+		{WithNames(registryTestPerms(0b1)).NamesString(), "Read"},
+		{WithNames(registryTestPerms(0b1011)).NamesString(), "Read|Write|Exec"},
+		{WithNames(registryTestPerms(1<<15 | 0b100)).NamesString(), "bit2|bit15"},
+		{WithNames(registryTestUnregistered(0b11)).NamesString(), "bit0|bit1"},
+	}
+	for _, tt := range tests {
+		if tt.got != tt.want {
+			t.Errorf("NamesString() = %q, want = %q", tt.got, tt.want)
+		}
+	}
+}
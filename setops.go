@@ -0,0 +1,173 @@
+package flagged
+
+// Union returns the union of f and other, with the bits set in either of them.
+func (f BitFlags8) Union(other BitFlags8) BitFlags8 { return f | other }
+
+// UnionWith sets the bits set in other, like f = f.Union(other).
+func (f *BitFlags8) UnionWith(other BitFlags8) { *f |= other }
+
+// Intersect returns the intersection of f and other, with the bits set in both of them.
+func (f BitFlags8) Intersect(other BitFlags8) BitFlags8 { return f & other }
+
+// IntersectWith resets the bits not set in other, like f = f.Intersect(other).
+func (f *BitFlags8) IntersectWith(other BitFlags8) { *f &= other }
+
+// Difference returns the bits set in f but not in other, like f &^ other.
+func (f BitFlags8) Difference(other BitFlags8) BitFlags8 { return f &^ other }
+
+// DifferenceWith resets the bits set in other, like f = f.Difference(other).
+func (f *BitFlags8) DifferenceWith(other BitFlags8) { *f &^= other }
+
+// Xor returns the symmetric difference of f and other, with the bits set
+// in only one of them.
+func (f BitFlags8) Xor(other BitFlags8) BitFlags8 { return f ^ other }
+
+// XorWith toggles the bits set in other, like f = f.Xor(other).
+func (f *BitFlags8) XorWith(other BitFlags8) { *f ^= other }
+
+// Not returns the complement of f, with all its bits toggled.
+// Combined with Intersect, it keeps all the bits except those of a mask,
+// like f.Intersect(mask.Not()).
+func (f BitFlags8) Not() BitFlags8 { return ^f }
+
+// Equal reports whether f and other have the same bits set.
+func (f BitFlags8) Equal(other BitFlags8) bool { return f == other }
+
+// Intersects reports whether f and other have any bits set in common.
+func (f BitFlags8) Intersects(other BitFlags8) bool { return f&other != 0 }
+
+// IsSubsetOf reports whether all the bits set in f are set in other.
+func (f BitFlags8) IsSubsetOf(other BitFlags8) bool { return f&^other == 0 }
+
+// IsSupersetOf reports whether all the bits set in other are set in f,
+// like checking that granted permissions cover the required ones.
+func (f BitFlags8) IsSupersetOf(other BitFlags8) bool { return other&^f == 0 }
+
+// Union returns the union of f and other, with the bits set in either of them.
+func (f BitFlags16) Union(other BitFlags16) BitFlags16 { return f | other }
+
+// UnionWith sets the bits set in other, like f = f.Union(other).
+func (f *BitFlags16) UnionWith(other BitFlags16) { *f |= other }
+
+// Intersect returns the intersection of f and other, with the bits set in both of them.
+func (f BitFlags16) Intersect(other BitFlags16) BitFlags16 { return f & other }
+
+// IntersectWith resets the bits not set in other, like f = f.Intersect(other).
+func (f *BitFlags16) IntersectWith(other BitFlags16) { *f &= other }
+
+// Difference returns the bits set in f but not in other, like f &^ other.
+func (f BitFlags16) Difference(other BitFlags16) BitFlags16 { return f &^ other }
+
+// DifferenceWith resets the bits set in other, like f = f.Difference(other).
+func (f *BitFlags16) DifferenceWith(other BitFlags16) { *f &^= other }
+
+// Xor returns the symmetric difference of f and other, with the bits set
+// in only one of them.
+func (f BitFlags16) Xor(other BitFlags16) BitFlags16 { return f ^ other }
+
+// XorWith toggles the bits set in other, like f = f.Xor(other).
+func (f *BitFlags16) XorWith(other BitFlags16) { *f ^= other }
+
+// Not returns the complement of f, with all its bits toggled.
+// Combined with Intersect, it keeps all the bits except those of a mask,
+// like f.Intersect(mask.Not()).
+func (f BitFlags16) Not() BitFlags16 { return ^f }
+
+// Equal reports whether f and other have the same bits set.
+func (f BitFlags16) Equal(other BitFlags16) bool { return f == other }
+
+// Intersects reports whether f and other have any bits set in common.
+func (f BitFlags16) Intersects(other BitFlags16) bool { return f&other != 0 }
+
+// IsSubsetOf reports whether all the bits set in f are set in other.
+func (f BitFlags16) IsSubsetOf(other BitFlags16) bool { return f&^other == 0 }
+
+// IsSupersetOf reports whether all the bits set in other are set in f,
+// like checking that granted permissions cover the required ones.
+func (f BitFlags16) IsSupersetOf(other BitFlags16) bool { return other&^f == 0 }
+
+// Union returns the union of f and other, with the bits set in either of them.
+func (f BitFlags32) Union(other BitFlags32) BitFlags32 { return f | other }
+
+// UnionWith sets the bits set in other, like f = f.Union(other).
+func (f *BitFlags32) UnionWith(other BitFlags32) { *f |= other }
+
+// Intersect returns the intersection of f and other, with the bits set in both of them.
+func (f BitFlags32) Intersect(other BitFlags32) BitFlags32 { return f & other }
+
+// IntersectWith resets the bits not set in other, like f = f.Intersect(other).
+func (f *BitFlags32) IntersectWith(other BitFlags32) { *f &= other }
+
+// Difference returns the bits set in f but not in other, like f &^ other.
+func (f BitFlags32) Difference(other BitFlags32) BitFlags32 { return f &^ other }
+
+// DifferenceWith resets the bits set in other, like f = f.Difference(other).
+func (f *BitFlags32) DifferenceWith(other BitFlags32) { *f &^= other }
+
+// Xor returns the symmetric difference of f and other, with the bits set
+// in only one of them.
+func (f BitFlags32) Xor(other BitFlags32) BitFlags32 { return f ^ other }
+
+// XorWith toggles the bits set in other, like f = f.Xor(other).
+func (f *BitFlags32) XorWith(other BitFlags32) { *f ^= other }
+
+// Not returns the complement of f, with all its bits toggled.
+// Combined with Intersect, it keeps all the bits except those of a mask,
+// like f.Intersect(mask.Not()).
+func (f BitFlags32) Not() BitFlags32 { return ^f }
+
+// Equal reports whether f and other have the same bits set.
+func (f BitFlags32) Equal(other BitFlags32) bool { return f == other }
+
+// Intersects reports whether f and other have any bits set in common.
+func (f BitFlags32) Intersects(other BitFlags32) bool { return f&other != 0 }
+
+// IsSubsetOf reports whether all the bits set in f are set in other.
+func (f BitFlags32) IsSubsetOf(other BitFlags32) bool { return f&^other == 0 }
+
+// IsSupersetOf reports whether all the bits set in other are set in f,
+// like checking that granted permissions cover the required ones.
+func (f BitFlags32) IsSupersetOf(other BitFlags32) bool { return other&^f == 0 }
+
+// Union returns the union of f and other, with the bits set in either of them.
+func (f BitFlags64) Union(other BitFlags64) BitFlags64 { return f | other }
+
+// UnionWith sets the bits set in other, like f = f.Union(other).
+func (f *BitFlags64) UnionWith(other BitFlags64) { *f |= other }
+
+// Intersect returns the intersection of f and other, with the bits set in both of them.
+func (f BitFlags64) Intersect(other BitFlags64) BitFlags64 { return f & other }
+
+// IntersectWith resets the bits not set in other, like f = f.Intersect(other).
+func (f *BitFlags64) IntersectWith(other BitFlags64) { *f &= other }
+
+// Difference returns the bits set in f but not in other, like f &^ other.
+func (f BitFlags64) Difference(other BitFlags64) BitFlags64 { return f &^ other }
+
+// DifferenceWith resets the bits set in other, like f = f.Difference(other).
+func (f *BitFlags64) DifferenceWith(other BitFlags64) { *f &^= other }
+
+// Xor returns the symmetric difference of f and other, with the bits set
+// in only one of them.
+func (f BitFlags64) Xor(other BitFlags64) BitFlags64 { return f ^ other }
+
+// XorWith toggles the bits set in other, like f = f.Xor(other).
+func (f *BitFlags64) XorWith(other BitFlags64) { *f ^= other }
+
+// Not returns the complement of f, with all its bits toggled.
+// Combined with Intersect, it keeps all the bits except those of a mask,
+// like f.Intersect(mask.Not()).
+func (f BitFlags64) Not() BitFlags64 { return ^f }
+
+// Equal reports whether f and other have the same bits set.
+func (f BitFlags64) Equal(other BitFlags64) bool { return f == other }
+
+// Intersects reports whether f and other have any bits set in common.
+func (f BitFlags64) Intersects(other BitFlags64) bool { return f&other != 0 }
+
+// IsSubsetOf reports whether all the bits set in f are set in other.
+func (f BitFlags64) IsSubsetOf(other BitFlags64) bool { return f&^other == 0 }
+
+// IsSupersetOf reports whether all the bits set in other are set in f,
+// like checking that granted permissions cover the required ones.
+func (f BitFlags64) IsSupersetOf(other BitFlags64) bool { return other&^f == 0 }
@@ -0,0 +1,254 @@
+package flagged
+
+import (
+	"fmt"
+	"testing"
+)
+
+// setOp is a set operation on one of the BitFlags types, in both its
+// returning and in-place forms.
+type setOp[T bitFlags] struct {
+	name    string
+	fn      func(f, other T) T
+	inPlace func(f *T, other T)
+}
+
+// helperRunTestSetOp runs the tests of op, checking each bit of its result
+// against bitOp applied to the corresponding bits of its operands.
+func helperRunTestSetOp[T bitFlags, TP ptrBitFlags[T]](t *testing.T, op setOp[T], bitOp func(f, other bool) bool) {
+	var (
+		zero   T
+		allset = ^zero
+		size   = TP(&zero).Size()
+	)
+	type testCase struct {
+		name    string
+		initial T
+		other   T
+	}
+	tests := []testCase{
+		{
+			name:    "zero zero",
+			initial: zero,
+			other:   zero,
+		},
+		{
+			name:    "zero allset",
+			initial: zero,
+			other:   allset,
+		},
+		{
+			name:    "allset zero",
+			initial: allset,
+			other:   zero,
+		},
+		{
+			name:    "partial",
+			initial: zero | 0b0101 | T(1)<<(size-1),
+			other:   zero | 0b0011 | T(1)<<(size-2),
+		},
+	}
+	t.Run(fmt.Sprintf("%s/%T", op.name, zero), func(t *testing.T) {
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				var w T
+				for i := range size {
+					TP(&w).SetTo(i, bitOp(TP(&tt.initial).Is(i), TP(&tt.other).Is(i)))
+				}
+				if got := op.fn(tt.initial, tt.other); got != w {
+					t.Errorf("%s() = %v, want = %v", op.name, TP(&got).String(), TP(&w).String())
+				}
+
+				f := tt.initial
+				op.inPlace(&f, tt.other)
+				if f != w {
+					t.Errorf("%sWith() = %v, want = %v", op.name, TP(&f).String(), TP(&w).String())
+				}
+			})
+		}
+	})
+}
+
+func unionBit(f, other bool) bool { return f || other }
+
+func TestBitFlags_Union(t *testing.T) {
+	helperRunTestSetOp(t, setOp[BitFlags8]{"Union", BitFlags8.Union, (*BitFlags8).UnionWith}, unionBit)
+	helperRunTestSetOp(t, setOp[BitFlags16]{"Union", BitFlags16.Union, (*BitFlags16).UnionWith}, unionBit)
+	helperRunTestSetOp(t, setOp[BitFlags32]{"Union", BitFlags32.Union, (*BitFlags32).UnionWith}, unionBit)
+	helperRunTestSetOp(t, setOp[BitFlags64]{"Union", BitFlags64.Union, (*BitFlags64).UnionWith}, unionBit)
+}
+
+func intersectBit(f, other bool) bool { return f && other }
+
+func TestBitFlags_Intersect(t *testing.T) {
+	helperRunTestSetOp(t, setOp[BitFlags8]{"Intersect", BitFlags8.Intersect, (*BitFlags8).IntersectWith}, intersectBit)
+	helperRunTestSetOp(t, setOp[BitFlags16]{"Intersect", BitFlags16.Intersect, (*BitFlags16).IntersectWith}, intersectBit)
+	helperRunTestSetOp(t, setOp[BitFlags32]{"Intersect", BitFlags32.Intersect, (*BitFlags32).IntersectWith}, intersectBit)
+	helperRunTestSetOp(t, setOp[BitFlags64]{"Intersect", BitFlags64.Intersect, (*BitFlags64).IntersectWith}, intersectBit)
+}
+
+func differenceBit(f, other bool) bool { return f && !other }
+
+func TestBitFlags_Difference(t *testing.T) {
+	helperRunTestSetOp(t, setOp[BitFlags8]{"Difference", BitFlags8.Difference, (*BitFlags8).DifferenceWith}, differenceBit)
+	helperRunTestSetOp(t, setOp[BitFlags16]{"Difference", BitFlags16.Difference, (*BitFlags16).DifferenceWith}, differenceBit)
+	helperRunTestSetOp(t, setOp[BitFlags32]{"Difference", BitFlags32.Difference, (*BitFlags32).DifferenceWith}, differenceBit)
+	helperRunTestSetOp(t, setOp[BitFlags64]{"Difference", BitFlags64.Difference, (*BitFlags64).DifferenceWith}, differenceBit)
+}
+
+func xorBit(f, other bool) bool { return f != other }
+
+func TestBitFlags_Xor(t *testing.T) {
+	helperRunTestSetOp(t, setOp[BitFlags8]{"Xor", BitFlags8.Xor, (*BitFlags8).XorWith}, xorBit)
+	helperRunTestSetOp(t, setOp[BitFlags16]{"Xor", BitFlags16.Xor, (*BitFlags16).XorWith}, xorBit)
+	helperRunTestSetOp(t, setOp[BitFlags32]{"Xor", BitFlags32.Xor, (*BitFlags32).XorWith}, xorBit)
+	helperRunTestSetOp(t, setOp[BitFlags64]{"Xor", BitFlags64.Xor, (*BitFlags64).XorWith}, xorBit)
+}
+
+func helperRunTestNot[T bitFlags, TP interface {
+	ptrBitFlags[T]
+	Not() T
+}](t *testing.T) {
+	var (
+		zero   T
+		allset = ^zero
+		size   = TP(&zero).Size()
+	)
+	tests := []struct {
+		name    string
+		initial T
+		want    T
+	}{
+		{
+			name:    "zero",
+			initial: zero,
+			want:    allset,
+		},
+		{
+			name:    "allset",
+			initial: allset,
+			want:    zero,
+		},
+		{
+			name:    "partial",
+			initial: zero | 0b0101 | T(1)<<(size-1),
+			want:    allset &^ (0b0101 | T(1)<<(size-1)),
+		},
+	}
+	t.Run(fmt.Sprintf("%T", zero), func(t *testing.T) {
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				var f TP = &tt.initial
+				if got := f.Not(); got != tt.want {
+					t.Errorf("Not() = %v, want = %v", TP(&got).String(), TP(&tt.want).String())
+				}
+			})
+		}
+	})
+}
+
+func TestBitFlags_Not(t *testing.T) {
+	helperRunTestNot[BitFlags8](t)
+	helperRunTestNot[BitFlags16](t)
+	helperRunTestNot[BitFlags32](t)
+	helperRunTestNot[BitFlags64](t)
+}
+
+func helperRunTestSetRelations[T bitFlags, TP interface {
+	ptrBitFlags[T]
+	Equal(other T) bool
+	Intersects(other T) bool
+	IsSubsetOf(other T) bool
+	IsSupersetOf(other T) bool
+}](t *testing.T) {
+	var (
+		zero   T
+		allset = ^zero
+		size   = TP(&zero).Size()
+	)
+	type testCase struct {
+		name           string
+		initial        T
+		other          T
+		wantEqual      bool
+		wantIntersects bool
+		wantSubset     bool
+		wantSuperset   bool
+	}
+	tests := []testCase{
+		{
+			name:         "zero zero",
+			initial:      zero,
+			other:        zero,
+			wantEqual:    true,
+			wantSubset:   true,
+			wantSuperset: true,
+		},
+		{
+			name:         "allset zero",
+			initial:      allset,
+			other:        zero,
+			wantSuperset: true,
+		},
+		{
+			name:           "equal",
+			initial:        zero | 0b0101 | T(1)<<(size-1),
+			other:          zero | 0b0101 | T(1)<<(size-1),
+			wantEqual:      true,
+			wantIntersects: true,
+			wantSubset:     true,
+			wantSuperset:   true,
+		},
+		{
+			name:           "subset",
+			initial:        zero | 0b0001,
+			other:          zero | 0b0101,
+			wantIntersects: true,
+			wantSubset:     true,
+		},
+		{
+			name:           "superset",
+			initial:        zero | 0b0101 | T(1)<<(size-1),
+			other:          zero | 0b0100,
+			wantIntersects: true,
+			wantSuperset:   true,
+		},
+		{
+			name:           "overlapping",
+			initial:        zero | 0b0101,
+			other:          zero | 0b0011,
+			wantIntersects: true,
+		},
+		{
+			name:    "disjoint",
+			initial: zero | 0b0101,
+			other:   zero | 0b1010,
+		},
+	}
+	t.Run(fmt.Sprintf("%T", zero), func(t *testing.T) {
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				var f TP = &tt.initial
+				if got := f.Equal(tt.other); got != tt.wantEqual {
+					t.Errorf("Equal() = %v, want = %v", got, tt.wantEqual)
+				}
+				if got := f.Intersects(tt.other); got != tt.wantIntersects {
+					t.Errorf("Intersects() = %v, want = %v", got, tt.wantIntersects)
+				}
+				if got := f.IsSubsetOf(tt.other); got != tt.wantSubset {
+					t.Errorf("IsSubsetOf() = %v, want = %v", got, tt.wantSubset)
+				}
+				if got := f.IsSupersetOf(tt.other); got != tt.wantSuperset {
+					t.Errorf("IsSupersetOf() = %v, want = %v", got, tt.wantSuperset)
+				}
+			})
+		}
+	})
+}
+
+func TestBitFlags_SetRelations(t *testing.T) {
+	helperRunTestSetRelations[BitFlags8](t)
+	helperRunTestSetRelations[BitFlags16](t)
+	helperRunTestSetRelations[BitFlags32](t)
+	helperRunTestSetRelations[BitFlags64](t)
+}
@@ -0,0 +1,99 @@
+package flagged
+
+// checkBitIndex returns an [IndexError] if idx is out of range, instead of
+// panicking like validateBitIndex.
+func checkBitIndex(size int, idx BitIndex) error {
+	if idx >= 0 && idx < size {
+		return nil
+	}
+	return IndexError{Idx: idx, Size: size}
+}
+
+func tryIs[T bitFlags](f T, size int, idx BitIndex) (set bool, err error) {
+	if err := checkBitIndex(size, idx); err != nil {
+		return false, err
+	}
+	return isUint(f, idx), nil
+}
+
+func trySet[T bitFlags](f *T, size int, idx BitIndex, new bool) (old bool, err error) {
+	if err := checkBitIndex(size, idx); err != nil {
+		return false, err
+	}
+	return set(f, size, idx, new), nil
+}
+
+func tryToggle[T bitFlags](f *T, size int, idx BitIndex) (new bool, err error) {
+	if err := checkBitIndex(size, idx); err != nil {
+		return false, err
+	}
+	return toggle(f, size, idx), nil
+}
+
+// TryIs is like [BitFlags8.Is], but returns an [IndexError] instead of panicking.
+func (f BitFlags8) TryIs(idx BitIndex) (set bool, err error) { return tryIs(f, 8, idx) }
+
+// TrySet is like [BitFlags8.Set], but returns an [IndexError] instead of panicking.
+func (f *BitFlags8) TrySet(idx BitIndex) (old bool, err error) { return trySet(f, 8, idx, true) }
+
+// TryReset is like [BitFlags8.Reset], but returns an [IndexError] instead of panicking.
+func (f *BitFlags8) TryReset(idx BitIndex) (old bool, err error) { return trySet(f, 8, idx, false) }
+
+// TrySetTo is like [BitFlags8.SetTo], but returns an [IndexError] instead of panicking.
+func (f *BitFlags8) TrySetTo(idx BitIndex, new bool) (old bool, err error) {
+	return trySet(f, 8, idx, new)
+}
+
+// TryToggle is like [BitFlags8.Toggle], but returns an [IndexError] instead of panicking.
+func (f *BitFlags8) TryToggle(idx BitIndex) (new bool, err error) { return tryToggle(f, 8, idx) }
+
+// TryIs is like [BitFlags16.Is], but returns an [IndexError] instead of panicking.
+func (f BitFlags16) TryIs(idx BitIndex) (set bool, err error) { return tryIs(f, 16, idx) }
+
+// TrySet is like [BitFlags16.Set], but returns an [IndexError] instead of panicking.
+func (f *BitFlags16) TrySet(idx BitIndex) (old bool, err error) { return trySet(f, 16, idx, true) }
+
+// TryReset is like [BitFlags16.Reset], but returns an [IndexError] instead of panicking.
+func (f *BitFlags16) TryReset(idx BitIndex) (old bool, err error) { return trySet(f, 16, idx, false) }
+
+// TrySetTo is like [BitFlags16.SetTo], but returns an [IndexError] instead of panicking.
+func (f *BitFlags16) TrySetTo(idx BitIndex, new bool) (old bool, err error) {
+	return trySet(f, 16, idx, new)
+}
+
+// TryToggle is like [BitFlags16.Toggle], but returns an [IndexError] instead of panicking.
+func (f *BitFlags16) TryToggle(idx BitIndex) (new bool, err error) { return tryToggle(f, 16, idx) }
+
+// TryIs is like [BitFlags32.Is], but returns an [IndexError] instead of panicking.
+func (f BitFlags32) TryIs(idx BitIndex) (set bool, err error) { return tryIs(f, 32, idx) }
+
+// TrySet is like [BitFlags32.Set], but returns an [IndexError] instead of panicking.
+func (f *BitFlags32) TrySet(idx BitIndex) (old bool, err error) { return trySet(f, 32, idx, true) }
+
+// TryReset is like [BitFlags32.Reset], but returns an [IndexError] instead of panicking.
+func (f *BitFlags32) TryReset(idx BitIndex) (old bool, err error) { return trySet(f, 32, idx, false) }
+
+// TrySetTo is like [BitFlags32.SetTo], but returns an [IndexError] instead of panicking.
+func (f *BitFlags32) TrySetTo(idx BitIndex, new bool) (old bool, err error) {
+	return trySet(f, 32, idx, new)
+}
+
+// TryToggle is like [BitFlags32.Toggle], but returns an [IndexError] instead of panicking.
+func (f *BitFlags32) TryToggle(idx BitIndex) (new bool, err error) { return tryToggle(f, 32, idx) }
+
+// TryIs is like [BitFlags64.Is], but returns an [IndexError] instead of panicking.
+func (f BitFlags64) TryIs(idx BitIndex) (set bool, err error) { return tryIs(f, 64, idx) }
+
+// TrySet is like [BitFlags64.Set], but returns an [IndexError] instead of panicking.
+func (f *BitFlags64) TrySet(idx BitIndex) (old bool, err error) { return trySet(f, 64, idx, true) }
+
+// TryReset is like [BitFlags64.Reset], but returns an [IndexError] instead of panicking.
+func (f *BitFlags64) TryReset(idx BitIndex) (old bool, err error) { return trySet(f, 64, idx, false) }
+
+// TrySetTo is like [BitFlags64.SetTo], but returns an [IndexError] instead of panicking.
+func (f *BitFlags64) TrySetTo(idx BitIndex, new bool) (old bool, err error) {
+	return trySet(f, 64, idx, new)
+}
+
+// TryToggle is like [BitFlags64.Toggle], but returns an [IndexError] instead of panicking.
+func (f *BitFlags64) TryToggle(idx BitIndex) (new bool, err error) { return tryToggle(f, 64, idx) }
@@ -0,0 +1,104 @@
+package flagged
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func helperRunTestTry[T bitFlags, TP interface {
+	ptrBitFlags[T]
+	TryIs(idx BitIndex) (bool, error)
+	TrySet(idx BitIndex) (bool, error)
+	TryReset(idx BitIndex) (bool, error)
+	TrySetTo(idx BitIndex, new bool) (bool, error)
+	TryToggle(idx BitIndex) (bool, error)
+}](t *testing.T) {
+	var (
+		zero   T
+		allset = ^zero
+		size   = TP(&zero).Size()
+	)
+	type testCase struct {
+		name     string
+		initial  T
+		bitIndex BitIndex
+		wantErr  bool
+	}
+	tests := []testCase{
+		{
+			name:     "zero - range start",
+			initial:  zero,
+			bitIndex: 0,
+		},
+		{
+			name:     "allset - range end",
+			initial:  allset,
+			bitIndex: size - 1,
+		},
+		{
+			name:     "out of range - negative",
+			initial:  zero | 0b0101,
+			bitIndex: -1,
+			wantErr:  true,
+		},
+		{
+			name:     "out of range - size",
+			initial:  zero | 0b0101,
+			bitIndex: size,
+			wantErr:  true,
+		},
+	}
+	t.Run(fmt.Sprintf("%T", zero), func(t *testing.T) {
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				for _, op := range []struct {
+					name string
+					try  func(f TP, idx BitIndex) (bool, error)
+					fn   func(f TP, idx BitIndex) bool
+				}{
+					{"TryIs", TP.TryIs, TP.Is},
+					{"TrySet", TP.TrySet, TP.Set},
+					{"TryReset", TP.TryReset, TP.Reset},
+					{"TrySetTo", func(f TP, idx BitIndex) (bool, error) { return f.TrySetTo(idx, true) },
+						func(f TP, idx BitIndex) bool { return f.SetTo(idx, true) }},
+					{"TryToggle", TP.TryToggle, TP.Toggle},
+				} {
+					f := tt.initial
+					got, err := op.try(&f, tt.bitIndex)
+					if tt.wantErr {
+						var ie IndexError
+						if !errors.As(err, &ie) || ie.Idx != tt.bitIndex || ie.Size != size {
+							t.Errorf("%s() error = %v, want IndexError{%v, %v}", op.name, err, tt.bitIndex, size)
+						}
+						if f != tt.initial {
+							t.Errorf("%s() changed the value on error", op.name)
+						}
+						continue
+					}
+
+					want := tt.initial
+					wantV := op.fn(&want, tt.bitIndex)
+					if err != nil || got != wantV || f != want {
+						t.Errorf("%s() = %v, %v, %v, want = %v, nil, %v",
+							op.name, got, err, TP(&f).String(), wantV, TP(&want).String())
+					}
+				}
+			})
+		}
+	})
+}
+
+func TestBitFlags_Try(t *testing.T) {
+	helperRunTestTry[BitFlags8](t)
+	helperRunTestTry[BitFlags16](t)
+	helperRunTestTry[BitFlags32](t)
+	helperRunTestTry[BitFlags64](t)
+}
+
+func TestIndexError_Error(t *testing.T) {
+	err := IndexError{Idx: 16, Size: 16}
+	if got, want := err.Error(), "index 16 out of range [0..15]"; got != want {
+		t.Errorf("Error() = %q, want = %q", got, want)
+	}
+}
@@ -0,0 +1,74 @@
+package flagged
+
+// The Unchecked methods skip the validation of the bit index, for inner
+// loops where the caller guarantees it's in the allowed range [0, Size-1].
+// Passing an invalid index to them doesn't panic with an [IndexError], and
+// the result is unspecified.
+
+// IsUnchecked is like [BitFlags8.Is], but doesn't validate idx.
+func (f BitFlags8) IsUnchecked(idx BitIndex) (set bool) { return isUint(f, idx) }
+
+// SetUnchecked is like [BitFlags8.Set], but doesn't validate idx.
+func (f *BitFlags8) SetUnchecked(idx BitIndex) (old bool) { return setUnchecked(f, idx, true) }
+
+// ResetUnchecked is like [BitFlags8.Reset], but doesn't validate idx.
+func (f *BitFlags8) ResetUnchecked(idx BitIndex) (old bool) { return setUnchecked(f, idx, false) }
+
+// SetToUnchecked is like [BitFlags8.SetTo], but doesn't validate idx.
+func (f *BitFlags8) SetToUnchecked(idx BitIndex, new bool) (old bool) {
+	return setUnchecked(f, idx, new)
+}
+
+// ToggleUnchecked is like [BitFlags8.Toggle], but doesn't validate idx.
+func (f *BitFlags8) ToggleUnchecked(idx BitIndex) (new bool) { return toggleUnchecked(f, idx) }
+
+// IsUnchecked is like [BitFlags16.Is], but doesn't validate idx.
+func (f BitFlags16) IsUnchecked(idx BitIndex) (set bool) { return isUint(f, idx) }
+
+// SetUnchecked is like [BitFlags16.Set], but doesn't validate idx.
+func (f *BitFlags16) SetUnchecked(idx BitIndex) (old bool) { return setUnchecked(f, idx, true) }
+
+// ResetUnchecked is like [BitFlags16.Reset], but doesn't validate idx.
+func (f *BitFlags16) ResetUnchecked(idx BitIndex) (old bool) { return setUnchecked(f, idx, false) }
+
+// SetToUnchecked is like [BitFlags16.SetTo], but doesn't validate idx.
+func (f *BitFlags16) SetToUnchecked(idx BitIndex, new bool) (old bool) {
+	return setUnchecked(f, idx, new)
+}
+
+// ToggleUnchecked is like [BitFlags16.Toggle], but doesn't validate idx.
+func (f *BitFlags16) ToggleUnchecked(idx BitIndex) (new bool) { return toggleUnchecked(f, idx) }
+
+// IsUnchecked is like [BitFlags32.Is], but doesn't validate idx.
+func (f BitFlags32) IsUnchecked(idx BitIndex) (set bool) { return isUint(f, idx) }
+
+// SetUnchecked is like [BitFlags32.Set], but doesn't validate idx.
+func (f *BitFlags32) SetUnchecked(idx BitIndex) (old bool) { return setUnchecked(f, idx, true) }
+
+// ResetUnchecked is like [BitFlags32.Reset], but doesn't validate idx.
+func (f *BitFlags32) ResetUnchecked(idx BitIndex) (old bool) { return setUnchecked(f, idx, false) }
+
+// SetToUnchecked is like [BitFlags32.SetTo], but doesn't validate idx.
+func (f *BitFlags32) SetToUnchecked(idx BitIndex, new bool) (old bool) {
+	return setUnchecked(f, idx, new)
+}
+
+// ToggleUnchecked is like [BitFlags32.Toggle], but doesn't validate idx.
+func (f *BitFlags32) ToggleUnchecked(idx BitIndex) (new bool) { return toggleUnchecked(f, idx) }
+
+// IsUnchecked is like [BitFlags64.Is], but doesn't validate idx.
+func (f BitFlags64) IsUnchecked(idx BitIndex) (set bool) { return isUint(f, idx) }
+
+// SetUnchecked is like [BitFlags64.Set], but doesn't validate idx.
+func (f *BitFlags64) SetUnchecked(idx BitIndex) (old bool) { return setUnchecked(f, idx, true) }
+
+// ResetUnchecked is like [BitFlags64.Reset], but doesn't validate idx.
+func (f *BitFlags64) ResetUnchecked(idx BitIndex) (old bool) { return setUnchecked(f, idx, false) }
+
+// SetToUnchecked is like [BitFlags64.SetTo], but doesn't validate idx.
+func (f *BitFlags64) SetToUnchecked(idx BitIndex, new bool) (old bool) {
+	return setUnchecked(f, idx, new)
+}
+
+// ToggleUnchecked is like [BitFlags64.Toggle], but doesn't validate idx.
+func (f *BitFlags64) ToggleUnchecked(idx BitIndex) (new bool) { return toggleUnchecked(f, idx) }
@@ -0,0 +1,77 @@
+package flagged
+
+import (
+	"fmt"
+	"testing"
+)
+
+func helperRunTestUnchecked[T bitFlags, TP interface {
+	ptrBitFlags[T]
+	IsUnchecked(idx BitIndex) bool
+	SetUnchecked(idx BitIndex) bool
+	ResetUnchecked(idx BitIndex) bool
+	SetToUnchecked(idx BitIndex, new bool) bool
+	ToggleUnchecked(idx BitIndex) bool
+}](t *testing.T) {
+	var (
+		zero   T
+		allset = ^zero
+		size   = TP(&zero).Size()
+	)
+	tests := []struct {
+		name    string
+		initial T
+	}{
+		{
+			name:    "zero",
+			initial: zero,
+		},
+		{
+			name:    "allset",
+			initial: allset,
+		},
+		{
+			name:    "partial",
+			initial: zero | 0b0101 | T(1)<<(size-1),
+		},
+	}
+	t.Run(fmt.Sprintf("%T", zero), func(t *testing.T) {
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				// Each unchecked method acts like its checked counterpart
+				// for every valid index.
+				for idx := range size {
+					for _, op := range []struct {
+						name      string
+						unchecked func(f TP, idx BitIndex) bool
+						checked   func(f TP, idx BitIndex) bool
+					}{
+						{"IsUnchecked", TP.IsUnchecked, TP.Is},
+						{"SetUnchecked", TP.SetUnchecked, TP.Set},
+						{"ResetUnchecked", TP.ResetUnchecked, TP.Reset},
+						{
+							"SetToUnchecked",
+							func(f TP, idx BitIndex) bool { return f.SetToUnchecked(idx, true) },
+							func(f TP, idx BitIndex) bool { return f.SetTo(idx, true) },
+						},
+						{"ToggleUnchecked", TP.ToggleUnchecked, TP.Toggle},
+					} {
+						got, want := tt.initial, tt.initial
+						gotV, wantV := op.unchecked(&got, idx), op.checked(&want, idx)
+						if gotV != wantV || got != want {
+							t.Errorf("%s(%v) = %v, %v, want = %v, %v",
+								op.name, idx, gotV, TP(&got).String(), wantV, TP(&want).String())
+						}
+					}
+				}
+			})
+		}
+	})
+}
+
+func TestBitFlags_Unchecked(t *testing.T) {
+	helperRunTestUnchecked[BitFlags8](t)
+	helperRunTestUnchecked[BitFlags16](t)
+	helperRunTestUnchecked[BitFlags32](t)
+	helperRunTestUnchecked[BitFlags64](t)
+}
@@ -0,0 +1,10 @@
+//go:build !flagged_unsafe
+
+package flagged
+
+func validateBitIndex(size int, idx BitIndex) {
+	if idx >= 0 && idx < size {
+		return
+	}
+	validateBitIndexSlow(size, idx)
+}
@@ -0,0 +1,111 @@
+//go:build !flagged_unsafe
+
+package flagged
+
+import (
+	"fmt"
+	"testing"
+)
+
+// validatesIndexes reports whether out of range bit indexes panic, which
+// they don't with the flagged_unsafe tag.
+const validatesIndexes = true
+
+func helperRunTestOfOrder[T bitFlags, TP ptrBitFlags[T]](t *testing.T) {
+	var (
+		zero T
+		size = TP(&zero).Size()
+		f    = zero | 0b01
+	)
+	tests := []struct {
+		name string
+		fn   func(idx ...BitIndex) bool
+		idx  []BitIndex
+	}{
+		// The answer is known at the first index, but the invalid index
+		// still panics, before or after it.
+		{"AnyOf - valid first", TP(&f).AnyOf, []BitIndex{0, size}},
+		{"AnyOf - invalid first", TP(&f).AnyOf, []BitIndex{size, 0}},
+		{"AllOf - valid first", TP(&f).AllOf, []BitIndex{1, -1}},
+		{"AllOf - invalid first", TP(&f).AllOf, []BitIndex{-1, 1}},
+	}
+	t.Run(fmt.Sprintf("%T", zero), func(t *testing.T) {
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				defer func() {
+					if _, ok := recover().(IndexError); !ok {
+						t.Errorf("%v didn't panic with an IndexError", tt.idx)
+					}
+				}()
+				tt.fn(tt.idx...)
+			})
+		}
+	})
+}
+
+func TestBitFlags_OfOrder(t *testing.T) {
+	helperRunTestOfOrder[BitFlags8](t)
+	helperRunTestOfOrder[BitFlags16](t)
+	helperRunTestOfOrder[BitFlags32](t)
+	helperRunTestOfOrder[BitFlags64](t)
+}
+
+func Test_validateBitIndex_panic(t *testing.T) {
+	tests := []struct {
+		name   string
+		size   int
+		idx    BitIndex
+		panicV any
+		msg    string
+	}{
+		{
+			name:   "no panic",
+			size:   8,
+			idx:    7,
+			panicV: nil,
+		},
+		{
+			name:   "positive panic - small idx",
+			size:   16,
+			idx:    16,
+			panicV: IndexError{Idx: 16, Size: 16},
+			msg:    "index 16 out of range [0..15]",
+		},
+		{
+			name:   "negative panic - small idx",
+			size:   64,
+			idx:    -99,
+			panicV: IndexError{Idx: -99, Size: 64},
+			msg:    "index -99 out of range [0..63]",
+		},
+		{
+			name:   "positive panic - big idx",
+			size:   32,
+			idx:    100,
+			panicV: IndexError{Idx: 100, Size: 32},
+			msg:    "index out of range [0..31]",
+		},
+		{
+			name:   "negative panic - big idx",
+			size:   64,
+			idx:    -9999,
+			panicV: IndexError{Idx: -9999, Size: 64},
+			msg:    "index out of range [0..63]",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer func() {
+				v := recover()
+				if v != tt.panicV {
+					t.Errorf("got panicV: %v; want: %v", v, tt.panicV)
+				}
+				if err, ok := v.(error); ok && err.Error() != tt.msg {
+					t.Errorf("got panic message: %q; want: %q", err.Error(), tt.msg)
+				}
+			}()
+
+			validateBitIndex(tt.size, tt.idx)
+		})
+	}
+}
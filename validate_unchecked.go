@@ -0,0 +1,9 @@
+//go:build flagged_unsafe
+
+package flagged
+
+// validateBitIndex doesn't validate idx when built with the flagged_unsafe
+// tag, for hot loops where the indexes are known to be valid, like
+// constants. Invalid indexes then don't panic with an [IndexError], and
+// the result of passing them is unspecified.
+func validateBitIndex(size int, idx BitIndex) {}
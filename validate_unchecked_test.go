@@ -0,0 +1,30 @@
+//go:build flagged_unsafe
+
+package flagged
+
+import "testing"
+
+// validatesIndexes reports whether out of range bit indexes panic, which
+// they don't with the flagged_unsafe tag.
+const validatesIndexes = false
+
+func Test_validateBitIndex_unchecked(t *testing.T) {
+	defer func() {
+		if r := recover(); r != nil {
+			t.Errorf("validateBitIndex() panicked with %v", r)
+		}
+	}()
+	validateBitIndex(8, 8)
+	validateBitIndex(64, -1)
+}
+
+func TestFromBools_unchecked(t *testing.T) {
+	// The length of the slice is checked even without validating the
+	// indexes, so it doesn't get truncated.
+	defer func() {
+		if _, ok := recover().(IndexError); !ok {
+			t.Error("FromBools(9 bools) didn't panic with an IndexError")
+		}
+	}()
+	FromBools[BitFlags8](make([]bool, 9))
+}
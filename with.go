@@ -0,0 +1,71 @@
+package flagged
+
+func withSetTo[T bitFlags](f T, size int, idx BitIndex, new bool) T {
+	set(&f, size, idx, new)
+	return f
+}
+
+func withToggled[T bitFlags](f T, size int, idx BitIndex) T {
+	toggle(&f, size, idx)
+	return f
+}
+
+// WithSet returns a copy of f with the bit at index idx set to true,
+// without changing f.
+// It panics if idx is out of the allowed range [0, 7].
+func (f BitFlags8) WithSet(idx BitIndex) BitFlags8 { return withSetTo(f, 8, idx, true) }
+
+// WithReset returns a copy of f with the bit at index idx set to false,
+// without changing f.
+// It panics if idx is out of the allowed range [0, 7].
+func (f BitFlags8) WithReset(idx BitIndex) BitFlags8 { return withSetTo(f, 8, idx, false) }
+
+// WithToggled returns a copy of f with the bit at index idx toggled,
+// without changing f.
+// It panics if idx is out of the allowed range [0, 7].
+func (f BitFlags8) WithToggled(idx BitIndex) BitFlags8 { return withToggled(f, 8, idx) }
+
+// WithSet returns a copy of f with the bit at index idx set to true,
+// without changing f.
+// It panics if idx is out of the allowed range [0, 15].
+func (f BitFlags16) WithSet(idx BitIndex) BitFlags16 { return withSetTo(f, 16, idx, true) }
+
+// WithReset returns a copy of f with the bit at index idx set to false,
+// without changing f.
+// It panics if idx is out of the allowed range [0, 15].
+func (f BitFlags16) WithReset(idx BitIndex) BitFlags16 { return withSetTo(f, 16, idx, false) }
+
+// WithToggled returns a copy of f with the bit at index idx toggled,
+// without changing f.
+// It panics if idx is out of the allowed range [0, 15].
+func (f BitFlags16) WithToggled(idx BitIndex) BitFlags16 { return withToggled(f, 16, idx) }
+
+// WithSet returns a copy of f with the bit at index idx set to true,
+// without changing f.
+// It panics if idx is out of the allowed range [0, 31].
+func (f BitFlags32) WithSet(idx BitIndex) BitFlags32 { return withSetTo(f, 32, idx, true) }
+
+// WithReset returns a copy of f with the bit at index idx set to false,
+// without changing f.
+// It panics if idx is out of the allowed range [0, 31].
+func (f BitFlags32) WithReset(idx BitIndex) BitFlags32 { return withSetTo(f, 32, idx, false) }
+
+// WithToggled returns a copy of f with the bit at index idx toggled,
+// without changing f.
+// It panics if idx is out of the allowed range [0, 31].
+func (f BitFlags32) WithToggled(idx BitIndex) BitFlags32 { return withToggled(f, 32, idx) }
+
+// WithSet returns a copy of f with the bit at index idx set to true,
+// without changing f.
+// It panics if idx is out of the allowed range [0, 63].
+func (f BitFlags64) WithSet(idx BitIndex) BitFlags64 { return withSetTo(f, 64, idx, true) }
+
+// WithReset returns a copy of f with the bit at index idx set to false,
+// without changing f.
+// It panics if idx is out of the allowed range [0, 63].
+func (f BitFlags64) WithReset(idx BitIndex) BitFlags64 { return withSetTo(f, 64, idx, false) }
+
+// WithToggled returns a copy of f with the bit at index idx toggled,
+// without changing f.
+// It panics if idx is out of the allowed range [0, 63].
+func (f BitFlags64) WithToggled(idx BitIndex) BitFlags64 { return withToggled(f, 64, idx) }
@@ -0,0 +1,90 @@
+package flagged
+
+import (
+	"fmt"
+	"testing"
+)
+
+func helperRunTestWith[T bitFlags, TP interface {
+	ptrBitFlags[T]
+	WithSet(idx BitIndex) T
+	WithReset(idx BitIndex) T
+	WithToggled(idx BitIndex) T
+}](t *testing.T) {
+	var (
+		zero   T
+		allset = ^zero
+		size   = TP(&zero).Size()
+	)
+	type testCase struct {
+		name     string
+		initial  T
+		bitIndex BitIndex
+		panics   bool
+	}
+	tests := []testCase{
+		{
+			name:     "zero - range start",
+			initial:  zero,
+			bitIndex: 0,
+		},
+		{
+			name:     "allset - range end",
+			initial:  allset,
+			bitIndex: size - 1,
+		},
+		{
+			name:     "partial",
+			initial:  zero | 0b0101,
+			bitIndex: 1,
+		},
+		{
+			name:     "out of range",
+			initial:  zero | 0b0101,
+			bitIndex: size,
+			panics:   true,
+		},
+	}
+	t.Run(fmt.Sprintf("%T", zero), func(t *testing.T) {
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				skipUnvalidated(t, tt.panics)
+				for _, op := range []struct {
+					name    string
+					fn      func(f TP, idx BitIndex) T
+					inPlace func(f TP, idx BitIndex)
+				}{
+					{"WithSet", TP.WithSet, func(f TP, idx BitIndex) { f.Set(idx) }},
+					{"WithReset", TP.WithReset, func(f TP, idx BitIndex) { f.Reset(idx) }},
+					{"WithToggled", TP.WithToggled, func(f TP, idx BitIndex) { f.Toggle(idx) }},
+				} {
+					f := tt.initial
+					func() {
+						defer func() {
+							if r := recover(); (r != nil) != tt.panics {
+								t.Errorf("%s() panic = %v, want panic = %v", op.name, r, tt.panics)
+							}
+						}()
+
+						got := op.fn(&f, tt.bitIndex)
+						want := tt.initial
+						op.inPlace(&want, tt.bitIndex)
+						if got != want {
+							t.Errorf("%s() = %v, want = %v", op.name, TP(&got).String(), TP(&want).String())
+						}
+					}()
+					if f != tt.initial {
+						t.Errorf("%s() changed the receiver to %v", op.name, TP(&f).String())
+					}
+				}
+			})
+		}
+	})
+}
+
+func TestBitFlags_With(t *testing.T) {
+	helperRunTestWith[BitFlags8](t)
+	helperRunTestWith[BitFlags16](t)
+	helperRunTestWith[BitFlags32](t)
+	helperRunTestWith[BitFlags64](t)
+}